@@ -0,0 +1,81 @@
+package ber
+
+import (
+	"github.com/yafred/asn1-go/types"
+)
+
+// maximum number of contents bytes of a primitive string in CER (X.690 9.2)
+const cerSegmentSize = 1000
+
+// WriteOctetStringTLV encodes tag, length and value of an OCTET STRING and return length of encoded data
+// tag is given in its primitive form, with CER a value over 1000 bytes is encoded in constructed form with 1000 bytes segments
+func (w *Writer) WriteOctetStringTLV(tag []byte, value []byte) int {
+	if w.rules != CER || len(value) <= cerSegmentSize {
+		nBytes := w.WriteOctetString(value)
+		nBytes += w.WriteLength64(uint64(nBytes))
+		return nBytes + w.WriteOctetString(tag)
+	}
+
+	nBytes := w.WriteEndOfContents()
+
+	// segments are written backwards: last segment first
+	for end := len(value); end > 0; {
+		begin := ((end - 1) / cerSegmentSize) * cerSegmentSize
+		nSegmentBytes := w.WriteOctetString(value[begin:end])
+		nSegmentBytes += w.WriteLength64(uint64(nSegmentBytes))
+		nBytes += nSegmentBytes + w.writeByte(octetStringSegmentTag)
+		end = begin
+	}
+
+	nBytes += w.WriteIndefiniteLength()
+	return nBytes + w.writeConstructedTag(tag)
+}
+
+// WriteRestrictedCharacterStringTLV encodes tag, length and value of a restricted character string and return length of encoded data
+// tag is given in its primitive form, with CER a value over 1000 bytes is encoded in constructed form with 1000 bytes segments
+func (w *Writer) WriteRestrictedCharacterStringTLV(tag []byte, value string) int {
+	return w.WriteOctetStringTLV(tag, []byte(value))
+}
+
+// WriteBitStringTLV encodes tag, length and value of a BIT STRING and return length of encoded data
+// tag is given in its primitive form, with CER a value over 1000 bytes is encoded in constructed form with 1000 bytes segments
+func (w *Writer) WriteBitStringTLV(tag []byte, value types.BitString) int {
+	length := value.ValidLength()
+	nValueBytes := (length + 7) / 8
+
+	// first byte of each segment is the number of padding bits
+	const nSegmentValueBytes = cerSegmentSize - 1
+
+	if w.rules != CER || nValueBytes <= nSegmentValueBytes {
+		nBytes := w.WriteBitString(value)
+		nBytes += w.WriteLength64(uint64(nBytes))
+		return nBytes + w.WriteOctetString(tag)
+	}
+
+	nBytes := w.WriteEndOfContents()
+
+	// segments are written backwards: last segment first, only the last segment has padding bits
+	for end := nValueBytes; end > 0; {
+		begin := ((end - 1) / nSegmentValueBytes) * nSegmentValueBytes
+		segment := types.BitString{Bytes: value.Bytes[begin:end], Length: 8 * (end - begin)}
+		if end == nValueBytes {
+			segment.Length = length - 8*begin
+		}
+		nSegmentBytes := w.WriteBitString(segment)
+		nSegmentBytes += w.WriteLength64(uint64(nSegmentBytes))
+		nBytes += nSegmentBytes + w.writeByte(bitStringSegmentTag)
+		end = begin
+	}
+
+	nBytes += w.WriteIndefiniteLength()
+	return nBytes + w.writeConstructedTag(tag)
+}
+
+// writeConstructedTag writes a tag given in its primitive form with the constructed bit set and return length of encoded data
+func (w *Writer) writeConstructedTag(tag []byte) int {
+	nBytes := w.WriteOctetString(tag)
+	if nBytes > 0 {
+		w.dataBuffer[len(w.dataBuffer)-w.dataSize] |= 0x20
+	}
+	return nBytes
+}
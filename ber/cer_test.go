@@ -0,0 +1,162 @@
+package ber
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestCERWriteConstructedLength(t *testing.T) {
+	writer := NewCERWriter(3)
+
+	nBytes := writer.WriteBoolean(true)
+	nBytes += writer.WriteLength64(uint64(nBytes))
+	nBytes += writer.WriteOctetString([]byte{0x01})
+
+	nBytes += writer.WriteConstructedLength(nBytes)
+	nBytes += writer.WriteOctetString([]byte{0x30})
+
+	expectedBuffer := [...]byte{0x30, 0x80, 0x01, 0x01, 0xff, 0x00, 0x00}
+	if nBytes != len(expectedBuffer) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestBERWriteConstructedLength(t *testing.T) {
+	writer := NewWriter(10)
+
+	nBytes := writer.WriteBoolean(true)
+	nBytes += writer.WriteLength64(uint64(nBytes))
+	nBytes += writer.WriteOctetString([]byte{0x01})
+
+	nBytes += writer.WriteConstructedLength(nBytes)
+	nBytes += writer.WriteOctetString([]byte{0x30})
+
+	expectedBuffer := [...]byte{0x30, 0x03, 0x01, 0x01, 0xff}
+	if nBytes != len(expectedBuffer) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCERWriteOctetStringTLV(t *testing.T) {
+	value := make([]byte, 2500)
+	for i := range value {
+		value[i] = byte(i)
+	}
+
+	writer := NewCERWriter(10)
+
+	nBytes := writer.WriteOctetStringTLV([]byte{0x04}, value)
+
+	encoded := writer.GetDataBuffer()
+	if nBytes != len(encoded) || nBytes != 2+3*4+2500+2 {
+		t.Fatal("Wrong")
+	}
+
+	expectedHeader := [...]byte{0x24, 0x80, 0x04, 0x82, 0x03, 0xe8, 0x00, 0x01}
+	if false == bytes.Equal(encoded[:len(expectedHeader)], expectedHeader[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	reader := NewReader(bytes.NewReader(encoded))
+	decoded, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(decoded, value) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCERWriteShortOctetStringTLV(t *testing.T) {
+	writer := NewCERWriter(10)
+
+	nBytes := writer.WriteRestrictedCharacterStringTLV([]byte{0x1a}, "Jones")
+
+	expectedBuffer := [...]byte{0x1a, 0x05, 0x4a, 0x6f, 0x6e, 0x65, 0x73}
+	if nBytes != len(expectedBuffer) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteOctetStringTLV(t *testing.T) {
+	writer := NewWriter(10)
+
+	nBytes := writer.WriteOctetStringTLV([]byte{0x80}, make([]byte, 1500))
+
+	if nBytes != 1+3+1500 {
+		t.Fatal("Wrong")
+	}
+
+	expectedHeader := [...]byte{0x80, 0x82, 0x05, 0xdc}
+	if false == bytes.Equal(writer.GetDataBuffer()[:4], expectedHeader[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCERWriteBitStringTLV(t *testing.T) {
+	value := types.BitString{Bytes: make([]byte, 1000), Length: 7995}
+	for i := range value.Bytes {
+		value.Bytes[i] = 0xff
+	}
+
+	writer := NewCERWriter(10)
+
+	nBytes := writer.WriteBitStringTLV([]byte{0x03}, value)
+
+	encoded := writer.GetDataBuffer()
+	if nBytes != len(encoded) || nBytes != 2+(4+1000)+(2+2)+2 {
+		t.Fatal("Wrong")
+	}
+
+	expectedHeader := [...]byte{0x23, 0x80, 0x03, 0x82, 0x03, 0xe8, 0x00, 0xff}
+	if false == bytes.Equal(encoded[:len(expectedHeader)], expectedHeader[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	expectedTrailer := [...]byte{0x03, 0x02, 0x05, 0xe0, 0x00, 0x00}
+	if false == bytes.Equal(encoded[len(encoded)-len(expectedTrailer):], expectedTrailer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	reader := NewReader(bytes.NewReader(encoded))
+	decoded, err := reader.ReadConstructedBitString(readTagAndLength(t, reader))
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if decoded.Length != 7995 || decoded.Get(7994) == false {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCERWriterSortSetOfComponents(t *testing.T) {
+	writer := NewCERWriter(10)
+
+	nBytes := writer.WriteOctetString([]byte{0x30, 0x80, 0x01, 0x01, 0x00, 0x00, 0x00})
+	nBytes += writer.WriteOctetString([]byte{0x30, 0x80, 0x01, 0x01, 0xff, 0x00, 0x00})
+	nBytes += writer.WriteOctetString([]byte{0x30, 0x80, 0x00, 0x00})
+
+	err := writer.SortSetOfComponents(nBytes)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{
+		0x30, 0x80, 0x00, 0x00,
+		0x30, 0x80, 0x01, 0x01, 0x00, 0x00, 0x00,
+		0x30, 0x80, 0x01, 0x01, 0xff, 0x00, 0x00,
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,115 @@
+package ber
+
+import (
+	"errors"
+)
+
+// constructedValue is a constructed value being read
+type constructedValue struct {
+	// offset of the end of the contents, -1 if length is indefinite
+	end int64
+
+	// true when end-of-contents has been read (indefinite length)
+	endOfContentsRead bool
+}
+
+// OpenConstructed must be called after the tag and the length of a constructed value have been read
+// components are then read with NextComponent until it returns false, then CloseConstructed must be called
+func (r *Reader) OpenConstructed() error {
+	if !r.isConstructed() {
+		return errors.New("last read tag is not constructed")
+	}
+	return r.openConstructed(r.lengthValue)
+}
+
+// openConstructed starts reading the contents of a constructed value which has nBytes bytes (-1 if length is indefinite)
+func (r *Reader) openConstructed(nBytes int64) error {
+	value := constructedValue{end: -1}
+	if nBytes >= 0 {
+		value.end = r.offset + nBytes
+	}
+
+	if len(r.constructedValues) != 0 {
+		parent := r.constructedValues[len(r.constructedValues)-1]
+		if parent.end >= 0 && value.end > parent.end {
+			return errors.New("constructed value exceeds the length of the enclosing value")
+		}
+	}
+
+	r.constructedValues = append(r.constructedValues, value)
+	return nil
+}
+
+// NextComponent reads the tag of the next component of the innermost open constructed value
+// returns false if there are no more components (end-of-contents is read if length is indefinite)
+func (r *Reader) NextComponent() (bool, error) {
+	if len(r.constructedValues) == 0 {
+		return false, errors.New("no constructed value open")
+	}
+	value := &r.constructedValues[len(r.constructedValues)-1]
+
+	if value.end >= 0 {
+		if r.offset > value.end {
+			return false, errors.New("components overrun the length of constructed value")
+		}
+		if r.offset == value.end {
+			return false, nil
+		}
+		return true, r.ReadTag()
+	}
+
+	if value.endOfContentsRead {
+		return false, nil
+	}
+
+	err := r.ReadTag()
+	if err != nil {
+		return false, err
+	}
+
+	if r.tagLength == 1 && r.tagBuffer[0] == 0x00 {
+		err = r.readEndOfContentsLength()
+		if err != nil {
+			return false, err
+		}
+		value.endOfContentsRead = true
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// CloseConstructed closes the innermost open constructed value
+// raises an error if its length does not match the bytes read or if end-of-contents is not found (indefinite length)
+func (r *Reader) CloseConstructed() error {
+	if len(r.constructedValues) == 0 {
+		return errors.New("no constructed value open")
+	}
+	value := r.constructedValues[len(r.constructedValues)-1]
+	r.constructedValues = r.constructedValues[:len(r.constructedValues)-1]
+
+	if value.end >= 0 {
+		if r.offset != value.end {
+			return errors.New("length of constructed value does not match the bytes read")
+		}
+		return nil
+	}
+
+	if !value.endOfContentsRead {
+		endOfContents := make([]byte, 2)
+		err := r.read(endOfContents)
+		if err != nil {
+			return err
+		}
+		if endOfContents[0] != 0x00 || endOfContents[1] != 0x00 {
+			return errors.New("end-of-contents expected")
+		}
+	}
+
+	return nil
+}
+
+// GetOffset returns the number of bytes read so far
+func (r *Reader) GetOffset() int64 {
+	return r.offset
+}
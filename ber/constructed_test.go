@@ -0,0 +1,230 @@
+package ber
+
+import (
+	"bytes"
+	"testing"
+)
+
+func readIntegers(t *testing.T, reader *Reader) []int {
+	var values []int
+	for {
+		more, err := reader.NextComponent()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if !more {
+			return values
+		}
+		if false == reader.MatchTag([]byte{0x02}) {
+			t.Fatal("Wrong")
+		}
+		err = reader.ReadLength()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		value, err := reader.ReadInteger(reader.GetLengthValue())
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		values = append(values, value)
+	}
+}
+
+func TestReadConstructedDefinite(t *testing.T) {
+	in := bytes.NewReader([]byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02, 0x05, 0x00})
+
+	reader := NewReader(in)
+	readTagAndLength(t, reader)
+
+	err := reader.OpenConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	values := readIntegers(t, reader)
+	if len(values) != 2 || values[0] != 1 || values[1] != 2 {
+		t.Fatal("Wrong")
+	}
+
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if reader.GetOffset() != 8 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadConstructedIndefinite(t *testing.T) {
+	in := bytes.NewReader([]byte{
+		0x30, 0x80,
+		0x02, 0x01, 0x01,
+		0x30, 0x80, 0x02, 0x01, 0x02, 0x00, 0x00,
+		0x30, 0x03, 0x02, 0x01, 0x03,
+		0x00, 0x00,
+	})
+
+	reader := NewReader(in)
+	readTagAndLength(t, reader)
+
+	err := reader.OpenConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	var values []int
+	for i := 0; i < 3; i++ {
+		more, err := reader.NextComponent()
+		if err != nil || !more {
+			t.Fatal("Wrong:", err)
+		}
+		err = reader.ReadLength()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if reader.MatchTag([]byte{0x02}) {
+			value, _ := reader.ReadInteger(reader.GetLengthValue())
+			values = append(values, value)
+			continue
+		}
+		err = reader.OpenConstructed()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		values = append(values, readIntegers(t, reader)...)
+		err = reader.CloseConstructed()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+	}
+
+	more, err := reader.NextComponent()
+	if err != nil || more {
+		t.Fatal("Wrong:", err)
+	}
+
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCloseConstructedIndefinite(t *testing.T) {
+	in := bytes.NewReader([]byte{0x30, 0x80, 0x05, 0x00, 0x00, 0x00})
+
+	reader := NewReader(in)
+	readTagAndLength(t, reader)
+
+	err := reader.OpenConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	readTagAndLength(t, reader)
+
+	// end-of-contents read by CloseConstructed
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestCloseConstructedErrors(t *testing.T) {
+	// bytes left
+	in := bytes.NewReader([]byte{0x30, 0x04, 0x05, 0x00, 0x05, 0x00})
+
+	reader := NewReader(in)
+	readTagAndLength(t, reader)
+	reader.OpenConstructed()
+	readTagAndLength(t, reader)
+
+	err := reader.CloseConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// missing end-of-contents
+	in = bytes.NewReader([]byte{0x30, 0x80, 0x05, 0x00, 0x05, 0x00})
+
+	reader = NewReader(in)
+	readTagAndLength(t, reader)
+	reader.OpenConstructed()
+	readTagAndLength(t, reader)
+
+	err = reader.CloseConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// nothing open
+	err = reader.CloseConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestOpenConstructedErrors(t *testing.T) {
+	// primitive
+	in := bytes.NewReader([]byte{0x04, 0x00})
+
+	reader := NewReader(in)
+	readTagAndLength(t, reader)
+
+	err := reader.OpenConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// inner value longer than outer value
+	in = bytes.NewReader([]byte{0x30, 0x04, 0x30, 0x03, 0x05, 0x00, 0x05, 0x00})
+
+	reader = NewReader(in)
+	readTagAndLength(t, reader)
+	reader.OpenConstructed()
+	readTagAndLength(t, reader)
+
+	err = reader.OpenConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+}
+
+func TestReadConstructedIndefiniteInDefinite(t *testing.T) {
+	in := bytes.NewReader([]byte{0x30, 0x04, 0x30, 0x80, 0x00, 0x00})
+
+	reader := NewReader(in)
+	readTagAndLength(t, reader)
+	reader.OpenConstructed()
+	readTagAndLength(t, reader)
+
+	err := reader.OpenConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	more, err := reader.NextComponent()
+	if err != nil || more {
+		t.Fatal("Wrong:", err)
+	}
+
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	more, err = reader.NextComponent()
+	if err != nil || more {
+		t.Fatal("Wrong:", err)
+	}
+
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
@@ -0,0 +1,498 @@
+package ber
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// universal tag numbers of the types supported by Marshal and Unmarshal (X.680 8.4)
+const (
+	booleanTag          = 1
+	integerTag          = 2
+	bitStringTag        = 3
+	octetStringTag      = 4
+	objectIdentifierTag = 6
+	realTag             = 9
+	enumeratedTag       = 10
+	utf8StringTag       = 12
+	relativeOIDTag      = 13
+	timeTag             = 14
+	sequenceTag         = 16
+	setTag              = 17
+	numericStringTag    = 18
+	printableStringTag  = 19
+	ia5StringTag        = 22
+	utcTimeTag          = 23
+	generalizedTimeTag  = 24
+	visibleStringTag    = 26
+	dateTag             = 31
+	timeOfDayTag        = 32
+	dateTimeTag         = 33
+	durationTag         = 34
+)
+
+var (
+	bigIntType           = reflect.TypeOf((*big.Int)(nil))
+	bitStringType        = reflect.TypeOf(types.BitString{})
+	objectIdentifierType = reflect.TypeOf(types.ObjectIdentifier{})
+	relativeOIDType      = reflect.TypeOf(types.RelativeOID{})
+	utcTimeType          = reflect.TypeOf(types.UTCTime(""))
+	generalizedTimeType  = reflect.TypeOf(types.GeneralizedTime(""))
+	timeType             = reflect.TypeOf(types.Time(""))
+	dateType             = reflect.TypeOf(types.Date(""))
+	timeOfDayType        = reflect.TypeOf(types.TimeOfDay(""))
+	dateTimeType         = reflect.TypeOf(types.DateTime(""))
+	durationType         = reflect.TypeOf(types.Duration(""))
+)
+
+// fieldParameters holds the options of an `asn1` struct tag, e.g. `asn1:"tag:0,explicit,optional"`
+type fieldParameters struct {
+	hasTag     bool           // tag:n given
+	class      types.TagClass // class of the tag (context-specific unless application or private is given)
+	tagNumber  uint64         // number of the tag
+	explicit   bool           // the tag is added around the tag of the type instead of replacing it
+	optional   bool           // the component can be absent (nil)
+	hasDefault bool           // default:v given
+	defaultInt int64          // default value of an INTEGER (1 or 0 for a BOOLEAN)
+	choice     bool           // the struct is a CHOICE, its fields are pointers to the alternatives
+	set        bool           // the struct is a SET, the slice a SET OF
+	enumerated bool           // the integer is an ENUMERATED
+	stringTag  uint64         // universal tag of a string (UTF8String unless another string type is given)
+}
+
+// parseFieldParameters parses the options of an `asn1` struct tag
+func parseFieldParameters(tag string) (fieldParameters, error) {
+	params := fieldParameters{class: types.ContextClass, stringTag: utf8StringTag}
+	hasClass := false
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "":
+		case strings.HasPrefix(option, "tag:"):
+			number, err := strconv.ParseUint(option[4:], 10, 64)
+			if err != nil {
+				return params, errors.New("invalid tag number in " + strconv.Quote(option))
+			}
+			params.hasTag = true
+			params.tagNumber = number
+		case strings.HasPrefix(option, "default:"):
+			text := option[8:]
+			switch text {
+			case "true":
+				params.defaultInt = 1
+			case "false":
+				params.defaultInt = 0
+			default:
+				value, err := strconv.ParseInt(text, 10, 64)
+				if err != nil {
+					return params, errors.New("invalid default value in " + strconv.Quote(option))
+				}
+				params.defaultInt = value
+			}
+			params.hasDefault = true
+		case option == "application":
+			params.class = types.ApplicationClass
+			hasClass = true
+		case option == "private":
+			params.class = types.PrivateClass
+			hasClass = true
+		case option == "explicit":
+			params.explicit = true
+		case option == "implicit":
+		case option == "optional":
+			params.optional = true
+		case option == "choice":
+			params.choice = true
+		case option == "set":
+			params.set = true
+		case option == "enumerated":
+			params.enumerated = true
+		case option == "utf8":
+			params.stringTag = utf8StringTag
+		case option == "numeric":
+			params.stringTag = numericStringTag
+		case option == "printable":
+			params.stringTag = printableStringTag
+		case option == "ia5":
+			params.stringTag = ia5StringTag
+		case option == "visible":
+			params.stringTag = visibleStringTag
+		default:
+			return params, errors.New("unknown asn1 option " + strconv.Quote(option))
+		}
+	}
+	if hasClass && !params.hasTag {
+		return params, errors.New("class given without tag number in " + strconv.Quote(tag))
+	}
+	if params.explicit && !params.hasTag {
+		return params, errors.New("explicit given without tag number in " + strconv.Quote(tag))
+	}
+	return params, nil
+}
+
+// elementParameters returns the parameters of the components of a SEQUENCE OF or SET OF: the string type and enumerated option of the SEQUENCE OF or SET OF
+func elementParameters(params fieldParameters) fieldParameters {
+	return fieldParameters{class: types.ContextClass, stringTag: params.stringTag, enumerated: params.enumerated}
+}
+
+// structField is a field of a struct encoded as a component (or as an alternative of a CHOICE)
+type structField struct {
+	index  int
+	name   string
+	params fieldParameters
+}
+
+// getStructFields returns the fields of a struct which are encoded, unexported fields and fields tagged `asn1:"-"` are ignored
+func getStructFields(t reflect.Type) ([]structField, error) {
+	fields := []structField{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("asn1")
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		params, err := parseFieldParameters(tag)
+		if err != nil {
+			return nil, errors.New(t.Name() + "." + field.Name + ": " + err.Error())
+		}
+		if params.optional && field.Type.Kind() != reflect.Ptr && field.Type.Kind() != reflect.Slice {
+			return nil, errors.New(t.Name() + "." + field.Name + ": optional only supported for pointers and slices")
+		}
+		fields = append(fields, structField{index: i, name: field.Name, params: params})
+	}
+	return fields, nil
+}
+
+// getChoiceAlternatives returns the alternatives of a CHOICE, raises an error if they are not pointers
+func getChoiceAlternatives(t reflect.Type) ([]structField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, errors.New("CHOICE must be a struct, not " + t.String())
+	}
+	alternatives, err := getStructFields(t)
+	if err != nil {
+		return nil, err
+	}
+	for _, alternative := range alternatives {
+		if t.Field(alternative.index).Type.Kind() != reflect.Ptr {
+			return nil, errors.New("alternative " + t.Name() + "." + alternative.name + " of CHOICE must be a pointer")
+		}
+	}
+	return alternatives, nil
+}
+
+// universalTag returns the number of the universal tag of a type and whether its encoding is constructed
+func universalTag(t reflect.Type, params fieldParameters) (uint64, bool, error) {
+	switch t {
+	case bigIntType:
+		return integerTag, false, nil
+	case bitStringType:
+		return bitStringTag, false, nil
+	case objectIdentifierType:
+		return objectIdentifierTag, false, nil
+	case relativeOIDType:
+		return relativeOIDTag, false, nil
+	case utcTimeType:
+		return utcTimeTag, false, nil
+	case generalizedTimeType:
+		return generalizedTimeTag, false, nil
+	case timeType:
+		return timeTag, false, nil
+	case dateType:
+		return dateTag, false, nil
+	case timeOfDayType:
+		return timeOfDayTag, false, nil
+	case dateTimeType:
+		return dateTimeTag, false, nil
+	case durationType:
+		return durationTag, false, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return booleanTag, false, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if params.enumerated {
+			return enumeratedTag, false, nil
+		}
+		return integerTag, false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return integerTag, false, nil
+	case reflect.Float32, reflect.Float64:
+		return realTag, false, nil
+	case reflect.String:
+		return params.stringTag, false, nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return octetStringTag, false, nil
+		}
+		if params.set {
+			return setTag, true, nil
+		}
+		return sequenceTag, true, nil
+	case reflect.Struct:
+		if params.set {
+			return setTag, true, nil
+		}
+		return sequenceTag, true, nil
+	}
+	return 0, false, errors.New("type " + t.String() + " is not supported")
+}
+
+// isDefaultValue returns true if a component has the default value given in its parameters
+func isDefaultValue(v reflect.Value, params fieldParameters) bool {
+	if !params.hasDefault {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool() == (params.defaultInt != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == params.defaultInt
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return params.defaultInt >= 0 && v.Uint() == uint64(params.defaultInt)
+	}
+	return false
+}
+
+// isAbsent returns true if a component is not encoded: it is optional and nil, or it has its default value
+func isAbsent(v reflect.Value, params fieldParameters) bool {
+	if isDefaultValue(v, params) {
+		return true
+	}
+	return params.optional && v.IsNil()
+}
+
+// Marshal returns the DER encoding of val, whose ASN.1 type is given by its Go type and the `asn1` tags of struct fields
+//
+// Go types are mapped as follows:
+// bool: BOOLEAN, integers and *big.Int: INTEGER (ENUMERATED with the enumerated option), float32 and float64: REAL
+// string: UTF8String (NumericString, PrintableString, IA5String or VisibleString with the numeric, printable, ia5 or visible option)
+// []byte: OCTET STRING, types.BitString: BIT STRING, types.ObjectIdentifier: OBJECT IDENTIFIER, types.RelativeOID: RELATIVE-OID
+// types.UTCTime, types.GeneralizedTime, types.Time, types.Date, types.TimeOfDay, types.DateTime and types.Duration: the time types
+// struct: SEQUENCE (SET with the set option, CHOICE with the choice option), other slices: SEQUENCE OF (SET OF with the set option)
+// pointers are followed
+//
+// the options of `asn1` tags are:
+// tag:n gives a context-specific tag (application or private tag with the application or private option), implicit unless explicit is given
+// optional: the component (a pointer or a slice) is absent if it is nil
+// default:v: the component (INTEGER or BOOLEAN) is absent if it has the value v
+// choice: the component is a CHOICE, a struct of pointers to the alternatives of which exactly one is not nil (a tagged CHOICE is always explicit)
+// set, enumerated, utf8, numeric, printable, ia5, visible: see above
+// a field tagged `asn1:"-"` and unexported fields are ignored
+func Marshal(val interface{}) ([]byte, error) {
+	return MarshalWithParams(val, "")
+}
+
+// MarshalWithParams returns the DER encoding of val, with the options of an `asn1` tag applying to val itself (e.g. "choice" or "tag:1,application")
+func MarshalWithParams(val interface{}, params string) ([]byte, error) {
+	w := NewDERWriter(0)
+	_, err := w.WriteValue(val, params)
+	if err != nil {
+		return nil, err
+	}
+	return w.GetDataBuffer(), nil
+}
+
+// WriteValue encodes tag, length and value of val with the encoding rules of the writer and return length of encoded data (see Marshal)
+// params are the options of an `asn1` tag applying to val itself, nothing is written if an error is raised
+func (w *Writer) WriteValue(val interface{}, params string) (int, error) {
+	p, err := parseFieldParameters(params)
+	if err != nil {
+		return 0, err
+	}
+
+	dataSize := w.dataSize
+	nBytes, err := w.marshal(reflect.ValueOf(val), p)
+	if err != nil {
+		w.dataSize = dataSize
+		return 0, err
+	}
+	return nBytes, nil
+}
+
+// marshal writes the TLV encoding of a value and return length of encoded data
+func (w *Writer) marshal(v reflect.Value, params fieldParameters) (int, error) {
+	if !v.IsValid() {
+		return 0, errors.New("cannot encode a nil value")
+	}
+	if v.Kind() == reflect.Ptr && v.Type() != bigIntType {
+		if v.IsNil() {
+			return 0, errors.New("cannot encode a nil " + v.Type().String())
+		}
+		return w.marshal(v.Elem(), params)
+	}
+
+	nBytes, err := w.marshalUntagged(v, params)
+	if err != nil {
+		return 0, err
+	}
+
+	if params.hasTag && (params.explicit || params.choice) {
+		nBytes += w.WriteConstructedLength(nBytes)
+		nBytes += w.WriteTag(types.Tag{Class: params.class, Constructed: true, Number: params.tagNumber})
+	}
+	return nBytes, nil
+}
+
+// marshalUntagged writes the TLV encoding of a value, without the explicit tag given in its parameters
+func (w *Writer) marshalUntagged(v reflect.Value, params fieldParameters) (int, error) {
+	if params.choice {
+		return w.marshalChoice(v)
+	}
+
+	number, constructed, err := universalTag(v.Type(), params)
+	if err != nil {
+		return 0, err
+	}
+	tag := types.Tag{Class: types.UniversalClass, Constructed: constructed, Number: number}
+	if params.hasTag && !params.explicit {
+		tag = types.Tag{Class: params.class, Constructed: constructed, Number: params.tagNumber}
+	}
+	return w.marshalTLV(v, params, EncodeTag(tag))
+}
+
+// marshalChoice writes the TLV encoding of the alternative of a CHOICE which is not nil
+func (w *Writer) marshalChoice(v reflect.Value) (int, error) {
+	alternatives, err := getChoiceAlternatives(v.Type())
+	if err != nil {
+		return 0, err
+	}
+
+	var chosen *structField
+	for i, alternative := range alternatives {
+		if v.Field(alternative.index).IsNil() {
+			continue
+		}
+		if chosen != nil {
+			return 0, errors.New("more than one alternative of CHOICE " + v.Type().Name() + " is set")
+		}
+		chosen = &alternatives[i]
+	}
+	if chosen == nil {
+		return 0, errors.New("no alternative of CHOICE " + v.Type().Name() + " is set")
+	}
+	return w.marshal(v.Field(chosen.index), chosen.params)
+}
+
+// marshalTLV writes the contents, the length and the tag of a value which is not a CHOICE and return length of encoded data
+func (w *Writer) marshalTLV(v reflect.Value, params fieldParameters, tag []byte) (int, error) {
+	// strings are segmented by CER, UTCTime and GeneralizedTime are converted to their DER form by DER and CER
+	switch {
+	case v.Type() == utcTimeType || v.Type() == generalizedTimeType:
+	case v.Type() == bitStringType:
+		return w.WriteBitStringTLV(tag, v.Interface().(types.BitString)), nil
+	case v.Kind() == reflect.String:
+		return w.WriteRestrictedCharacterStringTLV(tag, v.String()), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return w.WriteOctetStringTLV(tag, v.Bytes()), nil
+	}
+
+	var nBytes int
+	var err error
+	if tag[0]&0x20 == 0x20 {
+		nBytes, err = w.marshalConstructedContents(v, params)
+		if err != nil {
+			return 0, err
+		}
+		nBytes += w.WriteConstructedLength(nBytes)
+	} else {
+		nBytes, err = w.marshalPrimitiveContents(v)
+		if err != nil {
+			return 0, err
+		}
+		nBytes += w.WriteLength64(uint64(nBytes))
+	}
+	return nBytes + w.WriteOctetString(tag), nil
+}
+
+// marshalPrimitiveContents writes the contents of a value whose encoding is primitive and return length of encoded data
+func (w *Writer) marshalPrimitiveContents(v reflect.Value) (int, error) {
+	switch v.Type() {
+	case bigIntType:
+		if v.IsNil() {
+			return 0, errors.New("cannot encode a nil *big.Int")
+		}
+		return w.WriteBigInteger(v.Interface().(*big.Int)), nil
+	case objectIdentifierType:
+		nBytes := w.WriteObjectIdentifier(v.Interface().(types.ObjectIdentifier))
+		if nBytes == 0 {
+			return 0, errors.New("invalid OBJECT IDENTIFIER")
+		}
+		return nBytes, nil
+	case relativeOIDType:
+		if v.Len() == 0 {
+			return 0, errors.New("RELATIVE-OID must have at least one arc")
+		}
+		return w.WriteRelativeOID(v.Interface().(types.RelativeOID)), nil
+	case utcTimeType:
+		value := v.Interface().(types.UTCTime)
+		if _, err := value.Time(); err != nil {
+			return 0, err
+		}
+		return w.WriteUTCTime(value), nil
+	case generalizedTimeType:
+		value := v.Interface().(types.GeneralizedTime)
+		if _, err := value.Time(); err != nil {
+			return 0, err
+		}
+		return w.WriteGeneralizedTime(value), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return w.WriteBoolean(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return w.WriteInteger64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return w.WriteUnsignedInteger64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return w.WriteReal(v.Float()), nil
+	}
+	return 0, errors.New("type " + v.Type().String() + " is not supported")
+}
+
+// marshalConstructedContents writes the components of a SEQUENCE, SET, SEQUENCE OF or SET OF and return length of encoded data
+// with DER and CER, the components of a SET or SET OF are sorted
+func (w *Writer) marshalConstructedContents(v reflect.Value, params fieldParameters) (int, error) {
+	nBytes := 0
+
+	// components are written backwards: last component first
+	if v.Kind() == reflect.Slice {
+		for i := v.Len() - 1; i >= 0; i-- {
+			n, err := w.marshal(v.Index(i), elementParameters(params))
+			if err != nil {
+				return 0, err
+			}
+			nBytes += n
+		}
+		if params.set {
+			return nBytes, w.SortSetOfComponents(nBytes)
+		}
+		return nBytes, nil
+	}
+
+	fields, err := getStructFields(v.Type())
+	if err != nil {
+		return 0, err
+	}
+	for i := len(fields) - 1; i >= 0; i-- {
+		field := v.Field(fields[i].index)
+		if isAbsent(field, fields[i].params) {
+			continue
+		}
+		n, err := w.marshal(field, fields[i].params)
+		if err != nil {
+			return 0, errors.New(v.Type().Name() + "." + fields[i].name + ": " + err.Error())
+		}
+		nBytes += n
+	}
+	if params.set {
+		return nBytes, w.SortSetComponents(nBytes)
+	}
+	return nBytes, nil
+}
@@ -0,0 +1,219 @@
+package ber
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+type marshalSimple struct {
+	A int
+	B bool
+}
+
+type marshalChoice struct {
+	Number *int    `asn1:"tag:0"`
+	Text   *string `asn1:"tag:1"`
+}
+
+type marshalTagged struct {
+	Version  int      `asn1:"tag:0,explicit,default:1"`
+	Serial   *big.Int `asn1:"tag:1"`
+	Name     string   `asn1:"printable"`
+	Comment  *string  `asn1:"tag:2,optional"`
+	Flags    types.BitString
+	Oid      types.ObjectIdentifier
+	Value    marshalChoice `asn1:"choice"`
+	Elements []int         `asn1:"set"`
+	ignored  int
+	Ignored  int `asn1:"-"`
+}
+
+type marshalSet struct {
+	B string `asn1:"tag:1"`
+	A int    `asn1:"tag:0"`
+}
+
+func TestMarshalSimple(t *testing.T) {
+	data, err := Marshal(marshalSimple{A: 5, B: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data, []byte{0x30, 0x06, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestMarshalTagged(t *testing.T) {
+	number := 3
+	value := marshalTagged{
+		Version:  1,
+		Serial:   big.NewInt(256),
+		Name:     "ab",
+		Flags:    types.BitString{Bytes: []byte{0x80}, Length: 1},
+		Oid:      types.ObjectIdentifier{1, 2},
+		Value:    marshalChoice{Number: &number},
+		Elements: []int{2, 1},
+		ignored:  7,
+		Ignored:  7,
+	}
+	data, err := Marshal(value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expected := []byte{
+		0x30, 0x1a,
+		0x81, 0x02, 0x01, 0x00, // Serial (Version has its default value)
+		0x13, 0x02, 'a', 'b', // Name
+		0x03, 0x02, 0x07, 0x80, // Flags
+		0x06, 0x01, 0x2a, // Oid
+		0x80, 0x01, 0x03, // Value
+		0x31, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02, // Elements, sorted
+	}
+	if false == bytes.Equal(data, expected) {
+		t.Fatal("Wrong")
+	}
+
+	comment := "c"
+	value.Version = 2
+	value.Comment = &comment
+	data, err = Marshal(value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data[:7], []byte{0x30, 0x22, 0xa0, 0x03, 0x02, 0x01, 0x02}) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(data[15:18], []byte{0x82, 0x01, 'c'}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestMarshalSet(t *testing.T) {
+	data, err := MarshalWithParams(marshalSet{A: 1, B: "x"}, "set")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data, []byte{0x31, 0x06, 0x80, 0x01, 0x01, 0x81, 0x01, 'x'}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestMarshalTopLevelChoice(t *testing.T) {
+	text := "hi"
+	data, err := MarshalWithParams(marshalChoice{Text: &text}, "choice")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data, []byte{0x81, 0x02, 'h', 'i'}) {
+		t.Fatal("Wrong")
+	}
+
+	data, err = MarshalWithParams(marshalChoice{Text: &text}, "choice,tag:3,application")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data, []byte{0x63, 0x04, 0x81, 0x02, 'h', 'i'}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestMarshalHighTagNumber(t *testing.T) {
+	data, err := MarshalWithParams(true, "tag:200,private")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data, []byte{0xdf, 0x81, 0x48, 0x01, 0xff}) {
+		t.Fatal("Wrong")
+	}
+}
+
+type marshalOptional struct {
+	Number *int   `asn1:"tag:0,optional"`
+	Flag   *bool  `asn1:"tag:1,optional"`
+	Bytes  []byte `asn1:"tag:2,optional"`
+}
+
+func TestMarshalOptional(t *testing.T) {
+	number, flag := 0, false
+	data, err := Marshal(marshalOptional{Number: &number, Flag: &flag, Bytes: []byte{}})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(data, []byte{0x30, 0x08, 0x80, 0x01, 0x00, 0x81, 0x01, 0x00, 0x82, 0x00}) {
+		t.Fatal("Wrong")
+	}
+
+	var decoded marshalOptional
+	err = Unmarshal(data, &decoded)
+	if err != nil || decoded.Number == nil || *decoded.Number != 0 || decoded.Flag == nil || *decoded.Flag || decoded.Bytes == nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	data, err = Marshal(marshalOptional{})
+	if err != nil || false == bytes.Equal(data, []byte{0x30, 0x00}) {
+		t.Fatal("Wrong:", err)
+	}
+	err = Unmarshal(data, &decoded)
+	if err != nil || decoded.Number != nil || decoded.Flag != nil || decoded.Bytes != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	// presence of a zero value could not be told apart from absence
+	_, err = Marshal(struct {
+		Number int `asn1:"optional"`
+	}{})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	_, err := MarshalWithParams(marshalChoice{}, "choice")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = Marshal(map[string]int{})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = MarshalWithParams(1, "tag:x")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = MarshalWithParams(1, "explicit")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = Marshal(types.ObjectIdentifier{3})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	var pointer *int
+	_, err = Marshal(pointer)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteValueCER(t *testing.T) {
+	writer := NewCERWriter(0)
+	length, err := writer.WriteValue(marshalSimple{A: 5, B: true}, "")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), []byte{0x30, 0x80, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff, 0x00, 0x00}) || length != 10 {
+		t.Fatal("Wrong")
+	}
+
+	_, err = writer.WriteValue(pointerToNothing(), "")
+	if err == nil || len(writer.GetDataBuffer()) != 10 {
+		t.Fatal("Wrong")
+	}
+}
+
+func pointerToNothing() *marshalSimple {
+	return nil
+}
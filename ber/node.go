@@ -0,0 +1,177 @@
+package ber
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Node is a TLV encoding decoded without knowing its type
+type Node struct {
+	// tag of the encoding
+	Tag types.Tag
+
+	// length of the contents, -1 if length is indefinite
+	Length int64
+
+	// offset of the tag in the stream
+	Offset int64
+
+	// number of bytes of the tag and the length
+	HeaderLength int
+
+	// contents octets (without end-of-contents if length is indefinite)
+	Value []byte
+
+	// encodings held in the contents if the encoding is constructed
+	Children []*Node
+}
+
+// EncodedLength returns the number of bytes of the encoding (including end-of-contents if length is indefinite)
+func (n *Node) EncodedLength() int64 {
+	if n.Length < 0 {
+		return int64(n.HeaderLength+len(n.Value)) + 2
+	}
+	return int64(n.HeaderLength) + n.Length
+}
+
+// Child returns the descendant of the node found by following the indexes given (the node itself if none is given), nil if there is no such descendant
+func (n *Node) Child(indexes ...int) *Node {
+	node := n
+	for _, index := range indexes {
+		if index < 0 || index >= len(node.Children) {
+			return nil
+		}
+		node = node.Children[index]
+	}
+	return node
+}
+
+// MaxNodeDepth is the maximum number of nested encodings decoded into nodes
+const MaxNodeDepth = 100
+
+// DepthError is raised when encodings are nested deeper than MaxNodeDepth
+type DepthError struct {
+	// offset of the first encoding exceeding the maximum depth
+	Offset int64
+}
+
+// Error returns the description of the error
+func (e *DepthError) Error() string {
+	return "encodings nested deeper than " + strconv.Itoa(MaxNodeDepth) + " levels"
+}
+
+// ParseNodes decodes the consecutive TLV encodings of data into trees of nodes
+// values of the nodes are slices of data
+func ParseNodes(data []byte) ([]*Node, error) {
+	r := NewReader(bytes.NewReader(data))
+	r.SetMaxLength(int64(len(data)))
+
+	var nodes []*Node
+	for r.GetOffset() != int64(len(data)) {
+		offset := r.offset
+		err := r.ReadTag()
+		if err != nil {
+			return nil, err
+		}
+		node, err := r.readNode(offset, 1)
+		if err != nil {
+			return nil, err
+		}
+		node.setValues(data, 0)
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// ReadNode reads tag, length and value of the next encoding in the stream and decodes it into a tree of nodes
+// values of the children are slices of the value of their parent
+// raises an error if a primitive encoding has an indefinite length, a *DepthError if encodings are nested deeper than MaxNodeDepth
+func (r *Reader) ReadNode() (*Node, error) {
+	offset := r.offset
+	err := r.ReadTag()
+	if err != nil {
+		return nil, err
+	}
+
+	// bytes read after the tag are recorded once to get the contents octets of all nodes
+	depth := len(r.constructedValues)
+	r.recordedContents = append(r.recordedContents, []byte{})
+	node, err := r.readNode(offset, 1)
+	recorded := r.recordedContents[len(r.recordedContents)-1]
+	r.recordedContents = r.recordedContents[:len(r.recordedContents)-1]
+	if err != nil {
+		// constructed values left open by the error are closed to leave the reader consistent
+		r.constructedValues = r.constructedValues[:depth]
+		return nil, err
+	}
+
+	node.setValues(recorded, r.offset-int64(len(recorded)))
+	return node, nil
+}
+
+// readNode decodes the encoding starting at offset, whose tag has been read, depth is 1 for the outermost encoding
+func (r *Reader) readNode(offset int64, depth int) (*Node, error) {
+	if depth > MaxNodeDepth {
+		return nil, &DepthError{Offset: offset}
+	}
+
+	node := &Node{Tag: r.GetTag(), Offset: offset}
+	tagLength := r.tagLength
+
+	err := r.ReadLength()
+	if err != nil {
+		return nil, err
+	}
+	node.Length = r.lengthValue
+	node.HeaderLength = tagLength + r.lengthLength
+
+	if !node.Tag.Constructed {
+		if node.Length < 0 {
+			return nil, errors.New("indefinite length in primitive encoding")
+		}
+		return node, r.skip(node.Length)
+	}
+
+	err = r.OpenConstructed()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		childOffset := r.offset
+		more, err := r.NextComponent()
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			break
+		}
+		child, err := r.readNode(childOffset, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, r.CloseConstructed()
+}
+
+// setValues sets the values of the node and its descendants as slices of buffer, which holds the bytes of the stream starting at offset base
+func (n *Node) setValues(buffer []byte, base int64) {
+	for _, child := range n.Children {
+		child.setValues(buffer, base)
+	}
+
+	start := n.Offset + int64(n.HeaderLength)
+	end := start + n.Length
+	if n.Length < 0 {
+		// contents of indefinite length are the children followed by end-of-contents
+		end = start
+		if len(n.Children) != 0 {
+			last := n.Children[len(n.Children)-1]
+			end = last.Offset + last.EncodedLength()
+		}
+	}
+	n.Value = buffer[start-base : end-base : end-base]
+}
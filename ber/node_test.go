@@ -0,0 +1,167 @@
+package ber
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestParseNodesDefinite(t *testing.T) {
+	data := []byte{0x30, 0x08, 0x02, 0x01, 0x05, 0xa0, 0x03, 0x01, 0x01, 0xff, 0x05, 0x00}
+	nodes, err := ParseNodes(data)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatal("Wrong")
+	}
+
+	root := nodes[0]
+	if false == root.Tag.Equal(types.Tag{Class: types.UniversalClass, Constructed: true, Number: 16}) {
+		t.Fatal("Wrong")
+	}
+	if root.Length != 8 || root.Offset != 0 || root.HeaderLength != 2 || root.EncodedLength() != 10 || len(root.Children) != 2 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(root.Value, data[2:10]) {
+		t.Fatal("Wrong")
+	}
+
+	integer := root.Child(0)
+	if integer.Offset != 2 || integer.Length != 1 || false == bytes.Equal(integer.Value, []byte{0x05}) || integer.Children != nil {
+		t.Fatal("Wrong")
+	}
+
+	boolean := root.Child(1, 0)
+	if boolean == nil || boolean.Offset != 7 || false == bytes.Equal(boolean.Value, []byte{0xff}) {
+		t.Fatal("Wrong")
+	}
+	if root.Child(1, 1) != nil || root.Child(2) != nil || root.Child() != root {
+		t.Fatal("Wrong")
+	}
+
+	null := nodes[1]
+	if null.Offset != 10 || null.Length != 0 || null.Tag.Number != 5 || len(null.Value) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseNodesIndefinite(t *testing.T) {
+	data := []byte{0x30, 0x80, 0x24, 0x80, 0x04, 0x01, 'a', 0x00, 0x00, 0x9f, 0x81, 0x48, 0x81, 0x01, 0x07, 0x00, 0x00}
+	nodes, err := ParseNodes(data)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if len(nodes) != 1 {
+		t.Fatal("Wrong")
+	}
+
+	root := nodes[0]
+	if root.Length != -1 || root.HeaderLength != 2 || root.EncodedLength() != 17 || len(root.Children) != 2 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(root.Value, data[2:15]) {
+		t.Fatal("Wrong")
+	}
+
+	segments := root.Child(0)
+	if segments.Length != -1 || len(segments.Children) != 1 || false == bytes.Equal(segments.Child(0).Value, []byte{'a'}) {
+		t.Fatal("Wrong")
+	}
+
+	tagged := root.Child(1)
+	if tagged.Tag.String() != "[200]" || tagged.Offset != 9 || tagged.HeaderLength != 5 || tagged.Length != 1 || false == bytes.Equal(tagged.Value, []byte{0x07}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseNodesErrors(t *testing.T) {
+	invalids := [][]byte{
+		{0x30, 0x03, 0x02, 0x01},             // truncated
+		{0x30, 0x02, 0x02, 0x01, 0x05},       // child overruns its parent
+		{0x04, 0x80, 0x00, 0x00},             // indefinite length in primitive encoding
+		{0x30, 0x80, 0x02, 0x01, 0x05},       // missing end-of-contents
+		{0x30, 0x05, 0x02, 0x01, 0x05, 0x00}, // length exceeding data
+	}
+	for _, invalid := range invalids {
+		_, err := ParseNodes(invalid)
+		if err == nil {
+			t.Fatal("Wrong")
+		}
+	}
+}
+
+func TestReadNodeDER(t *testing.T) {
+	reader := NewDERReader(bytes.NewReader([]byte{0x30, 0x80, 0x00, 0x00}))
+	_, err := reader.ReadNode()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+// nestedIndefinite returns depth constructed encodings of indefinite length nested in one another around a NULL
+func nestedIndefinite(depth int) []byte {
+	data := bytes.Repeat([]byte{0x30, 0x80}, depth)
+	data = append(data, 0x05, 0x00)
+	return append(data, make([]byte, 2*depth)...)
+}
+
+func TestParseNodesDepth(t *testing.T) {
+	nodes, err := ParseNodes(nestedIndefinite(MaxNodeDepth - 1))
+	if err != nil || len(nodes) != 1 {
+		t.Fatal("Wrong:", err)
+	}
+	node := nodes[0]
+	for i := 0; i < MaxNodeDepth-1; i++ {
+		node = node.Child(0)
+	}
+	if node == nil || node.Tag.Number != 5 || node.Offset != 2*(MaxNodeDepth-1) {
+		t.Fatal("Wrong")
+	}
+	if nodes[0].EncodedLength() != int64(4*(MaxNodeDepth-1)+2) {
+		t.Fatal("Wrong")
+	}
+
+	_, err = ParseNodes(nestedIndefinite(20000))
+	depthErr, ok := err.(*DepthError)
+	if !ok || depthErr.Offset != 2*MaxNodeDepth {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadNodeDepth(t *testing.T) {
+	data := append(nestedIndefinite(20000), 0x05, 0x00)
+	reader := NewReader(bytes.NewReader(data))
+	reader.SetMaxLength(int64(len(data)))
+	_, err := reader.ReadNode()
+	if _, ok := err.(*DepthError); !ok {
+		t.Fatal("Wrong:", err)
+	}
+	if len(reader.constructedValues) != 0 || len(reader.recordedContents) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadNodeValues(t *testing.T) {
+	// a node read after other bytes of the stream
+	data := []byte{0x05, 0x00, 0x30, 0x80, 0x24, 0x80, 0x04, 0x01, 'a', 0x00, 0x00, 0x02, 0x01, 0x05, 0x00, 0x00}
+	reader := NewReader(bytes.NewReader(data))
+	_, err := reader.ReadNode()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	node, err := reader.ReadNode()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if node.Offset != 2 || false == bytes.Equal(node.Value, data[4:14]) || node.EncodedLength() != 14 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(node.Child(0).Value, data[6:9]) || false == bytes.Equal(node.Child(0, 0).Value, []byte{'a'}) {
+		t.Fatal("Wrong")
+	}
+	if node.Child(1).Offset != 11 || false == bytes.Equal(node.Child(1).Value, []byte{0x05}) {
+		t.Fatal("Wrong")
+	}
+}
@@ -3,46 +3,148 @@ package ber
 import (
 	"errors"
 	"io"
+	"math/big"
 
+	"github.com/yafred/asn1-go/internal/octets"
 	"github.com/yafred/asn1-go/types"
 )
 
+// DefaultMaxLength is the maximum length value accepted by a Reader unless SetMaxLength is called
+const DefaultMaxLength = octets.DefaultMaxLength
+
+// readChunkLength is the maximum number of bytes allocated before they are read, so that a hostile length does not cause a large allocation
+const readChunkLength = 0x10000
+
+// maxInt is the largest value of an int
+const maxInt = int64(^uint(0) >> 1)
+
 // reader helps decode ASN.1 values
 type Reader struct {
 	// stream to read from
 	in io.Reader
 
+	// encoding rules enforced by the reader
+	rules EncodingRules
+
+	// bytes read since the beginning of the SET OF values (to check their order) and nodes (see ReadNode) being read, innermost last
+	recordedContents [][]byte
+
 	// value of last read length
 	lengthLength int
-	lengthValue  int
+	lengthValue  int64
+
+	// maximum length value accepted
+	maxLength int64
 
 	// value of last read tag
 	tagLength  int
-	tagBuffer  [10]byte
+	tagBuffer  [maxTagLength]byte
 	tagMatched bool
+
+	// number of bytes read from the stream
+	offset int64
+
+	// constructed values being read (innermost last)
+	constructedValues []constructedValue
 }
 
 // NewReader creates a reader
 func NewReader(in io.Reader) *Reader {
 	r := new(Reader)
 	r.in = in
+	r.maxLength = DefaultMaxLength
+	return r
+}
+
+// NewDERReader creates a reader rejecting anything that is not valid DER
+func NewDERReader(in io.Reader) *Reader {
+	r := NewReader(in)
+	r.rules = DER
 	return r
 }
 
+// GetEncodingRules returns the encoding rules enforced by the reader
+func (r *Reader) GetEncodingRules() EncodingRules {
+	return r.rules
+}
+
+// read fills buffer from the stream, raises an error if end of stream is reached
+func (r *Reader) read(buffer []byte) error {
+	n, err := io.ReadFull(r.in, buffer)
+	r.offset += int64(n)
+	for i := range r.recordedContents {
+		r.recordedContents[i] = append(r.recordedContents[i], buffer[:n]...)
+	}
+	return err
+}
+
+// readBytes reads nBytes bytes from the stream, the buffer grows as bytes are read
+func (r *Reader) readBytes(nBytes int) ([]byte, error) {
+	if nBytes < 0 {
+		return nil, errors.New("negative length")
+	}
+	if nBytes <= readChunkLength {
+		buffer := make([]byte, nBytes)
+		return buffer, r.read(buffer)
+	}
+
+	buffer := make([]byte, 0, readChunkLength)
+	for len(buffer) < nBytes {
+		chunkLength := nBytes - len(buffer)
+		if chunkLength > readChunkLength {
+			chunkLength = readChunkLength
+		}
+		start := len(buffer)
+		buffer = append(buffer, make([]byte, chunkLength)...)
+		err := r.read(buffer[start:])
+		if err != nil {
+			return buffer, err
+		}
+	}
+	return buffer, nil
+}
+
+// skip reads nBytes bytes from the stream without keeping them
+func (r *Reader) skip(nBytes int64) error {
+	buffer := make([]byte, readChunkLength)
+	for nBytes > 0 {
+		chunkLength := int64(readChunkLength)
+		if chunkLength > nBytes {
+			chunkLength = nBytes
+		}
+		err := r.read(buffer[:chunkLength])
+		if err != nil {
+			return err
+		}
+		nBytes -= chunkLength
+	}
+	return nil
+}
+
 // ReadOctetString decodes a []byte value from dataBuffer at current offset, raises an error if end of dataBuffer is reached
+// if the last read tag is constructed, the segments are reassembled (see ReadConstructedOctetString)
 func (r *Reader) ReadOctetString(nBytes int) ([]byte, error) {
-	buffer := make([]byte, nBytes)
-
-	_, err := r.in.Read(buffer)
+	if r.isConstructed() {
+		return r.ReadConstructedOctetString(nBytes)
+	}
+	if nBytes < 0 {
+		return nil, errors.New("indefinite length in primitive encoding")
+	}
 
-	return buffer[0:], err
+	return r.readBytes(nBytes)
 }
 
 // ReadRestrictedCharacterString decodes a string value from dataBuffer at current offset, raises an error if end of dataBuffer is reached
+// if the last read tag is constructed, the segments are reassembled (see ReadConstructedRestrictedCharacterString)
 func (r *Reader) ReadRestrictedCharacterString(nBytes int) (string, error) {
-	buffer := make([]byte, nBytes)
+	if r.isConstructed() {
+		return r.ReadConstructedRestrictedCharacterString(nBytes)
+	}
+	if nBytes < 0 {
+		return "", errors.New("indefinite length in primitive encoding")
+	}
 
-	_, err := r.in.Read(buffer)
+	buffer, err := r.readBytes(nBytes)
 
 	return string(buffer), err
 }
@@ -56,21 +158,65 @@ func (r *Reader) ReadBoolean() (bool, error) {
 	if aByte == 0 {
 		return false, nil
 	}
+	if aByte != 0xFF && r.rules == DER {
+		return false, errors.New("BOOLEAN value must be 0x00 or 0xFF in DER")
+	}
 
 	return true, nil
 }
 
+// ReadNull checks the length of a NULL value, raises an error if it is not zero
+func (r *Reader) ReadNull(nBytes int) error {
+	if nBytes != 0 {
+		return errors.New("NULL value must have zero length")
+	}
+	return nil
+}
+
+// ReadEnumerated reads nBytes bytes from the dataBuffer to decode an ENUMERATED value, raises an error if end of dataBuffer is reached
+// raises an error if the value is not one of allowedValues
+func (r *Reader) ReadEnumerated(nBytes int, allowedValues []int) (int, error) {
+	value, isUnknown, err := r.ReadExtensibleEnumerated(nBytes, allowedValues)
+	if err != nil {
+		return 0, err
+	}
+	if isUnknown {
+		return 0, errors.New("ENUMERATED value not allowed")
+	}
+	return value, nil
+}
+
+// ReadExtensibleEnumerated reads nBytes bytes from the dataBuffer to decode the value of an extensible ENUMERATED, raises an error if end of dataBuffer is reached
+// the returned bool is true if the value is not one of knownValues (unknown extension)
+func (r *Reader) ReadExtensibleEnumerated(nBytes int, knownValues []int) (int, bool, error) {
+	value, err := r.ReadInteger64(nBytes)
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, knownValue := range knownValues {
+		if int64(knownValue) == value {
+			return knownValue, false, nil
+		}
+	}
+
+	if int64(int(value)) != value {
+		return 0, false, errors.New("ENUMERATED value overflows int")
+	}
+	return int(value), true, nil
+}
+
 // readByte reads a byte from the dataBuffer, raises an error if end of dataBuffer is reached
 func (r *Reader) readByte() (byte, error) {
 	buffer := make([]byte, 1)
 
-	_, err := r.in.Read(buffer)
+	err := r.read(buffer)
 
 	return buffer[0], err
 }
 
 // ReadLength reads a length from the dataBuffer, raises an error if end of dataBuffer is reached
-// raises an error if length has more than 4 bytes
+// raises an error if length value does not fit in 8 bytes or exceeds the maximum length (see SetMaxLength)
 func (r *Reader) ReadLength() error {
 	r.lengthLength = 0
 	r.lengthValue = 0
@@ -82,38 +228,77 @@ func (r *Reader) ReadLength() error {
 	}
 
 	if aByte == 0x80 {
+		if r.rules == DER {
+			return errors.New("indefinite length not allowed in DER")
+		}
 		r.lengthLength = 1
 		r.lengthValue = -1
 	} else {
 		if aByte > 0x7f { // long form
 
-			nBytes := aByte & 0x7f
-
-			if nBytes > 4 {
-				return errors.New("length value more than 4 bytes not supported")
+			if aByte == 0xff {
+				return errors.New("reserved length form 0xFF")
 			}
 
-			r.lengthLength = int(nBytes) + 1
+			nBytes := int(aByte & 0x7f)
+
+			r.lengthLength = nBytes + 1
 			r.lengthValue = 0
 
+			var value uint64
 			for i := nBytes; i > 0; i-- {
 				aByte, err = r.readByte()
 				if err != nil {
 					return err
 				}
-				r.lengthValue += int(aByte) << ((i - 1) * 8)
+				if r.rules == DER && i == nBytes && aByte == 0 {
+					return errors.New("length encoded with leading zero byte not allowed in DER")
+				}
+				if value > 0xffffffffffffff {
+					return errors.New("length value more than 8 bytes not supported")
+				}
+				value = value<<8 | uint64(aByte)
+			}
+
+			if value > uint64(r.maxLength) {
+				return errors.New("length value exceeds maximum length")
+			}
+			r.lengthValue = int64(value)
+
+			if r.rules == DER && r.lengthValue < 0x80 {
+				return errors.New("long form length which fits in short form not allowed in DER")
 			}
 		} else { // short form
 			r.lengthLength = 1
-			r.lengthValue = int(aByte)
+			r.lengthValue = int64(aByte)
 		}
 	}
 
+	if r.lengthValue > r.maxLength {
+		return errors.New("length value exceeds maximum length")
+	}
+
+	return nil
+}
+
+// SetMaxLength sets the maximum length value accepted by ReadLength (DefaultMaxLength if not set)
+// raises an error and keeps the current maximum if value is negative
+func (r *Reader) SetMaxLength(value int64) error {
+	if value < 0 {
+		return errors.New("negative maximum length")
+	}
+	r.maxLength = value
 	return nil
 }
 
 // GetLengthValue returns the last read length value (-1 if form is indefinite)
+// the value is truncated if it does not fit in an int (32-bit platforms), see GetLengthValue64
 func (r *Reader) GetLengthValue() int {
+	return int(r.lengthValue)
+}
+
+// GetLengthValue64 returns the last read length value as an int64 (-1 if form is indefinite)
+func (r *Reader) GetLengthValue64() int64 {
 	return r.lengthValue
 }
 
@@ -127,11 +312,15 @@ func (r *Reader) ReadInteger(nBytes int) (int, error) {
 	if nBytes > 4 {
 		return 0, errors.New("integers over 4 bytes not supported")
 	}
+	if nBytes < 1 {
+		return 0, errors.New("zero length INTEGER")
+	}
 
 	aByte, err := r.readByte()
 	if err != nil {
 		return 0, err
 	}
+	firstByte := aByte
 
 	result := 0
 	mult := 1
@@ -150,6 +339,9 @@ func (r *Reader) ReadInteger(nBytes int) (int, error) {
 		if err != nil {
 			return 0, err
 		}
+		if i == nBytes-1 && r.rules == DER && isPaddedInteger(firstByte, aByte) {
+			return 0, errors.New("INTEGER encoded with leading padding byte not allowed in DER")
+		}
 		if mult == -1 {
 			aByte = aByte ^ 0xff
 		}
@@ -160,16 +352,116 @@ func (r *Reader) ReadInteger(nBytes int) (int, error) {
 	return result * mult, nil
 }
 
+// ReadInteger64 reads a maximum of 8 bytes from the dataBuffer to decode an int64, raises an error if end of dataBuffer is reached
+func (r *Reader) ReadInteger64(nBytes int) (int64, error) {
+	if nBytes > 8 {
+		return 0, errors.New("integers over 8 bytes not supported by ReadInteger64")
+	}
+
+	buffer, err := r.readIntegerBytes(nBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	var result int64
+	if buffer[0]&0x80 == 0x80 {
+		result = -1 // sign extension
+	}
+	for _, aByte := range buffer {
+		result = result<<8 | int64(aByte)
+	}
+
+	return result, nil
+}
+
+// ReadUnsignedInteger64 reads a maximum of 9 bytes from the dataBuffer to decode a uint64, raises an error if end of dataBuffer is reached
+// raises an error if the value is negative
+func (r *Reader) ReadUnsignedInteger64(nBytes int) (uint64, error) {
+	if nBytes > 9 {
+		return 0, errors.New("integers over 9 bytes not supported by ReadUnsignedInteger64")
+	}
+
+	buffer, err := r.readIntegerBytes(nBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	if buffer[0]&0x80 == 0x80 {
+		return 0, errors.New("negative INTEGER cannot be decoded as uint64")
+	}
+	if nBytes == 9 && buffer[0] != 0 {
+		return 0, errors.New("INTEGER overflows uint64")
+	}
+
+	var result uint64
+	for _, aByte := range buffer {
+		result = result<<8 | uint64(aByte)
+	}
+
+	return result, nil
+}
+
+// ReadBigInteger reads nBytes bytes from the dataBuffer to decode an INTEGER of any size, raises an error if end of dataBuffer is reached
+func (r *Reader) ReadBigInteger(nBytes int) (*big.Int, error) {
+	buffer, err := r.readIntegerBytes(nBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Int)
+	if buffer[0]&0x80 == 0 {
+		return result.SetBytes(buffer), nil
+	}
+
+	// negative number: value is -(^buffer + 1)
+	for i := range buffer {
+		buffer[i] ^= 0xff
+	}
+	result.SetBytes(buffer)
+	result.Add(result, big.NewInt(1))
+	return result.Neg(result), nil
+}
+
+// readIntegerBytes reads the nBytes bytes of an INTEGER, raises an error if end of dataBuffer is reached
+func (r *Reader) readIntegerBytes(nBytes int) ([]byte, error) {
+	if nBytes < 1 {
+		return nil, errors.New("zero length INTEGER")
+	}
+
+	buffer, err := r.readBytes(nBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if nBytes > 1 && r.rules == DER && isPaddedInteger(buffer[0], buffer[1]) {
+		return nil, errors.New("INTEGER encoded with leading padding byte not allowed in DER")
+	}
+
+	return buffer, nil
+}
+
+// isPaddedInteger returns true if the first byte of an INTEGER is redundant (X.690 8.3.2)
+func isPaddedInteger(firstByte byte, secondByte byte) bool {
+	return firstByte == 0x00 && secondByte&0x80 == 0 || firstByte == 0xFF && secondByte&0x80 == 0x80
+}
+
 // ReadBitString reads a nBytes bytes from the dataBuffer to decode a BitString, raises an error if end of dataBuffer is reached
+// if the last read tag is constructed, the segments are reassembled (see ReadConstructedBitString)
 func (r *Reader) ReadBitString(nBytes int) (types.BitString, error) {
+	if r.isConstructed() {
+		return r.ReadConstructedBitString(nBytes)
+	}
+
 	result := types.BitString{}
 
+	if nBytes < 0 {
+		return result, errors.New("indefinite length in primitive encoding")
+	}
 	if nBytes == 0 {
 		return result, errors.New("zero length BIT STRING")
 	}
 
-	bytes := make([]byte, nBytes)
-	_, err := r.in.Read(bytes)
+	bytes, err := r.readBytes(nBytes)
 	if err != nil {
 		return result, err
 	}
@@ -194,14 +486,17 @@ func (r *Reader) ReadRelativeOID(nBytes int) (types.RelativeOID, error) {
 		return nil, err
 	}
 
-	buffer := make([]byte, nBytes)
-
-	_, err := r.in.Read(buffer)
+	buffer, err := r.readBytes(nBytes)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if buffer[nBytes-1]&0x80 == 0x80 {
+		err := errors.New("ReadRelativeOID last arc is truncated")
+		return nil, err
+	}
+
 	// The number of arcs in the RelativeOID will have the same number bytes to decode
 	ret := make([]int64, nBytes)
 	currentArc := -1
@@ -269,6 +564,10 @@ func (r *Reader) ReadTag() error {
 	}
 
 	for i := 1; !isLastByte; i++ {
+		if i == len(r.tagBuffer) {
+			return errors.New("tag number more than 64 bits not supported")
+		}
+
 		r.tagBuffer[i], err = r.readByte()
 
 		if err != nil {
@@ -282,18 +581,28 @@ func (r *Reader) ReadTag() error {
 		}
 	}
 
+	_, _, err = DecodeTag(r.tagBuffer[:r.tagLength])
+	if err != nil {
+		return err
+	}
+
 	// switch toggle (will be set again when length is read ... meaning that tag has been matched)
 	r.tagMatched = false
 
 	return nil
 }
 
+// isConstructed returns true if the last read tag is constructed
+func (r *Reader) isConstructed() bool {
+	return r.tagLength != 0 && r.tagBuffer[0]&0x20 != 0
+}
+
 // GetTagLength returns the length of the last read tag
 func (r *Reader) GetTagLength() int {
 	return r.tagLength
 }
 
-// MatchTag return true if input matches last read tag
+// MatchTag return true if input matches last read tag (see IsTag)
 func (r *Reader) MatchTag(tag []byte) bool {
 	r.tagMatched = false
 	if r.tagLength == len(tag) {
@@ -308,7 +617,7 @@ func (r *Reader) MatchTag(tag []byte) bool {
 	return r.tagMatched
 }
 
-// LookAheadTag return true if one item of the input matches last read tag
+// LookAheadTag return true if one item of the input matches last read tag (see IsOneOfTags)
 func (r *Reader) LookAheadTag(tags [][]byte) bool {
 	foundMatch := false
 
@@ -328,3 +637,35 @@ func (r *Reader) LookAheadTag(tags [][]byte) bool {
 
 	return foundMatch
 }
+
+// BeginSetOf must be called after the length of a SET OF has been read, before its components are read
+// with DER, the encodings of the components are recorded until EndSetOf is called
+func (r *Reader) BeginSetOf() {
+	if r.rules == DER {
+		r.recordedContents = append(r.recordedContents, []byte{})
+	}
+}
+
+// EndSetOf must be called after the last component of a SET OF has been read
+// with DER, raises an error if the components were not sorted in ascending order of their encodings
+func (r *Reader) EndSetOf() error {
+	if r.rules != DER || len(r.recordedContents) == 0 {
+		return nil
+	}
+
+	contents := r.recordedContents[len(r.recordedContents)-1]
+	r.recordedContents = r.recordedContents[:len(r.recordedContents)-1]
+
+	components, err := splitEncodings(contents)
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i < len(components); i++ {
+		if octets.CompareSetOfEncodings(components[i-1], components[i]) > 0 {
+			return errors.New("SET OF components not sorted in DER")
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package ber
 
 import (
 	"bytes"
+	"math/big"
+	"runtime"
 	"testing"
 )
 
@@ -263,6 +265,18 @@ func TestReadRelativeOID2(t *testing.T) {
 	}
 }
 
+func TestReadRelativeOIDTruncated(t *testing.T) {
+	in := bytes.NewReader([]byte{0x2a, 0x86})
+
+	reader := NewReader(in)
+
+	_, err := reader.ReadRelativeOID(2)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
 func TestReadObjectIdentifier(t *testing.T) {
 	in := bytes.NewReader([]byte{0x29, 0x28})
 
@@ -469,3 +483,481 @@ func TestReadTagLookAhead2(t *testing.T) {
 		t.Fatal("Wrong")
 	}
 }
+
+func TestDERReadLengthIndefinite(t *testing.T) {
+	in := bytes.NewReader([]byte{0x80})
+
+	reader := NewDERReader(in)
+
+	err := reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadLengthNotMinimal(t *testing.T) {
+	in := bytes.NewReader([]byte{0x81, 0x7f})
+
+	reader := NewDERReader(in)
+
+	err := reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x82, 0x00, 0x81})
+
+	reader = NewDERReader(in)
+
+	err = reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x81, 0x80})
+
+	reader = NewDERReader(in)
+
+	err = reader.ReadLength()
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if reader.GetLengthValue() != 128 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadBoolean(t *testing.T) {
+	in := bytes.NewReader([]byte{0x01})
+
+	reader := NewDERReader(in)
+
+	_, err := reader.ReadBoolean()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x01})
+
+	reader = NewReader(in)
+
+	value, err := reader.ReadBoolean()
+
+	if err != nil || value == false {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadIntegerPadding(t *testing.T) {
+	in := bytes.NewReader([]byte{0x00, 0x7f})
+
+	reader := NewDERReader(in)
+
+	_, err := reader.ReadInteger(2)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0xff, 0x80})
+
+	reader = NewDERReader(in)
+
+	_, err = reader.ReadInteger(2)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x00, 0x80})
+
+	reader = NewDERReader(in)
+
+	value, err := reader.ReadInteger(2)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != 128 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadIntegerZeroLength(t *testing.T) {
+	in := bytes.NewReader([]byte{0x01})
+
+	reader := NewReader(in)
+
+	_, err := reader.ReadInteger(0)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func readSetOfIntegers(reader *Reader, nComponents int) error {
+	reader.BeginSetOf()
+	for i := 0; i < nComponents; i++ {
+		err := reader.ReadTag()
+		if err != nil {
+			return err
+		}
+		err = reader.ReadLength()
+		if err != nil {
+			return err
+		}
+		_, err = reader.ReadInteger(reader.GetLengthValue())
+		if err != nil {
+			return err
+		}
+	}
+	return reader.EndSetOf()
+}
+
+func TestDERReadSetOf(t *testing.T) {
+	in := bytes.NewReader([]byte{0x02, 0x01, 0x01, 0x02, 0x01, 0x03, 0x02, 0x02, 0x01, 0x00})
+
+	reader := NewDERReader(in)
+
+	err := readSetOfIntegers(reader, 3)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestDERReadSetOfUnsorted(t *testing.T) {
+	in := bytes.NewReader([]byte{0x02, 0x01, 0x03, 0x02, 0x01, 0x01})
+
+	reader := NewDERReader(in)
+
+	err := readSetOfIntegers(reader, 2)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x02, 0x01, 0x03, 0x02, 0x01, 0x01})
+
+	reader = NewReader(in)
+
+	err = readSetOfIntegers(reader, 2)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadInteger64(t *testing.T) {
+	in := bytes.NewReader([]byte{0xf8, 0x00, 0x00, 0x00, 0x00, 0x00})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadInteger64(6)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != -0x80000000000 {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.ReadInteger64(9)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadUnsignedInteger64(t *testing.T) {
+	in := bytes.NewReader([]byte{0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadUnsignedInteger64(9)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != 0xffffffffffffffff {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.ReadUnsignedInteger64(1)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadBigInteger(t *testing.T) {
+	in := bytes.NewReader([]byte{0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadBigInteger(12)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedValue, _ := new(big.Int).SetString("ff00000000000000000000", 16)
+	if value.Cmp(expectedValue) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadBigIntegerNegative(t *testing.T) {
+	in := bytes.NewReader([]byte{0xff, 0x7f, 0xfe, 0x82, 0x87, 0xc0})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadBigInteger(2)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value.Int64() != -129 {
+		t.Fatal("Wrong")
+	}
+
+	value, err = reader.ReadBigInteger(4)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value.Int64() != -25000000 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadBigIntegerPadding(t *testing.T) {
+	in := bytes.NewReader([]byte{0xff, 0xff})
+
+	reader := NewDERReader(in)
+
+	_, err := reader.ReadBigInteger(2)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadLength64(t *testing.T) {
+	in := bytes.NewReader([]byte{0x85, 0x01, 0x00, 0x00, 0x00, 0x00})
+
+	reader := NewReader(in)
+
+	err := reader.ReadLength()
+
+	// over DefaultMaxLength
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x85, 0x01, 0x00, 0x00, 0x00, 0x00})
+
+	reader = NewReader(in)
+	reader.SetMaxLength(0x7FFFFFFFFFFFFFFF)
+
+	err = reader.ReadLength()
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if reader.GetLengthValue64() != 0x100000000 {
+		t.Fatal("Wrong")
+	}
+
+	if reader.GetLengthLength() != 6 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadLengthMax(t *testing.T) {
+	in := bytes.NewReader([]byte{0x82, 0x01, 0x00})
+
+	reader := NewReader(in)
+	reader.SetMaxLength(255)
+
+	err := reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte{0x7f})
+
+	reader = NewReader(in)
+	reader.SetMaxLength(100)
+
+	err = reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestSetMaxLength(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0x01}))
+
+	if reader.SetMaxLength(-1) == nil {
+		t.Fatal("Wrong")
+	}
+
+	err := reader.SetMaxLength(0)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	err = reader.ReadLength()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadOctetStringHostileLength(t *testing.T) {
+	// 4 GiB declared, 2 bytes available
+	in := bytes.NewReader([]byte{0x04, 0x84, 0xff, 0xff, 0xff, 0xff, 0x01, 0x02})
+
+	reader := NewReader(in)
+	reader.ReadTag()
+	reader.ReadLength()
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := reader.ReadOctetString(reader.GetLengthValue())
+	runtime.ReadMemStats(&after)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	if after.TotalAlloc-before.TotalAlloc > 1<<20 {
+		t.Fatal("Wrong:", after.TotalAlloc-before.TotalAlloc)
+	}
+
+	// value read in several chunks
+	data := make([]byte, 3*readChunkLength+1)
+	data[len(data)-1] = 0xff
+	reader = NewReader(bytes.NewReader(data))
+	value, err := reader.ReadOctetString(len(data))
+	if err != nil || false == bytes.Equal(value, data) {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadLengthTooLong(t *testing.T) {
+	in := bytes.NewReader([]byte{0x89, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
+
+	reader := NewReader(in)
+	reader.SetMaxLength(0x7FFFFFFFFFFFFFFF)
+
+	err := reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// leading zeros are fine in BER
+	in = bytes.NewReader([]byte{0x89, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0a})
+
+	reader = NewReader(in)
+
+	err = reader.ReadLength()
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if reader.GetLengthValue() != 10 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadLengthReserved(t *testing.T) {
+	in := bytes.NewReader([]byte{0xff})
+
+	reader := NewReader(in)
+
+	err := reader.ReadLength()
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadNull(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{}))
+
+	err := reader.ReadNull(0)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	err = reader.ReadNull(1)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadEnumerated(t *testing.T) {
+	in := bytes.NewReader([]byte{0x02, 0x05})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadEnumerated(1, []int{0, 1, 2})
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != 2 {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.ReadEnumerated(1, []int{0, 1, 2})
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadExtensibleEnumerated(t *testing.T) {
+	in := bytes.NewReader([]byte{0x01, 0x05})
+
+	reader := NewReader(in)
+
+	value, isUnknown, err := reader.ReadExtensibleEnumerated(1, []int{0, 1, 2})
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != 1 || isUnknown {
+		t.Fatal("Wrong")
+	}
+
+	value, isUnknown, err = reader.ReadExtensibleEnumerated(1, []int{0, 1, 2})
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != 5 || !isUnknown {
+		t.Fatal("Wrong")
+	}
+}
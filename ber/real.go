@@ -0,0 +1,318 @@
+package ber
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// REAL special values (X.690 8.5.9)
+const (
+	realPlusInfinity  = 0x40
+	realMinusInfinity = 0x41
+	realNotANumber    = 0x42
+	realMinusZero     = 0x43
+)
+
+// ISO 6093 number representations used by REAL decimal encodings (X.690 8.5.8)
+const (
+	realNR1 = 0x01
+	realNR2 = 0x02
+	realNR3 = 0x03
+)
+
+// ReadReal reads nBytes bytes from the dataBuffer to decode a REAL (binary, decimal or special value), raises an error if end of dataBuffer is reached
+// with DER, raises an error if the encoding is not the canonical one (X.690 11.3)
+func (r *Reader) ReadReal(nBytes int) (float64, error) {
+	if nBytes == 0 {
+		return 0, nil
+	}
+
+	buffer, err := r.readBytes(nBytes)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case buffer[0]&0x80 == 0x80:
+		return r.decodeBinaryReal(buffer)
+	case buffer[0]&0xC0 == 0x00:
+		return r.decodeDecimalReal(buffer)
+	}
+
+	if nBytes != 1 {
+		return 0, errors.New("invalid REAL special value")
+	}
+	switch buffer[0] {
+	case realPlusInfinity:
+		return math.Inf(1), nil
+	case realMinusInfinity:
+		return math.Inf(-1), nil
+	case realNotANumber:
+		return math.NaN(), nil
+	case realMinusZero:
+		return math.Copysign(0, -1), nil
+	}
+	return 0, errors.New("invalid REAL special value")
+}
+
+// decodeBinaryReal decodes the contents of a REAL in binary form (X.690 8.5.7)
+func (r *Reader) decodeBinaryReal(buffer []byte) (float64, error) {
+	first := buffer[0]
+
+	var baseShift int64 // log2 of the base
+	switch (first >> 4) & 0x03 {
+	case 0:
+		baseShift = 1
+	case 1:
+		baseShift = 3
+	case 2:
+		baseShift = 4
+	default:
+		return 0, errors.New("reserved REAL base")
+	}
+	scale := int64((first >> 2) & 0x03)
+
+	pos := 1
+	nExponentBytes := int(first&0x03) + 1
+	if nExponentBytes == 4 {
+		if len(buffer) < 2 {
+			return 0, errors.New("truncated REAL exponent")
+		}
+		nExponentBytes = int(buffer[1])
+		pos++
+	}
+	if nExponentBytes == 0 || nExponentBytes > 8 {
+		return 0, errors.New("REAL exponent length not supported")
+	}
+	if pos+nExponentBytes > len(buffer) {
+		return 0, errors.New("truncated REAL exponent")
+	}
+
+	exponentBytes := buffer[pos : pos+nExponentBytes]
+	var exponent int64
+	if exponentBytes[0]&0x80 == 0x80 {
+		exponent = -1 // sign extension
+	}
+	for _, aByte := range exponentBytes {
+		exponent = exponent<<8 | int64(aByte)
+	}
+	mantissaBytes := buffer[pos+nExponentBytes:]
+
+	if r.rules == DER {
+		switch {
+		case baseShift != 1 || scale != 0:
+			return 0, errors.New("REAL must use base 2 and no scaling factor in DER")
+		case len(exponentBytes) > 1 && isPaddedInteger(exponentBytes[0], exponentBytes[1]):
+			return 0, errors.New("REAL exponent encoded with leading padding byte not allowed in DER")
+		case first&0x03 == 0x03 && nExponentBytes <= 3:
+			return 0, errors.New("REAL exponent length encoded in long form not allowed in DER")
+		case len(mantissaBytes) == 0 || mantissaBytes[len(mantissaBytes)-1]&0x01 == 0:
+			return 0, errors.New("REAL mantissa must be odd in DER")
+		case mantissaBytes[0] == 0:
+			return 0, errors.New("REAL mantissa encoded with leading zero byte not allowed in DER")
+		}
+	}
+
+	mantissa := new(big.Float).SetInt(new(big.Int).SetBytes(mantissaBytes))
+	if mantissa.Sign() == 0 {
+		return 0, nil
+	}
+
+	// value is mantissa * 2^scale * base^exponent, stay well within big.Float exponent range
+	shift := scale
+	switch {
+	case exponent > math.MaxInt32/8:
+		shift = math.MaxInt16
+	case exponent < math.MinInt32/8:
+		shift = math.MinInt16
+	default:
+		shift += exponent * baseShift
+		if shift > math.MaxInt16 {
+			shift = math.MaxInt16
+		}
+		if shift < math.MinInt16 {
+			shift = math.MinInt16
+		}
+	}
+	mantissa.SetMantExp(mantissa, int(shift))
+
+	result, _ := mantissa.Float64()
+	if first&0x40 == 0x40 {
+		result = -result
+	}
+	return result, nil
+}
+
+// decodeDecimalReal decodes the contents of a REAL in decimal form (X.690 8.5.8)
+func (r *Reader) decodeDecimalReal(buffer []byte) (float64, error) {
+	form := buffer[0] & 0x3F
+	text := string(buffer[1:])
+
+	if r.rules == DER {
+		if form != realNR3 || !canonicalNR3.MatchString(text) {
+			return 0, errors.New("REAL decimal encoding must use canonical NR3 form in DER")
+		}
+	}
+
+	value := strings.TrimLeft(text, " ")
+	value = strings.Replace(value, ",", ".", 1)
+
+	var valid bool
+	switch form {
+	case realNR1:
+		valid = isISO6093(value, false, false)
+	case realNR2:
+		valid = isISO6093(value, true, false) && strings.Contains(value, ".")
+	case realNR3:
+		valid = isISO6093(value, true, true) && strings.ContainsAny(value, "eE")
+	default:
+		return 0, errors.New("invalid REAL decimal form")
+	}
+	if !valid {
+		return 0, errors.New("invalid REAL decimal value: " + text)
+	}
+
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, errors.New("invalid REAL decimal value: " + text)
+	}
+	return result, nil
+}
+
+// isISO6093 checks the characters of a ISO 6093 number (leading spaces removed and decimal mark normalized)
+func isISO6093(value string, hasDecimalMark bool, hasExponent bool) bool {
+	mantissa := value
+	if hasExponent {
+		i := strings.IndexAny(value, "eE")
+		if i < 0 {
+			return false
+		}
+		mantissa = value[:i]
+		if !isSignedDigits(value[i+1:], false) {
+			return false
+		}
+	}
+	return isSignedDigits(mantissa, hasDecimalMark)
+}
+
+// isSignedDigits checks a string is an optional sign followed by digits with an optional decimal mark
+func isSignedDigits(value string, hasDecimalMark bool) bool {
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		value = value[1:]
+	}
+	nDigits := 0
+	nDecimalMarks := 0
+	for _, c := range value {
+		switch {
+		case c >= '0' && c <= '9':
+			nDigits++
+		case c == '.' && hasDecimalMark:
+			nDecimalMarks++
+		default:
+			return false
+		}
+	}
+	return nDigits > 0 && nDecimalMarks <= 1
+}
+
+// canonicalNR3 matches REAL decimal values in the form required by DER (X.690 11.3.2)
+var canonicalNR3 = regexp.MustCompile(`^-?[1-9]([0-9]*[1-9])?\.E(\+0|-?[1-9][0-9]*)$`)
+
+// realToCanonicalNR3 formats a finite non zero float64 in the NR3 form required by DER, e.g. "15.E2", "-25.E-2", "1.E+0"
+func realToCanonicalNR3(value float64) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	// shortest representation d.ddde±xx
+	formatted := strconv.FormatFloat(value, 'e', -1, 64)
+	i := strings.IndexByte(formatted, 'e')
+	digits := strings.Replace(formatted[:i], ".", "", 1)
+	exponent, _ := strconv.Atoi(formatted[i+1:])
+	exponent -= len(digits) - 1
+
+	for len(digits) > 1 && digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		exponent++
+	}
+
+	exponentText := strconv.Itoa(exponent)
+	if exponent == 0 {
+		exponentText = "+0"
+	}
+
+	return sign + digits + ".E" + exponentText
+}
+
+// WriteReal encodes a float64 as a REAL to the buffer and return length of encoded data
+// finite values use the base 2 binary form with an odd mantissa, which is the DER canonical encoding (X.690 11.3.1)
+func (w *Writer) WriteReal(value float64) int {
+	if special, ok := realSpecialValue(value); ok {
+		if special == 0 {
+			return 0
+		}
+		return w.writeByte(special)
+	}
+
+	first := byte(0x80)
+	if value < 0 {
+		first |= 0x40
+		value = -value
+	}
+
+	fraction, exponent := math.Frexp(value)
+	mantissa := uint64(math.Ldexp(fraction, 53))
+	exponent -= 53
+	for mantissa&0x01 == 0 {
+		mantissa = mantissa >> 1
+		exponent++
+	}
+
+	nBytes := 0
+	for ; mantissa != 0; mantissa = mantissa >> 8 {
+		nBytes += w.writeByte(byte(mantissa))
+	}
+
+	nExponentBytes := w.WriteInteger64(int64(exponent))
+	nBytes += nExponentBytes
+	first |= byte(nExponentBytes - 1) // exponent is at most 2 bytes long
+
+	return nBytes + w.writeByte(first)
+}
+
+// WriteRealDecimal encodes a float64 as a REAL in decimal form to the buffer and return length of encoded data
+// finite values use the NR3 form required by DER (X.690 11.3.2)
+func (w *Writer) WriteRealDecimal(value float64) int {
+	if special, ok := realSpecialValue(value); ok {
+		if special == 0 {
+			return 0
+		}
+		return w.writeByte(special)
+	}
+
+	nBytes := w.WriteRestrictedCharacterString(realToCanonicalNR3(value))
+	return nBytes + w.writeByte(realNR3)
+}
+
+// realSpecialValue returns the first byte encoding a special REAL value (0 for plus zero which has no contents)
+func realSpecialValue(value float64) (byte, bool) {
+	switch {
+	case math.IsInf(value, 1):
+		return realPlusInfinity, true
+	case math.IsInf(value, -1):
+		return realMinusInfinity, true
+	case math.IsNaN(value):
+		return realNotANumber, true
+	case value == 0 && math.Signbit(value):
+		return realMinusZero, true
+	case value == 0:
+		return 0, true
+	}
+	return 0, false
+}
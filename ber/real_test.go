@@ -0,0 +1,214 @@
+package ber
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestWriteReal(t *testing.T) {
+	values := []float64{1, 0.5, -2.5, 1024, 0.1}
+	expected := [][]byte{
+		{0x80, 0x00, 0x01},
+		{0x80, 0xff, 0x01},
+		{0xc0, 0xff, 0x05},
+		{0x80, 0x0a, 0x01},
+		{0x80, 0xc9, 0x0c, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcd},
+	}
+
+	for i, value := range values {
+		writer := NewWriter(10)
+		encoded := writer.WriteReal(value)
+		if encoded != len(expected[i]) {
+			t.Fatal("Wrong length:", value)
+		}
+		if false == bytes.Equal(writer.GetDataBuffer(), expected[i]) {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestWriteRealSpecialValues(t *testing.T) {
+	values := []float64{math.Inf(1), math.Inf(-1), math.NaN(), math.Copysign(0, -1)}
+	expected := []byte{0x40, 0x41, 0x42, 0x43}
+
+	for i, value := range values {
+		writer := NewWriter(10)
+		encoded := writer.WriteReal(value)
+		if encoded != 1 {
+			t.Fatal("Should be 1")
+		}
+		if writer.GetDataBuffer()[0] != expected[i] {
+			t.Fatal("Wrong:", value)
+		}
+	}
+
+	writer := NewWriter(10)
+	encoded := writer.WriteReal(0)
+	if encoded != 0 {
+		t.Fatal("Should be 0")
+	}
+}
+
+func TestWriteRealDecimal(t *testing.T) {
+	values := []float64{1500, -0.25, 1, 123.456}
+	expected := []string{"15.E2", "-25.E-2", "1.E+0", "123456.E-3"}
+
+	for i, value := range values {
+		writer := NewWriter(10)
+		encoded := writer.WriteRealDecimal(value)
+		if encoded != len(expected[i])+1 {
+			t.Fatal("Wrong length:", value)
+		}
+		if false == bytes.Equal(writer.GetDataBuffer(), append([]byte{0x03}, expected[i]...)) {
+			t.Fatal("Wrong:", value, string(writer.GetDataBuffer()))
+		}
+	}
+}
+
+func TestReadReal(t *testing.T) {
+	encodings := [][]byte{
+		{0x80, 0x00, 0x01},
+		{0xc0, 0xff, 0x05},
+		{0x81, 0xff, 0xc9, 0x0c, 0xcc, 0xcc, 0xcc, 0xcc, 0xcc, 0xcd},
+		{0xa0, 0x01, 0x01},             // base 16
+		{0x94, 0x01, 0x03},             // base 8, scaling factor 1
+		{0x83, 0x01, 0x02, 0x01},       // exponent length in long form
+		{0x80, 0x00, 0x00, 0x00, 0x04}, // mantissa with leading zeros
+	}
+	expected := []float64{1, -2.5, 0.1, 16, 48, 4, 4}
+
+	for i, encoding := range encodings {
+		reader := NewReader(bytes.NewReader(encoding))
+		value, err := reader.ReadReal(len(encoding))
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if value != expected[i] {
+			t.Fatal("Wrong:", value, expected[i])
+		}
+	}
+}
+
+func TestReadRealDecimal(t *testing.T) {
+	encodings := []string{"\x01  -123", "\x02+1,5", "\x02.5", "\x0315.E2", "\x03-25e-2"}
+	expected := []float64{-123, 1.5, 0.5, 1500, -0.25}
+
+	for i, encoding := range encodings {
+		reader := NewReader(bytes.NewReader([]byte(encoding)))
+		value, err := reader.ReadReal(len(encoding))
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if value != expected[i] {
+			t.Fatal("Wrong:", value, expected[i])
+		}
+	}
+
+	invalid := []string{"\x011.5", "\x021", "\x03Inf", "\x04123", "\x02 1.5 "}
+
+	for _, encoding := range invalid {
+		reader := NewReader(bytes.NewReader([]byte(encoding)))
+		_, err := reader.ReadReal(len(encoding))
+		if err == nil {
+			t.Fatal("Wrong:", encoding)
+		}
+	}
+}
+
+func TestReadRealSpecialValues(t *testing.T) {
+	in := bytes.NewReader([]byte{0x40, 0x41, 0x42, 0x43, 0x44})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadReal(1)
+	if err != nil || !math.IsInf(value, 1) {
+		t.Fatal("Wrong")
+	}
+
+	value, err = reader.ReadReal(1)
+	if err != nil || !math.IsInf(value, -1) {
+		t.Fatal("Wrong")
+	}
+
+	value, err = reader.ReadReal(1)
+	if err != nil || !math.IsNaN(value) {
+		t.Fatal("Wrong")
+	}
+
+	value, err = reader.ReadReal(1)
+	if err != nil || value != 0 || !math.Signbit(value) {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.ReadReal(1)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	value, err = reader.ReadReal(0)
+	if err != nil || value != 0 || math.Signbit(value) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadReal(t *testing.T) {
+	invalid := [][]byte{
+		{0xa0, 0x01, 0x01},       // base 16
+		{0x84, 0x00, 0x01},       // scaling factor
+		{0x80, 0x00, 0x02},       // even mantissa
+		{0x81, 0x00, 0x01, 0x01}, // padded exponent
+		[]byte("\x0315.0E2"),
+		[]byte("\x02150.0"),
+	}
+
+	for _, encoding := range invalid {
+		reader := NewDERReader(bytes.NewReader(encoding))
+		_, err := reader.ReadReal(len(encoding))
+		if err == nil {
+			t.Fatal("Wrong:", encoding)
+		}
+	}
+
+	valid := [][]byte{
+		{0x80, 0xff, 0x01},
+		[]byte("\x0315.E2"),
+		[]byte("\x03-1.E+0"),
+	}
+
+	for _, encoding := range valid {
+		reader := NewDERReader(bytes.NewReader(encoding))
+		_, err := reader.ReadReal(len(encoding))
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+	}
+}
+
+func TestRealRoundTrip(t *testing.T) {
+	values := []float64{math.MaxFloat64, math.SmallestNonzeroFloat64, -1e-300, 6.02214076e23, math.Pi}
+
+	for _, value := range values {
+		writer := NewDERWriter(10)
+		encoded := writer.WriteReal(value)
+		reader := NewDERReader(bytes.NewReader(writer.GetDataBuffer()))
+		decoded, err := reader.ReadReal(encoded)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if decoded != value {
+			t.Fatal("Wrong:", value, decoded)
+		}
+
+		writer = NewDERWriter(10)
+		encoded = writer.WriteRealDecimal(value)
+		reader = NewDERReader(bytes.NewReader(writer.GetDataBuffer()))
+		decoded, err = reader.ReadReal(encoded)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if decoded != value {
+			t.Fatal("Wrong:", value, decoded)
+		}
+	}
+}
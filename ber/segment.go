@@ -0,0 +1,119 @@
+package ber
+
+import (
+	"errors"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// universal tags of the segments of constructed strings (X.690 8.6.4 and 8.7.3)
+const (
+	bitStringSegmentTag   = 0x03
+	octetStringSegmentTag = 0x04
+)
+
+// ReadConstructedOctetString reassembles the segments of a constructed OCTET STRING whose tag and length have been read (nBytes is -1 if length is indefinite)
+// raises an error if the last read tag is not constructed
+func (r *Reader) ReadConstructedOctetString(nBytes int) ([]byte, error) {
+	result := []byte{}
+
+	err := r.readSegments(int64(nBytes), octetStringSegmentTag, func(nSegmentBytes int) error {
+		buffer, err := r.readBytes(nSegmentBytes)
+		result = append(result, buffer...)
+		return err
+	})
+
+	return result, err
+}
+
+// ReadConstructedRestrictedCharacterString reassembles the segments of a constructed restricted character string (see ReadConstructedOctetString)
+func (r *Reader) ReadConstructedRestrictedCharacterString(nBytes int) (string, error) {
+	buffer, err := r.ReadConstructedOctetString(nBytes)
+	return string(buffer), err
+}
+
+// ReadConstructedBitString reassembles the segments of a constructed BIT STRING (see ReadConstructedOctetString), only the last segment may have padding bits
+func (r *Reader) ReadConstructedBitString(nBytes int) (types.BitString, error) {
+	result := types.BitString{Bytes: []byte{}}
+
+	err := r.readSegments(int64(nBytes), bitStringSegmentTag, func(nSegmentBytes int) error {
+		if result.Length%8 != 0 {
+			return errors.New("padding bits only allowed in the last segment of BIT STRING")
+		}
+		segment, err := r.ReadBitString(nSegmentBytes)
+		if err != nil {
+			return err
+		}
+		result.Bytes = append(result.Bytes, segment.Bytes...)
+		result.Length += segment.Length
+		return nil
+	})
+
+	return result, err
+}
+
+// readSegments reads the segments of a constructed string which has nBytes bytes (-1 if length is indefinite)
+// nested constructed segments are read recursively, readPrimitive is called for each primitive segment
+func (r *Reader) readSegments(nBytes int64, segmentTag byte, readPrimitive func(nSegmentBytes int) error) error {
+	if !r.isConstructed() {
+		return errors.New("last read tag is not constructed")
+	}
+	if r.rules == DER {
+		return errors.New("constructed string encoding not allowed in DER")
+	}
+
+	depth := len(r.constructedValues)
+	err := r.openConstructed(nBytes)
+	if err != nil {
+		return err
+	}
+	// the constructed value is closed even if reading fails, to leave the reader consistent
+	defer func() {
+		r.constructedValues = r.constructedValues[:depth]
+	}()
+
+	for {
+		more, err := r.NextComponent()
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+
+		if r.tagLength != 1 || r.tagBuffer[0]&^0x20 != segmentTag {
+			return errors.New("unexpected tag in segment of constructed string")
+		}
+
+		err = r.ReadLength()
+		if err != nil {
+			return err
+		}
+
+		if r.isConstructed() {
+			err = r.readSegments(r.lengthValue, segmentTag, readPrimitive)
+		} else {
+			if r.lengthValue < 0 {
+				return errors.New("indefinite length in primitive segment of constructed string")
+			}
+			err = readPrimitive(int(r.lengthValue))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.CloseConstructed()
+}
+
+// readEndOfContentsLength reads the second byte of an end-of-contents marker (X.690 8.1.5)
+func (r *Reader) readEndOfContentsLength() error {
+	aByte, err := r.readByte()
+	if err != nil {
+		return err
+	}
+	if aByte != 0x00 {
+		return errors.New("invalid end-of-contents")
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package ber
+
+import (
+	"bytes"
+	"testing"
+)
+
+func readTagAndLength(t *testing.T, reader *Reader) int {
+	err := reader.ReadTag()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = reader.ReadLength()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	return reader.GetLengthValue()
+}
+
+func TestReadConstructedOctetString(t *testing.T) {
+	in := bytes.NewReader([]byte{0x24, 0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x02, 0x03, 0x04, 0x04, 0x01, 0x05})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x01, 0x02, 0x03, 0x04}
+	if false == bytes.Equal(value, expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	// next value is primitive
+	value, err = reader.ReadOctetString(readTagAndLength(t, reader))
+
+	if err != nil || len(value) != 1 || value[0] != 0x05 {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadConstructedOctetStringIndefinite(t *testing.T) {
+	// implicitly tagged, nested segments with definite and indefinite lengths
+	in := bytes.NewReader([]byte{
+		0xa0, 0x80,
+		0x04, 0x01, 0x01,
+		0x24, 0x80, 0x04, 0x01, 0x02, 0x24, 0x03, 0x04, 0x01, 0x03, 0x00, 0x00,
+		0x04, 0x00,
+		0x00, 0x00,
+	})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x01, 0x02, 0x03}
+	if false == bytes.Equal(value, expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.readByte()
+
+	if err == nil {
+		t.Fatal("Wrong: all bytes should have been read")
+	}
+}
+
+func TestReadConstructedOctetStringErrors(t *testing.T) {
+	encodings := [][]byte{
+		{0x24, 0x03, 0x02, 0x01, 0x01},             // wrong segment tag
+		{0x24, 0x03, 0x04, 0x02, 0x01, 0x02},       // segment overruns
+		{0x24, 0x80, 0x04, 0x80, 0x01, 0x00, 0x00}, // indefinite primitive segment
+		{0x24, 0x80, 0x04, 0x01, 0x01, 0x00, 0x01}, // invalid end-of-contents
+		{0x24, 0x80, 0x04, 0x01, 0x01},             // missing end-of-contents
+	}
+
+	for _, encoding := range encodings {
+		reader := NewReader(bytes.NewReader(encoding))
+		_, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+		if err == nil {
+			t.Fatal("Wrong:", encoding)
+		}
+	}
+}
+
+func TestDERReadConstructedOctetString(t *testing.T) {
+	in := bytes.NewReader([]byte{0x24, 0x03, 0x04, 0x01, 0x01})
+
+	reader := NewDERReader(in)
+
+	_, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadConstructedBitString(t *testing.T) {
+	in := bytes.NewReader([]byte{0x23, 0x80, 0x03, 0x03, 0x00, 0x0a, 0x3b, 0x03, 0x05, 0x04, 0x5f, 0x29, 0x1c, 0xd0, 0x00, 0x00})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadConstructedBitString(readTagAndLength(t, reader))
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value.Length != 44 {
+		t.Fatal("Wrong:", value.Length)
+	}
+
+	expectedBuffer := [...]byte{0x0a, 0x3b, 0x5f, 0x29, 0x1c, 0xd0}
+	if false == bytes.Equal(value.Bytes, expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadConstructedBitStringPadding(t *testing.T) {
+	in := bytes.NewReader([]byte{0x23, 0x08, 0x03, 0x02, 0x04, 0x50, 0x03, 0x02, 0x00, 0x01})
+
+	reader := NewReader(in)
+
+	_, err := reader.ReadConstructedBitString(readTagAndLength(t, reader))
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadConstructedRestrictedCharacterString(t *testing.T) {
+	in := bytes.NewReader([]byte{0x3a, 0x09, 0x04, 0x03, 0x4a, 0x6f, 0x6e, 0x04, 0x02, 0x65, 0x73})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadConstructedRestrictedCharacterString(readTagAndLength(t, reader))
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != "Jones" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadConstructedNotConstructed(t *testing.T) {
+	in := bytes.NewReader([]byte{0x04, 0x01, 0x01})
+
+	reader := NewReader(in)
+
+	_, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadOctetStringReassembles(t *testing.T) {
+	// definite length segmented string read without checking the constructed bit
+	in := bytes.NewReader([]byte{0x24, 0x08, 0x04, 0x02, 0x01, 0x02, 0x04, 0x02, 0x03, 0x04, 0x23, 0x04, 0x03, 0x02, 0x04, 0xa0, 0x3a, 0x06, 0x04, 0x01, 'h', 0x04, 0x01, 'i'})
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadOctetString(readTagAndLength(t, reader))
+
+	expectedBuffer := [...]byte{0x01, 0x02, 0x03, 0x04}
+	if err != nil || false == bytes.Equal(value, expectedBuffer[0:]) {
+		t.Fatal("Wrong:", err)
+	}
+
+	bits, err := reader.ReadBitString(readTagAndLength(t, reader))
+
+	if err != nil || bits.Length != 4 || bits.Bytes[0] != 0xa0 {
+		t.Fatal("Wrong:", err)
+	}
+
+	text, err := reader.ReadRestrictedCharacterString(readTagAndLength(t, reader))
+
+	if err != nil || text != "hi" {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadConstructedOctetStringRecovery(t *testing.T) {
+	// wrong segment tag, then a SEQUENCE which would exceed the failed value if it had not been closed
+	in := bytes.NewReader([]byte{0x24, 0x04, 0x04, 0x01, 0x01, 0x05, 0x30, 0x03, 0x04, 0x01, 0x02})
+
+	reader := NewReader(in)
+
+	_, err := reader.ReadConstructedOctetString(readTagAndLength(t, reader))
+
+	if err == nil || len(reader.constructedValues) != 0 {
+		t.Fatal("Wrong")
+	}
+
+	readTagAndLength(t, reader)
+	err = reader.OpenConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	more, err := reader.NextComponent()
+	if !more || err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	reader.ReadLength()
+	value, err := reader.ReadOctetString(reader.GetLengthValue())
+	if err != nil || len(value) != 1 || value[0] != 0x02 {
+		t.Fatal("Wrong:", err)
+	}
+	more, err = reader.NextComponent()
+	if more || err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
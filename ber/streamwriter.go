@@ -0,0 +1,211 @@
+package ber
+
+import (
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// StreamWriter helps encode ASN.1 values forward, straight to a stream, with bounded memory
+// constructed values use the indefinite length form unless their length is provided by the caller
+// StreamWriter does not buffer: wrap the stream with a bufio.Writer for performance
+type StreamWriter struct {
+	// stream to write to
+	out io.Writer
+
+	// writer used to encode primitive values
+	scratch *Writer
+
+	// number of bytes written to the stream
+	offset int64
+
+	// offsets of the end of the constructed values being written (innermost last), -1 if length is indefinite
+	constructedEnds []int64
+}
+
+// NewStreamWriter creates a stream writer
+func NewStreamWriter(out io.Writer) *StreamWriter {
+	s := new(StreamWriter)
+	s.out = out
+	s.scratch = NewWriter(0)
+	return s
+}
+
+// GetOffset returns the number of bytes written so far
+func (s *StreamWriter) GetOffset() int64 {
+	return s.offset
+}
+
+// WriteRaw writes an encoding as is (e.g. the data buffer of a Writer)
+func (s *StreamWriter) WriteRaw(encoding []byte) error {
+	n, err := s.out.Write(encoding)
+	s.offset += int64(n)
+	return err
+}
+
+// WriteTag writes a tag
+func (s *StreamWriter) WriteTag(tag []byte) error {
+	return s.WriteRaw(tag)
+}
+
+// WriteLength writes a length in definite form
+func (s *StreamWriter) WriteLength(value int64) error {
+	if value < 0 {
+		return errors.New("negative length")
+	}
+	s.scratch.reset()
+	s.scratch.WriteLength64(uint64(value))
+	return s.WriteRaw(s.scratch.GetDataBuffer())
+}
+
+// BeginConstructed writes the tag of a constructed value and an indefinite length
+// components are then written and EndConstructed must be called
+func (s *StreamWriter) BeginConstructed(tag []byte) error {
+	err := s.WriteTag(tag)
+	if err != nil {
+		return err
+	}
+	err = s.WriteRaw([]byte{0x80})
+	if err != nil {
+		return err
+	}
+	s.constructedEnds = append(s.constructedEnds, -1)
+	return nil
+}
+
+// BeginConstructedWithLength writes the tag of a constructed value and its precomputed length (length of the contents)
+// components are then written and EndConstructed must be called
+func (s *StreamWriter) BeginConstructedWithLength(tag []byte, length int64) error {
+	err := s.WriteTag(tag)
+	if err != nil {
+		return err
+	}
+	err = s.WriteLength(length)
+	if err != nil {
+		return err
+	}
+	s.constructedEnds = append(s.constructedEnds, s.offset+length)
+	return nil
+}
+
+// EndConstructed ends the innermost constructed value
+// writes end-of-contents if length is indefinite, raises an error if precomputed length does not match the bytes written
+func (s *StreamWriter) EndConstructed() error {
+	if len(s.constructedEnds) == 0 {
+		return errors.New("no constructed value to end")
+	}
+	end := s.constructedEnds[len(s.constructedEnds)-1]
+	s.constructedEnds = s.constructedEnds[:len(s.constructedEnds)-1]
+
+	if end < 0 {
+		return s.WriteRaw([]byte{0x00, 0x00})
+	}
+	if s.offset != end {
+		return errors.New("precomputed length of constructed value does not match the bytes written")
+	}
+	return nil
+}
+
+// WriteOctetStringFrom writes tag, length and value of an OCTET STRING whose length bytes are copied from in
+func (s *StreamWriter) WriteOctetStringFrom(tag []byte, length int64, in io.Reader) error {
+	err := s.WriteTag(tag)
+	if err != nil {
+		return err
+	}
+	err = s.WriteLength(length)
+	if err != nil {
+		return err
+	}
+	n, err := io.CopyN(s.out, in, length)
+	s.offset += n
+	return err
+}
+
+// writePrimitive writes tag, length and the value encoded by encode
+func (s *StreamWriter) writePrimitive(tag []byte, encode func(w *Writer) int) error {
+	s.scratch.reset()
+	nBytes := encode(s.scratch)
+	s.scratch.WriteLength64(uint64(nBytes))
+	s.scratch.WriteOctetString(tag)
+	return s.WriteRaw(s.scratch.GetDataBuffer())
+}
+
+// WriteOctetString writes tag, length and value of an OCTET STRING
+func (s *StreamWriter) WriteOctetString(tag []byte, value []byte) error {
+	err := s.WriteTag(tag)
+	if err != nil {
+		return err
+	}
+	err = s.WriteLength(int64(len(value)))
+	if err != nil {
+		return err
+	}
+	return s.WriteRaw(value)
+}
+
+// WriteRestrictedCharacterString writes tag, length and value of a restricted character string
+func (s *StreamWriter) WriteRestrictedCharacterString(tag []byte, value string) error {
+	return s.WriteOctetString(tag, []byte(value))
+}
+
+// WriteBoolean writes tag, length and value of a BOOLEAN
+func (s *StreamWriter) WriteBoolean(tag []byte, value bool) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteBoolean(value) })
+}
+
+// WriteNull writes tag and length of a NULL
+func (s *StreamWriter) WriteNull(tag []byte) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteNull() })
+}
+
+// WriteInteger writes tag, length and value of an INTEGER
+func (s *StreamWriter) WriteInteger(tag []byte, value int) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteInteger(value) })
+}
+
+// WriteInteger64 writes tag, length and value of an INTEGER
+func (s *StreamWriter) WriteInteger64(tag []byte, value int64) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteInteger64(value) })
+}
+
+// WriteBigInteger writes tag, length and value of an INTEGER
+func (s *StreamWriter) WriteBigInteger(tag []byte, value *big.Int) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteBigInteger(value) })
+}
+
+// WriteEnumerated writes tag, length and value of an ENUMERATED
+func (s *StreamWriter) WriteEnumerated(tag []byte, value int) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteEnumerated(value) })
+}
+
+// WriteReal writes tag, length and value of a REAL
+func (s *StreamWriter) WriteReal(tag []byte, value float64) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteReal(value) })
+}
+
+// WriteBitString writes tag, length and value of a BIT STRING
+func (s *StreamWriter) WriteBitString(tag []byte, value types.BitString) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteBitString(value) })
+}
+
+// WriteObjectIdentifier writes tag, length and value of an OBJECT IDENTIFIER
+func (s *StreamWriter) WriteObjectIdentifier(tag []byte, value types.ObjectIdentifier) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteObjectIdentifier(value) })
+}
+
+// WriteRelativeOID writes tag, length and value of a RELATIVE-OID
+func (s *StreamWriter) WriteRelativeOID(tag []byte, value types.RelativeOID) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteRelativeOID(value) })
+}
+
+// WriteUTCTime writes tag, length and value of a UTCTime
+func (s *StreamWriter) WriteUTCTime(tag []byte, value types.UTCTime) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteUTCTime(value) })
+}
+
+// WriteGeneralizedTime writes tag, length and value of a GeneralizedTime
+func (s *StreamWriter) WriteGeneralizedTime(tag []byte, value types.GeneralizedTime) error {
+	return s.writePrimitive(tag, func(w *Writer) int { return w.WriteGeneralizedTime(value) })
+}
@@ -0,0 +1,138 @@
+package ber
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestStreamWriter(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	writer := NewStreamWriter(out)
+
+	err := writer.BeginConstructed([]byte{0x30})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteInteger([]byte{0x02}, 500)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.BeginConstructedWithLength([]byte{0xa0}, 5)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteBoolean([]byte{0x01}, true)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteNull([]byte{0x05})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.EndConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteObjectIdentifier([]byte{0x06}, types.ObjectIdentifier{1, 1, 40})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.EndConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{
+		0x30, 0x80,
+		0x02, 0x02, 0x01, 0xf4,
+		0xa0, 0x05, 0x01, 0x01, 0xff, 0x05, 0x00,
+		0x06, 0x02, 0x29, 0x28,
+		0x00, 0x00,
+	}
+	if false == bytes.Equal(out.Bytes(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+	if writer.GetOffset() != int64(len(expectedBuffer)) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestStreamWriterLengthMismatch(t *testing.T) {
+	writer := NewStreamWriter(new(bytes.Buffer))
+
+	writer.BeginConstructedWithLength([]byte{0x30}, 4)
+	writer.WriteBoolean([]byte{0x01}, true)
+
+	err := writer.EndConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	err = writer.EndConstructed()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestStreamWriterOctetStringFrom(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	writer := NewStreamWriter(out)
+
+	value := strings.Repeat("x", 300)
+	err := writer.WriteOctetStringFrom([]byte{0x04}, 300, strings.NewReader(value))
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedHeader := [...]byte{0x04, 0x82, 0x01, 0x2c}
+	if false == bytes.Equal(out.Bytes()[:4], expectedHeader[0:]) || out.String()[4:] != value {
+		t.Fatal("Wrong")
+	}
+
+	err = writer.WriteOctetStringFrom([]byte{0x04}, 10, strings.NewReader("short"))
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestStreamWriterReadBack(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	writer := NewStreamWriter(out)
+	writer.BeginConstructed([]byte{0x30})
+	writer.WriteRestrictedCharacterString([]byte{0x1a}, "Jones")
+	writer.WriteReal([]byte{0x09}, 0.5)
+	writer.EndConstructed()
+
+	reader := NewReader(bytes.NewReader(out.Bytes()))
+	readTagAndLength(t, reader)
+	reader.OpenConstructed()
+
+	more, _ := reader.NextComponent()
+	reader.ReadLength()
+	text, err := reader.ReadRestrictedCharacterString(reader.GetLengthValue())
+	if !more || err != nil || text != "Jones" {
+		t.Fatal("Wrong:", err)
+	}
+
+	more, _ = reader.NextComponent()
+	reader.ReadLength()
+	real, err := reader.ReadReal(reader.GetLengthValue())
+	if !more || err != nil || real != 0.5 {
+		t.Fatal("Wrong:", err)
+	}
+
+	more, _ = reader.NextComponent()
+	if more {
+		t.Fatal("Wrong")
+	}
+	err = reader.CloseConstructed()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
@@ -0,0 +1,90 @@
+package ber
+
+import (
+	"errors"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// maxTagLength is the length of the longest encoded tag: a number of 64 bits needs 10 subsequent octets
+const maxTagLength = 11
+
+// EncodeTag returns the identifier octets of a tag (X.690 8.1.2)
+func EncodeTag(tag types.Tag) []byte {
+	first := byte(tag.Class) << 6
+	if tag.Constructed {
+		first |= 0x20
+	}
+	if tag.Number < 0x1F {
+		return []byte{first | byte(tag.Number)}
+	}
+
+	// high tag number form: base 128, most significant group first, bit 8 set on all octets but the last
+	encoded := []byte{byte(tag.Number & 0x7F)}
+	for number := tag.Number >> 7; number != 0; number >>= 7 {
+		encoded = append([]byte{byte(number&0x7F) | 0x80}, encoded...)
+	}
+	return append([]byte{first | 0x1F}, encoded...)
+}
+
+// DecodeTag decodes the tag at the beginning of data and returns it with the number of octets it takes
+// raises an error if data is truncated, if the number is not encoded in the minimum number of octets or does not fit in 64 bits
+func DecodeTag(data []byte) (types.Tag, int, error) {
+	if len(data) == 0 {
+		return types.Tag{}, 0, errors.New("tag expected")
+	}
+
+	tag := types.Tag{
+		Class:       types.TagClass(data[0] >> 6),
+		Constructed: data[0]&0x20 == 0x20,
+		Number:      uint64(data[0] & 0x1F),
+	}
+	if tag.Number != 0x1F {
+		return tag, 1, nil
+	}
+
+	tag.Number = 0
+	for i := 1; i < len(data); i++ {
+		if i == 1 && data[i] == 0x80 {
+			return types.Tag{}, 0, errors.New("tag number encoded with leading zero bits")
+		}
+		if tag.Number > 0x1FFFFFFFFFFFFFF {
+			return types.Tag{}, 0, errors.New("tag number more than 64 bits not supported")
+		}
+		tag.Number = tag.Number<<7 | uint64(data[i]&0x7F)
+		if data[i]&0x80 == 0 {
+			if tag.Number < 0x1F {
+				return types.Tag{}, 0, errors.New("tag number encoded in high tag number form")
+			}
+			return tag, i + 1, nil
+		}
+	}
+	return types.Tag{}, 0, errors.New("truncated tag")
+}
+
+// WriteTag encodes a tag and return length of encoded data
+func (w *Writer) WriteTag(tag types.Tag) int {
+	return w.WriteOctetString(EncodeTag(tag))
+}
+
+// GetTag returns the last read tag
+func (r *Reader) GetTag() types.Tag {
+	tag, _, _ := DecodeTag(r.tagBuffer[:r.tagLength])
+	return tag
+}
+
+// IsTag returns true if tag has the class, form and number of the last read tag
+func (r *Reader) IsTag(tag types.Tag) bool {
+	r.tagMatched = r.tagLength != 0 && r.GetTag().Equal(tag)
+	return r.tagMatched
+}
+
+// IsOneOfTags returns true if one item of tags has the class, form and number of the last read tag
+func (r *Reader) IsOneOfTags(tags []types.Tag) bool {
+	for _, tag := range tags {
+		if r.tagLength != 0 && r.GetTag().Equal(tag) {
+			return true
+		}
+	}
+	return false
+}
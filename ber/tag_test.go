@@ -0,0 +1,101 @@
+package ber
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestEncodeTag(t *testing.T) {
+	if false == bytes.Equal(EncodeTag(types.Tag{Class: types.UniversalClass, Constructed: true, Number: 16}), []byte{0x30}) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(EncodeTag(types.Tag{Class: types.ApplicationClass, Number: 30}), []byte{0x5e}) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(EncodeTag(types.Tag{Class: types.ContextClass, Number: 31}), []byte{0x9f, 0x1f}) {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(EncodeTag(types.Tag{Class: types.PrivateClass, Constructed: true, Number: 200}), []byte{0xff, 0x81, 0x48}) {
+		t.Fatal("Wrong")
+	}
+	expected := []byte{0x1f, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}
+	if false == bytes.Equal(EncodeTag(types.Tag{Number: 0xffffffffffffffff}), expected) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDecodeTag(t *testing.T) {
+	tag, n, err := DecodeTag([]byte{0xff, 0x81, 0x48, 0x01})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if n != 3 || false == tag.Equal(types.Tag{Class: types.PrivateClass, Constructed: true, Number: 200}) {
+		t.Fatal("Wrong")
+	}
+
+	tag, n, err = DecodeTag([]byte{0x1f, 0x81, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if n != 11 || tag.Number != 0xffffffffffffffff {
+		t.Fatal("Wrong")
+	}
+
+	// truncated, leading zero bits, low number in high tag number form, more than 64 bits
+	invalids := [][]byte{
+		{},
+		{0x1f, 0x81},
+		{0x1f, 0x80, 0x01},
+		{0x1f, 0x1e},
+		{0x1f, 0x82, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	}
+	for _, invalid := range invalids {
+		_, _, err = DecodeTag(invalid)
+		if err == nil {
+			t.Fatal("Wrong")
+		}
+	}
+}
+
+func TestWriteTag(t *testing.T) {
+	writer := NewWriter(0)
+	length := writer.WriteTag(types.Tag{Class: types.ContextClass, Constructed: true, Number: 1})
+	length += writer.WriteTag(types.Tag{Class: types.ApplicationClass, Number: 100})
+	if false == bytes.Equal(writer.GetDataBuffer(), []byte{0x5f, 0x64, 0xa1}) || length != 3 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReaderTag(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0x7f, 0x64, 0x00}))
+	err := reader.ReadTag()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	tag := reader.GetTag()
+	if tag.String() != "[APPLICATION 100]" || false == tag.Constructed {
+		t.Fatal("Wrong")
+	}
+	if false == reader.IsTag(types.Tag{Class: types.ApplicationClass, Constructed: true, Number: 100}) {
+		t.Fatal("Wrong")
+	}
+	if reader.IsTag(types.Tag{Class: types.ApplicationClass, Number: 100}) {
+		t.Fatal("Wrong")
+	}
+	if false == reader.IsOneOfTags([]types.Tag{{Number: 4}, {Class: types.ApplicationClass, Constructed: true, Number: 100}}) {
+		t.Fatal("Wrong")
+	}
+	if reader.IsOneOfTags([]types.Tag{{Number: 4}}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadTagTooLong(t *testing.T) {
+	reader := NewReader(bytes.NewReader([]byte{0x1f, 0x81, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}))
+	err := reader.ReadTag()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,154 @@
+package ber
+
+import (
+	"errors"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// ReadUTCTime reads nBytes bytes from the dataBuffer to decode a UTCTime, raises an error if end of dataBuffer is reached or value is invalid
+// with DER, raises an error if the value is not in the form YYMMDDHHMMSSZ
+func (r *Reader) ReadUTCTime(nBytes int) (types.UTCTime, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.UTCTime(text)
+	_, err = value.Time()
+	if err != nil {
+		return "", err
+	}
+	if r.rules == DER && !value.IsDER() {
+		return "", errors.New("UTCTime must be in the form YYMMDDHHMMSSZ in DER")
+	}
+
+	return value, nil
+}
+
+// ReadGeneralizedTime reads nBytes bytes from the dataBuffer to decode a GeneralizedTime, raises an error if end of dataBuffer is reached or value is invalid
+// with DER, raises an error if the value is not in the form YYYYMMDDHHMMSS[.f]Z
+func (r *Reader) ReadGeneralizedTime(nBytes int) (types.GeneralizedTime, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.GeneralizedTime(text)
+	_, err = value.Time()
+	if err != nil {
+		return "", err
+	}
+	if r.rules == DER && !value.IsDER() {
+		return "", errors.New("GeneralizedTime must be in the form YYYYMMDDHHMMSS[.f]Z in DER")
+	}
+
+	return value, nil
+}
+
+// WriteUTCTime encodes a UTCTime to the buffer and return length of encoded data
+// with DER, the value is converted to the form YYMMDDHHMMSSZ (nothing is written if the value is invalid)
+func (w *Writer) WriteUTCTime(value types.UTCTime) int {
+	if w.rules != BER {
+		t, err := value.Time()
+		if err != nil {
+			return 0
+		}
+		value = types.NewUTCTime(t)
+	}
+	return w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteGeneralizedTime encodes a GeneralizedTime to the buffer and return length of encoded data
+// with DER, the value is converted to the form YYYYMMDDHHMMSS[.f]Z (nothing is written if the value is invalid)
+func (w *Writer) WriteGeneralizedTime(value types.GeneralizedTime) int {
+	if w.rules != BER {
+		t, err := value.Time()
+		if err != nil {
+			return 0
+		}
+		value = types.NewGeneralizedTime(t)
+	}
+	return w.WriteRestrictedCharacterString(string(value))
+}
+
+// ReadTime reads nBytes bytes from the dataBuffer to decode a TIME, raises an error if end of dataBuffer is reached
+// raises an error if the value does not match the property settings of the type
+func (r *Reader) ReadTime(nBytes int, properties types.TimeProperties) (types.Time, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.Time(text)
+	return value, value.Validate(properties)
+}
+
+// ReadDate reads nBytes bytes from the dataBuffer to decode a DATE, raises an error if end of dataBuffer is reached or value is invalid
+func (r *Reader) ReadDate(nBytes int) (types.Date, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.Date(text)
+	return value, value.Validate()
+}
+
+// ReadTimeOfDay reads nBytes bytes from the dataBuffer to decode a TIME-OF-DAY, raises an error if end of dataBuffer is reached or value is invalid
+func (r *Reader) ReadTimeOfDay(nBytes int) (types.TimeOfDay, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.TimeOfDay(text)
+	return value, value.Validate()
+}
+
+// ReadDateTime reads nBytes bytes from the dataBuffer to decode a DATE-TIME, raises an error if end of dataBuffer is reached or value is invalid
+func (r *Reader) ReadDateTime(nBytes int) (types.DateTime, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.DateTime(text)
+	return value, value.Validate()
+}
+
+// ReadDuration reads nBytes bytes from the dataBuffer to decode a DURATION, raises an error if end of dataBuffer is reached or value is invalid
+func (r *Reader) ReadDuration(nBytes int) (types.Duration, error) {
+	text, err := r.ReadRestrictedCharacterString(nBytes)
+	if err != nil {
+		return "", err
+	}
+
+	value := types.Duration(text)
+	return value, value.Validate()
+}
+
+// WriteTime encodes a TIME to the buffer and return length of encoded data (the value is not validated)
+func (w *Writer) WriteTime(value types.Time) int {
+	return w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteDate encodes a DATE to the buffer and return length of encoded data (the value is not validated)
+func (w *Writer) WriteDate(value types.Date) int {
+	return w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteTimeOfDay encodes a TIME-OF-DAY to the buffer and return length of encoded data (the value is not validated)
+func (w *Writer) WriteTimeOfDay(value types.TimeOfDay) int {
+	return w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteDateTime encodes a DATE-TIME to the buffer and return length of encoded data (the value is not validated)
+func (w *Writer) WriteDateTime(value types.DateTime) int {
+	return w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteDuration encodes a DURATION to the buffer and return length of encoded data (the value is not validated)
+func (w *Writer) WriteDuration(value types.Duration) int {
+	return w.WriteRestrictedCharacterString(string(value))
+}
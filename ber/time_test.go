@@ -0,0 +1,230 @@
+package ber
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestReadUTCTime(t *testing.T) {
+	in := bytes.NewReader([]byte("9912312359+0100"))
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadUTCTime(15)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != "9912312359+0100" {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte("9913312359Z"))
+
+	reader = NewReader(in)
+
+	_, err = reader.ReadUTCTime(11)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadUTCTime(t *testing.T) {
+	in := bytes.NewReader([]byte("9912312359+0100"))
+
+	reader := NewDERReader(in)
+
+	_, err := reader.ReadUTCTime(15)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	in = bytes.NewReader([]byte("991231235959Z"))
+
+	reader = NewDERReader(in)
+
+	_, err = reader.ReadUTCTime(13)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadGeneralizedTime(t *testing.T) {
+	in := bytes.NewReader([]byte("20231231235959,50-0130"))
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadGeneralizedTime(22)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != "20231231235959,50-0130" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERReadGeneralizedTime(t *testing.T) {
+	invalid := []string{"20231231235959.50Z", "20231231235959,5Z", "202312312359Z", "20231231235959", "20231231235959.0Z"}
+
+	for _, text := range invalid {
+		reader := NewDERReader(bytes.NewReader([]byte(text)))
+		_, err := reader.ReadGeneralizedTime(len(text))
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+
+	reader := NewDERReader(bytes.NewReader([]byte("20231231235959.5Z")))
+	_, err := reader.ReadGeneralizedTime(17)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestWriteUTCTime(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteUTCTime(types.UTCTime("9912312359+0100"))
+	if encoded != 15 {
+		t.Fatal("Should be 15")
+	}
+	if string(writer.GetDataBuffer()) != "9912312359+0100" {
+		t.Fatal("Wrong")
+	}
+
+	writer = NewDERWriter(10)
+
+	encoded = writer.WriteUTCTime(types.UTCTime("9912312359+0100"))
+	if encoded != 13 {
+		t.Fatal("Should be 13")
+	}
+	if string(writer.GetDataBuffer()) != "991231225900Z" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteGeneralizedTime(t *testing.T) {
+	writer := NewDERWriter(10)
+
+	var encoded = writer.WriteGeneralizedTime(types.GeneralizedTime("202312312359.5+0100"))
+	if encoded != 15 {
+		t.Fatal("Should be 15")
+	}
+	if string(writer.GetDataBuffer()) != "20231231225930Z" {
+		t.Fatal("Wrong")
+	}
+
+	writer = NewDERWriter(10)
+
+	encoded = writer.WriteGeneralizedTime(types.GeneralizedTime("invalid"))
+	if encoded != 0 {
+		t.Fatal("Should be 0")
+	}
+}
+
+func TestReadTime(t *testing.T) {
+	properties := types.TimeProperties{Basic: "Date", Date: "YW", Year: "Basic"}
+
+	in := bytes.NewReader([]byte("2020-W532020-W54"))
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadTime(8, properties)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != "2020-W53" {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.ReadTime(8, properties)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadDate(t *testing.T) {
+	in := bytes.NewReader([]byte("2024-02-292023-02-29"))
+
+	reader := NewReader(in)
+
+	value, err := reader.ReadDate(10)
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if value != "2024-02-29" {
+		t.Fatal("Wrong")
+	}
+
+	_, err = reader.ReadDate(10)
+
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadTimeOfDayDateTimeDuration(t *testing.T) {
+	in := bytes.NewReader([]byte("23:59:592023-12-31T23:59:59P1Y2M3DT4H5M6S"))
+
+	reader := NewReader(in)
+
+	timeOfDay, err := reader.ReadTimeOfDay(8)
+
+	if err != nil || timeOfDay != "23:59:59" {
+		t.Fatal("Wrong:", err)
+	}
+
+	dateTime, err := reader.ReadDateTime(19)
+
+	if err != nil || dateTime != "2023-12-31T23:59:59" {
+		t.Fatal("Wrong:", err)
+	}
+
+	duration, err := reader.ReadDuration(14)
+
+	if err != nil || duration != "P1Y2M3DT4H5M6S" {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestWriteDate(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteDate(types.Date("2023-12-31"))
+	if encoded != 10 {
+		t.Fatal("Should be 10")
+	}
+	if string(writer.GetDataBuffer()) != "2023-12-31" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteTimeTypes(t *testing.T) {
+	writer := NewWriter(10)
+
+	encoded := writer.WriteDuration(types.Duration("P2W"))
+	encoded += writer.WriteDateTime(types.DateTime("2023-12-31T23:59:59"))
+	encoded += writer.WriteTimeOfDay(types.TimeOfDay("23:59:59"))
+	encoded += writer.WriteTime(types.Time("2023-12-31/P1D"))
+
+	expected := "2023-12-31/P1D23:59:592023-12-31T23:59:59P2W"
+	if encoded != len(expected) {
+		t.Fatal("Wrong")
+	}
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,439 @@
+package ber
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Unmarshal decodes the BER encoding in data into val, which must be a non-nil pointer (see Marshal for the mapping of Go types)
+// raises an error if data holds more than one value
+func Unmarshal(data []byte, val interface{}) error {
+	return UnmarshalWithParams(data, val, "")
+}
+
+// UnmarshalWithParams decodes the BER encoding in data into val, with the options of an `asn1` tag applying to val itself (e.g. "choice" or "tag:1,application")
+func UnmarshalWithParams(data []byte, val interface{}, params string) error {
+	r := NewReader(bytes.NewReader(data))
+	err := r.ReadValue(val, params)
+	if err != nil {
+		return err
+	}
+	if r.GetOffset() != int64(len(data)) {
+		return errors.New("trailing data after the value")
+	}
+	return nil
+}
+
+// ReadValue reads tag, length and value of a value with the encoding rules of the reader and decodes it into val, which must be a non-nil pointer (see Marshal)
+// params are the options of an `asn1` tag applying to val itself
+func (r *Reader) ReadValue(val interface{}, params string) error {
+	p, err := parseFieldParameters(params)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("cannot decode into a nil or non-pointer value")
+	}
+
+	err = r.ReadTag()
+	if err != nil {
+		return err
+	}
+	if !r.matchesType(v.Elem().Type(), p) {
+		return errors.New("unexpected tag for " + v.Elem().Type().String())
+	}
+	return r.unmarshal(v.Elem(), p)
+}
+
+// matchesType returns true if the last read tag is the tag of a type (one of the tags of its alternatives if it is an untagged CHOICE)
+func (r *Reader) matchesType(t reflect.Type, params fieldParameters) bool {
+	if t.Kind() == reflect.Ptr && t != bigIntType {
+		t = t.Elem()
+	}
+	if params.hasTag {
+		return r.GetTag().Matches(types.Tag{Class: params.class, Number: params.tagNumber})
+	}
+	if params.choice {
+		alternatives, err := getChoiceAlternatives(t)
+		if err != nil {
+			return false
+		}
+		for _, alternative := range alternatives {
+			if r.matchesType(t.Field(alternative.index).Type, alternative.params) {
+				return true
+			}
+		}
+		return false
+	}
+	number, _, err := universalTag(t, params)
+	return err == nil && r.GetTag().Matches(types.Tag{Class: types.UniversalClass, Number: number})
+}
+
+// unmarshal decodes a value whose tag has been read (and matched)
+func (r *Reader) unmarshal(v reflect.Value, params fieldParameters) error {
+	if v.Kind() == reflect.Ptr && v.Type() != bigIntType {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	if !params.hasTag || !params.explicit && !params.choice {
+		return r.unmarshalUntagged(v, params)
+	}
+
+	// explicit tag (a tagged CHOICE is always explicit)
+	err := r.ReadLength()
+	if err != nil {
+		return err
+	}
+	err = r.OpenConstructed()
+	if err != nil {
+		return err
+	}
+	more, err := r.NextComponent()
+	if err != nil {
+		return err
+	}
+	params.hasTag = false
+	if !more || !r.matchesType(v.Type(), params) {
+		return errors.New("unexpected tag inside explicit tag of " + v.Type().String())
+	}
+	err = r.unmarshalUntagged(v, params)
+	if err != nil {
+		return err
+	}
+	more, err = r.NextComponent()
+	if err != nil {
+		return err
+	}
+	if more {
+		return errors.New("more than one value inside explicit tag of " + v.Type().String())
+	}
+	return r.CloseConstructed()
+}
+
+// unmarshalUntagged decodes a value whose tag has been read (and matched), without the explicit tag given in its parameters
+func (r *Reader) unmarshalUntagged(v reflect.Value, params fieldParameters) error {
+	if params.choice {
+		return r.unmarshalChoice(v)
+	}
+
+	err := r.ReadLength()
+	if err != nil {
+		return err
+	}
+
+	number, constructed, err := universalTag(v.Type(), params)
+	if err != nil {
+		return err
+	}
+	if constructed {
+		return r.unmarshalConstructed(v, params)
+	}
+
+	// strings may be constructed in BER, other types are always primitive
+	switch number {
+	case bitStringTag, octetStringTag, utf8StringTag, numericStringTag, printableStringTag, ia5StringTag, visibleStringTag:
+	default:
+		if r.isConstructed() {
+			return errors.New("constructed encoding of " + v.Type().String() + " not allowed")
+		}
+	}
+	if r.lengthValue < 0 && !r.isConstructed() {
+		return errors.New("indefinite length in primitive encoding")
+	}
+	if r.lengthValue > maxInt {
+		return errors.New("length value exceeds maximum length")
+	}
+	return r.unmarshalPrimitive(v, int(r.lengthValue))
+}
+
+// unmarshalChoice decodes the alternative of a CHOICE matching the last read tag
+func (r *Reader) unmarshalChoice(v reflect.Value) error {
+	alternatives, err := getChoiceAlternatives(v.Type())
+	if err != nil {
+		return err
+	}
+
+	v.Set(reflect.Zero(v.Type()))
+	for _, alternative := range alternatives {
+		field := v.Field(alternative.index)
+		if r.matchesType(field.Type(), alternative.params) {
+			return r.unmarshal(field, alternative.params)
+		}
+	}
+	return errors.New("unexpected tag for CHOICE " + v.Type().Name())
+}
+
+// unmarshalPrimitive decodes the nBytes bytes of contents of a value (nBytes is -1 for a constructed string with indefinite length)
+func (r *Reader) unmarshalPrimitive(v reflect.Value, nBytes int) error {
+	var value interface{}
+	var err error
+
+	switch v.Type() {
+	case bigIntType:
+		value, err = r.ReadBigInteger(nBytes)
+	case bitStringType:
+		value, err = r.ReadBitString(nBytes)
+	case objectIdentifierType:
+		value, err = r.ReadObjectIdentifier(nBytes)
+	case relativeOIDType:
+		value, err = r.ReadRelativeOID(nBytes)
+	case utcTimeType:
+		value, err = r.ReadUTCTime(nBytes)
+	case generalizedTimeType:
+		value, err = r.ReadGeneralizedTime(nBytes)
+	case timeType:
+		value, err = r.ReadTime(nBytes, types.TimeProperties{})
+	case dateType:
+		value, err = r.ReadDate(nBytes)
+	case timeOfDayType:
+		value, err = r.ReadTimeOfDay(nBytes)
+	case dateTimeType:
+		value, err = r.ReadDateTime(nBytes)
+	case durationType:
+		value, err = r.ReadDuration(nBytes)
+	}
+	if value != nil || err != nil {
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if nBytes != 1 {
+			return errors.New("BOOLEAN value must have a length of 1")
+		}
+		value, err := r.ReadBoolean()
+		v.SetBool(value)
+		return err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := r.ReadInteger64(nBytes)
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(value) {
+			return errors.New("INTEGER " + strconv.FormatInt(value, 10) + " overflows " + v.Type().String())
+		}
+		v.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := r.ReadUnsignedInteger64(nBytes)
+		if err != nil {
+			return err
+		}
+		if v.OverflowUint(value) {
+			return errors.New("INTEGER " + strconv.FormatUint(value, 10) + " overflows " + v.Type().String())
+		}
+		v.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := r.ReadReal(nBytes)
+		v.SetFloat(value)
+		return err
+	case reflect.String:
+		value, err := r.ReadRestrictedCharacterString(nBytes)
+		v.SetString(value)
+		return err
+	case reflect.Slice:
+		value, err := r.ReadOctetString(nBytes)
+		v.SetBytes(value)
+		return err
+	default:
+		return errors.New("type " + v.Type().String() + " is not supported")
+	}
+	return nil
+}
+
+// unmarshalConstructed decodes the components of a SEQUENCE, SET, SEQUENCE OF or SET OF
+func (r *Reader) unmarshalConstructed(v reflect.Value, params fieldParameters) error {
+	err := r.OpenConstructed()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case v.Kind() == reflect.Slice:
+		err = r.unmarshalSequenceOf(v, params)
+	case params.set:
+		err = r.unmarshalSet(v)
+	default:
+		err = r.unmarshalSequence(v)
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.CloseConstructed()
+}
+
+// unmarshalSequenceOf decodes the components of a SEQUENCE OF or SET OF
+// with DER, raises an error if the components of a SET OF are not sorted
+func (r *Reader) unmarshalSequenceOf(v reflect.Value, params fieldParameters) error {
+	if params.set {
+		r.BeginSetOf()
+	}
+
+	elementType := v.Type().Elem()
+	elementParams := elementParameters(params)
+	values := reflect.MakeSlice(v.Type(), 0, 0)
+	for {
+		more, err := r.NextComponent()
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+		if !r.matchesType(elementType, elementParams) {
+			return errors.New("unexpected tag in SEQUENCE OF " + elementType.String())
+		}
+		element := reflect.New(elementType).Elem()
+		err = r.unmarshal(element, elementParams)
+		if err != nil {
+			return err
+		}
+		values = reflect.Append(values, element)
+	}
+	v.Set(values)
+
+	if params.set {
+		return r.EndSetOf()
+	}
+	return nil
+}
+
+// unmarshalSequence decodes the components of a SEQUENCE, absent optional components are set to their zero value and absent components with a default value to this value
+// with DER, raises an error if a component is encoded with its default value
+func (r *Reader) unmarshalSequence(v reflect.Value) error {
+	fields, err := getStructFields(v.Type())
+	if err != nil {
+		return err
+	}
+
+	more, err := r.NextComponent()
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		value := v.Field(field.index)
+		if more && r.matchesType(value.Type(), field.params) {
+			err = r.unmarshalComponent(v, field)
+			if err != nil {
+				return err
+			}
+			more, err = r.NextComponent()
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err = setAbsentComponent(v, field)
+		if err != nil {
+			return err
+		}
+	}
+	if more {
+		return errors.New("unexpected component in SEQUENCE " + v.Type().Name())
+	}
+	return nil
+}
+
+// unmarshalSet decodes the components of a SET, in any order
+// with DER, raises an error if the components are not sorted by tag or if a component is encoded with its default value
+func (r *Reader) unmarshalSet(v reflect.Value) error {
+	fields, err := getStructFields(v.Type())
+	if err != nil {
+		return err
+	}
+
+	decoded := make([]bool, len(fields))
+	var previousTag *types.Tag
+	for {
+		more, err := r.NextComponent()
+		if err != nil {
+			return err
+		}
+		if !more {
+			break
+		}
+
+		tag := r.GetTag()
+		if r.rules == DER && previousTag != nil && previousTag.Compare(tag) >= 0 {
+			return errors.New("SET components not sorted in DER")
+		}
+		previousTag = &tag
+
+		found := false
+		for i, field := range fields {
+			if decoded[i] || !r.matchesType(v.Field(field.index).Type(), field.params) {
+				continue
+			}
+			err = r.unmarshalComponent(v, field)
+			if err != nil {
+				return err
+			}
+			decoded[i] = true
+			found = true
+			break
+		}
+		if !found {
+			return errors.New("unexpected component in SET " + v.Type().Name())
+		}
+	}
+
+	for i, field := range fields {
+		if !decoded[i] {
+			err = setAbsentComponent(v, field)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unmarshalComponent decodes a component of a SEQUENCE or SET whose tag has been read
+func (r *Reader) unmarshalComponent(v reflect.Value, field structField) error {
+	value := v.Field(field.index)
+	err := r.unmarshal(value, field.params)
+	if err != nil {
+		return errors.New(v.Type().Name() + "." + field.name + ": " + err.Error())
+	}
+	if r.rules == DER && isDefaultValue(value, field.params) {
+		return errors.New(v.Type().Name() + "." + field.name + ": component equal to its default value not allowed in DER")
+	}
+	return nil
+}
+
+// setAbsentComponent sets an absent component of a SEQUENCE or SET to its default value or to its zero value if it is optional
+// raises an error if the component is mandatory
+func setAbsentComponent(v reflect.Value, field structField) error {
+	value := v.Field(field.index)
+	switch {
+	case field.params.hasDefault:
+		switch value.Kind() {
+		case reflect.Bool:
+			value.SetBool(field.params.defaultInt != 0)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value.SetInt(field.params.defaultInt)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value.SetUint(uint64(field.params.defaultInt))
+		default:
+			return errors.New(v.Type().Name() + "." + field.name + ": default value only supported for INTEGER and BOOLEAN")
+		}
+	case field.params.optional:
+		value.Set(reflect.Zero(value.Type()))
+	default:
+		return errors.New("missing component " + v.Type().Name() + "." + field.name)
+	}
+	return nil
+}
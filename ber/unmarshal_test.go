@@ -0,0 +1,222 @@
+package ber
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+type unmarshalTimes struct {
+	UTC         types.UTCTime
+	Generalized types.GeneralizedTime
+	Date        types.Date
+	Duration    types.Duration
+}
+
+func TestUnmarshalSimple(t *testing.T) {
+	var value marshalSimple
+	err := Unmarshal([]byte{0x30, 0x06, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff}, &value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if value.A != 5 || value.B != true {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalTagged(t *testing.T) {
+	number := 3
+	value := marshalTagged{
+		Version:  1,
+		Serial:   big.NewInt(256),
+		Name:     "ab",
+		Flags:    types.BitString{Bytes: []byte{0x80}, Length: 1},
+		Oid:      types.ObjectIdentifier{1, 2},
+		Value:    marshalChoice{Number: &number},
+		Elements: []int{2, 1},
+	}
+	data, err := Marshal(value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	var decoded marshalTagged
+	err = Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if decoded.Version != 1 || decoded.Serial.Int64() != 256 || decoded.Name != "ab" || decoded.Comment != nil {
+		t.Fatal("Wrong")
+	}
+	if decoded.Flags.Length != 1 || false == decoded.Flags.Get(0) || len(decoded.Oid) != 2 || decoded.Oid[1] != 2 {
+		t.Fatal("Wrong")
+	}
+	if decoded.Value.Number == nil || *decoded.Value.Number != 3 || decoded.Value.Text != nil {
+		t.Fatal("Wrong")
+	}
+	if len(decoded.Elements) != 2 || decoded.Elements[0] != 1 || decoded.Elements[1] != 2 {
+		t.Fatal("Wrong")
+	}
+
+	comment := "c"
+	value.Version = 2
+	value.Comment = &comment
+	data, err = Marshal(value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if decoded.Version != 2 || decoded.Comment == nil || *decoded.Comment != "c" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalIndefiniteLength(t *testing.T) {
+	var value marshalSimple
+	err := Unmarshal([]byte{0x30, 0x80, 0x02, 0x01, 0x05, 0x01, 0x01, 0x01, 0x00, 0x00}, &value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if value.A != 5 || value.B != true {
+		t.Fatal("Wrong")
+	}
+
+	var text string
+	err = Unmarshal([]byte{0x2c, 0x80, 0x04, 0x01, 'a', 0x04, 0x01, 'b', 0x00, 0x00}, &text)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if text != "ab" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalSet(t *testing.T) {
+	var value marshalSet
+	err := UnmarshalWithParams([]byte{0x31, 0x06, 0x81, 0x01, 'x', 0x80, 0x01, 0x01}, &value, "set")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if value.A != 1 || value.B != "x" {
+		t.Fatal("Wrong")
+	}
+
+	// components not sorted
+	reader := NewDERReader(bytes.NewReader([]byte{0x31, 0x06, 0x81, 0x01, 'x', 0x80, 0x01, 0x01}))
+	err = reader.ReadValue(&value, "set")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// missing component
+	err = UnmarshalWithParams([]byte{0x31, 0x03, 0x80, 0x01, 0x01}, &value, "set")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalTopLevelChoice(t *testing.T) {
+	var value marshalChoice
+	err := UnmarshalWithParams([]byte{0x63, 0x04, 0x81, 0x02, 'h', 'i'}, &value, "choice,tag:3,application")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if value.Number != nil || value.Text == nil || *value.Text != "hi" {
+		t.Fatal("Wrong")
+	}
+
+	err = UnmarshalWithParams([]byte{0x82, 0x01, 0x00}, &value, "choice")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalTimes(t *testing.T) {
+	value := unmarshalTimes{
+		UTC:         "910506234540Z",
+		Generalized: "19920521000000Z",
+		Date:        "2024-02-29",
+		Duration:    "P1Y2M",
+	}
+	data, err := Marshal(value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	var decoded unmarshalTimes
+	err = Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if decoded != value {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalDERDefault(t *testing.T) {
+	// Version encoded with its default value
+	data := []byte{
+		0x30, 0x1f,
+		0xa0, 0x03, 0x02, 0x01, 0x01,
+		0x81, 0x02, 0x01, 0x00,
+		0x13, 0x02, 'a', 'b',
+		0x03, 0x02, 0x07, 0x80,
+		0x06, 0x01, 0x2a,
+		0x80, 0x01, 0x03,
+		0x31, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02,
+	}
+	var value marshalTagged
+	err := Unmarshal(data, &value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	reader := NewDERReader(bytes.NewReader(data))
+	err = reader.ReadValue(&value, "")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var value marshalSimple
+	err := Unmarshal([]byte{0x30, 0x06, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff}, value)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// trailing data
+	err = Unmarshal([]byte{0x30, 0x06, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff, 0x00}, &value)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// unexpected component
+	err = Unmarshal([]byte{0x30, 0x09, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff, 0x02, 0x01, 0x00}, &value)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// wrong tag
+	err = Unmarshal([]byte{0x31, 0x06, 0x02, 0x01, 0x05, 0x01, 0x01, 0xff}, &value)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// overflow
+	var small int8
+	err = Unmarshal([]byte{0x02, 0x02, 0x01, 0x00}, &small)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// constructed INTEGER
+	var number int
+	err = Unmarshal([]byte{0x22, 0x03, 0x02, 0x01, 0x00}, &number)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
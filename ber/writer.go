@@ -1,11 +1,32 @@
 package ber
 
 import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/yafred/asn1-go/internal/octets"
 	"github.com/yafred/asn1-go/types"
 )
 
+// EncodingRules identifies the flavour of BER produced by a Writer or accepted by a Reader
+type EncodingRules int
+
+const (
+	// BER is the Basic Encoding Rules (X.690 clause 8)
+	BER EncodingRules = iota
+	// DER is the Distinguished Encoding Rules (X.690 clause 10)
+	DER
+	// CER is the Canonical Encoding Rules (X.690 clause 9)
+	CER
+)
+
 // writer helps encode ASN.1 values
 type Writer struct {
+	// encoding rules enforced by the writer
+	rules EncodingRules
+
 	// size of the encoded data sitting (at the end) in the dataBuffer
 	dataSize int
 
@@ -26,11 +47,35 @@ func NewWriter(dataBufferIncrement int) *Writer {
 	return w
 }
 
+// NewDERWriter creates a writer producing DER encodings
+func NewDERWriter(dataBufferIncrement int) *Writer {
+	w := NewWriter(dataBufferIncrement)
+	w.rules = DER
+	return w
+}
+
+// NewCERWriter creates a writer producing CER encodings
+func NewCERWriter(dataBufferIncrement int) *Writer {
+	w := NewWriter(dataBufferIncrement)
+	w.rules = CER
+	return w
+}
+
+// GetEncodingRules returns the encoding rules enforced by the writer
+func (w *Writer) GetEncodingRules() EncodingRules {
+	return w.rules
+}
+
 func (w *Writer) GetDataBuffer() []byte {
 	var bufferPosition = len(w.dataBuffer) - w.dataSize
 	return w.dataBuffer[bufferPosition:]
 }
 
+// reset discards the encoded data
+func (w *Writer) reset() {
+	w.dataSize = 0
+}
+
 // WriteOctetString encodes a []byte to the buffer and return length of encoded data
 func (w *Writer) WriteOctetString(value []byte) int {
 	w.increaseDataSize(len(value))
@@ -59,32 +104,27 @@ func (w *Writer) WriteRestrictedCharacterString(value string) int {
 	return len(valueAsBytes)
 }
 
+// WriteNull encodes a NULL to the buffer and return length of encoded data (always 0 in this case)
+func (w *Writer) WriteNull() int {
+	return 0
+}
+
+// WriteEnumerated encodes an ENUMERATED value to the buffer and return length of encoded data
+func (w *Writer) WriteEnumerated(value int) int {
+	return w.WriteInteger(value)
+}
+
 // WriteInteger encodes an integer to the buffer and return length of encoded data
 func (w *Writer) WriteInteger(value int) int {
-	var nBytes int // bytes needed to write integer
-
-	if value >= 0 {
-		switch {
-		case value < 0x80:
-			nBytes = 1
-		case value < 0x8000:
-			nBytes = 2
-		case value < 0x800000:
-			nBytes = 3
-		default:
-			nBytes = 4
-		}
-	} else {
-		switch {
-		case value >= 0x80*-1:
-			nBytes = 1
-		case value >= 0x8000*-1:
-			nBytes = 2
-		case value >= 0x800000*-1:
-			nBytes = 3
-		default:
-			nBytes = 4
-		}
+	return w.WriteInteger64(int64(value))
+}
+
+// WriteInteger64 encodes an int64 to the buffer and return length of encoded data
+func (w *Writer) WriteInteger64(value int64) int {
+	nBytes := 1 // bytes needed to write integer
+
+	for rest := value >> 7; rest != 0 && rest != -1; rest = rest >> 8 {
+		nBytes++
 	}
 
 	w.increaseDataSize(nBytes)
@@ -100,28 +140,63 @@ func (w *Writer) WriteInteger(value int) int {
 	return nBytes
 }
 
-// WriteBitString encodes a BitString struct to the buffer and return length of encoded data
-func (w *Writer) WriteBitString(value types.BitString) int {
-	var nBytes int
+// WriteUnsignedInteger64 encodes a uint64 to the buffer and return length of encoded data
+func (w *Writer) WriteUnsignedInteger64(value uint64) int {
+	if value <= math.MaxInt64 {
+		return w.WriteInteger64(int64(value))
+	}
 
-	if value.Length > 0 && value.Bytes != nil && len(value.Bytes) != 0 {
-		length := value.Length
-		bytes := value.Bytes
-		if length > 8*len(bytes) {
-			length = 8 * len(bytes)
-		}
+	// top bit is set, a leading zero byte is needed
+	for i := 0; i < 8; i++ {
+		w.writeByte(byte(value))
+		value = value >> 8
+	}
+	return 8 + w.writeByte(0)
+}
 
-		padding := length % 8
-		if padding != 0 {
-			padding = 8 - padding
+// WriteBigInteger encodes an INTEGER of any size to the buffer and return length of encoded data
+func (w *Writer) WriteBigInteger(value *big.Int) int {
+	if value.Sign() >= 0 {
+		nBytes := w.WriteOctetString(value.Bytes())
+		if nBytes == 0 || w.dataBuffer[len(w.dataBuffer)-w.dataSize]&0x80 == 0x80 {
+			nBytes += w.writeByte(0)
 		}
+		return nBytes
+	}
 
-		nBytes += w.WriteOctetString(bytes)
-		nBytes += w.writeByte(byte(padding))
+	// negative number: bytes are ^(-value - 1)
+	complement := new(big.Int).Neg(value)
+	complement.Sub(complement, big.NewInt(1))
+	nBytes := w.WriteOctetString(complement.Bytes())
+	beginPos := len(w.dataBuffer) - w.dataSize
+	for i := beginPos; i < beginPos+nBytes; i++ {
+		w.dataBuffer[i] ^= 0xff
+	}
+	if nBytes == 0 || w.dataBuffer[beginPos]&0x80 == 0 {
+		nBytes += w.writeByte(0xff)
 	}
 	return nBytes
 }
 
+// WriteBitString encodes a BitString struct to the buffer and return length of encoded data
+// unused bits of the last byte are always written as zeros
+func (w *Writer) WriteBitString(value types.BitString) int {
+	length := value.ValidLength()
+
+	nValueBytes := (length + 7) / 8
+	padding := nValueBytes*8 - length
+
+	var nBytes int
+	if nValueBytes > 0 {
+		nBytes += w.WriteOctetString(value.Bytes[:nValueBytes])
+		lastBytePosition := len(w.dataBuffer) - w.dataSize + nValueBytes - 1
+		w.dataBuffer[lastBytePosition] &= byte(0xff << uint(padding))
+	}
+	nBytes += w.writeByte(byte(padding))
+
+	return nBytes
+}
+
 // WriteRelativeOID encodes a RelativeOID struct to the buffer and return length of encoded data
 func (w *Writer) WriteRelativeOID(value types.RelativeOID) int {
 	if len(value) == 0 {
@@ -217,37 +292,54 @@ func (w *Writer) WriteObjectIdentifier(value types.ObjectIdentifier) int {
 
 // WriteLength encodes a length in definite form  and return length of encoded data
 func (w *Writer) WriteLength(value uint32) uint32 {
-	var nBytes uint32 = 1
+	return uint32(w.WriteLength64(uint64(value)))
+}
 
-	switch {
-	case value > 0xFFFFFF:
-		nBytes = 5
-	case value > 0xFFFF:
-		nBytes = 4
-	case value > 0xFF:
-		nBytes = 3
-	case value > 0x7F:
-		nBytes = 2
+// WriteLength64 encodes a 64-bit length in definite form and return length of encoded data
+func (w *Writer) WriteLength64(value uint64) int {
+	if value < 0x80 {
+		return w.writeByte(byte(value))
 	}
 
-	var nShift uint
-	for i := nBytes; i > 1; {
-		aByte := byte(value >> nShift)
-		w.writeByte(aByte)
-		i = i - 1
-		nShift = nShift + 8
+	nBytes := 0
+	for ; value != 0; value = value >> 8 {
+		nBytes += w.writeByte(byte(value))
 	}
 
-	// first byte is either number of subsequent bytes or the length itself
-	firstByte := byte(value)
-	if nBytes > 1 {
-		firstByte = byte(nBytes-1) | 0x80
-	}
-	w.writeByte(firstByte)
+	// first byte is the number of subsequent bytes
+	nBytes += w.writeByte(byte(nBytes) | 0x80)
 
 	return nBytes
 }
 
+// WriteConstructedLength encodes the length of a constructed value whose contents are the last nBytes written and return length of encoded data
+// with CER, the length is indefinite and end-of-contents is inserted after the contents, otherwise the length is definite
+func (w *Writer) WriteConstructedLength(nBytes int) int {
+	if w.rules != CER {
+		return w.WriteLength64(uint64(nBytes))
+	}
+
+	w.increaseDataSize(2)
+	beginPos := len(w.dataBuffer) - w.dataSize
+	copy(w.dataBuffer[beginPos:], w.dataBuffer[beginPos+2:beginPos+2+nBytes])
+	w.dataBuffer[beginPos+nBytes] = 0x00
+	w.dataBuffer[beginPos+nBytes+1] = 0x00
+
+	return 2 + w.WriteIndefiniteLength()
+}
+
+// WriteIndefiniteLength encodes a length in indefinite form and return length of encoded data (always 1 in this case)
+func (w *Writer) WriteIndefiniteLength() int {
+	return w.writeByte(0x80)
+}
+
+// WriteEndOfContents encodes an end-of-contents marker and return length of encoded data (always 2 in this case)
+// as encoding is done backwards, it must be written before the contents of the constructed value
+func (w *Writer) WriteEndOfContents() int {
+	w.writeByte(0x00)
+	return 1 + w.writeByte(0x00)
+}
+
 // WriteByte writes a byte to the buffer and return length of encoded data (always 1 in this case)
 func (w *Writer) writeByte(value byte) int {
 	w.increaseDataSize(1)
@@ -270,3 +362,129 @@ func (w *Writer) increaseDataSize(nBytes int) {
 	}
 	w.dataSize += nBytes
 }
+
+// SortSetComponents reorders the components of a SET (the last nBytes written) by ascending tag
+// this is only done with DER and CER, raises an error if the components cannot be parsed
+func (w *Writer) SortSetComponents(nBytes int) error {
+	if w.rules == BER {
+		return nil
+	}
+	return w.sortComponents(nBytes, func(a []byte, b []byte) bool {
+		return compareEncodedTags(a, b) < 0
+	})
+}
+
+// SortSetOfComponents reorders the components of a SET OF (the last nBytes written) by ascending encoding
+// this is only done with DER and CER, raises an error if the components cannot be parsed
+func (w *Writer) SortSetOfComponents(nBytes int) error {
+	if w.rules == BER {
+		return nil
+	}
+	return w.sortComponents(nBytes, func(a []byte, b []byte) bool {
+		return octets.CompareSetOfEncodings(a, b) < 0
+	})
+}
+
+// sortComponents splits the last nBytes written into TLVs, sorts them and writes them back in place
+func (w *Writer) sortComponents(nBytes int, less func(a []byte, b []byte) bool) error {
+	if nBytes < 0 || nBytes > w.dataSize {
+		return errors.New("invalid number of bytes to sort")
+	}
+
+	beginPos := len(w.dataBuffer) - w.dataSize
+	data := w.dataBuffer[beginPos : beginPos+nBytes]
+
+	components, err := splitEncodings(data)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return less(components[i], components[j])
+	})
+
+	sorted := make([]byte, 0, nBytes)
+	for _, component := range components {
+		sorted = append(sorted, component...)
+	}
+	copy(data, sorted)
+
+	return nil
+}
+
+// splitEncodings splits a buffer into consecutive TLV encodings
+func splitEncodings(data []byte) ([][]byte, error) {
+	var components [][]byte
+	for len(data) != 0 {
+		n, err := encodingLength(data)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, data[:n])
+		data = data[n:]
+	}
+	return components, nil
+}
+
+// encodingLength returns the number of bytes of the TLV encoding at the beginning of a buffer
+func encodingLength(data []byte) (int, error) {
+	// tag
+	tag, pos, err := DecodeTag(data)
+	if err != nil {
+		return 0, err
+	}
+	if pos >= len(data) {
+		return 0, errors.New("truncated length")
+	}
+
+	// length
+	first := data[pos]
+	pos++
+	if first == 0x80 {
+		if !tag.Constructed {
+			return 0, errors.New("indefinite length in primitive encoding")
+		}
+		for {
+			if pos+1 < len(data) && data[pos] == 0 && data[pos+1] == 0 {
+				return pos + 2, nil
+			}
+			if pos >= len(data) {
+				return 0, errors.New("missing end-of-contents")
+			}
+			n, err := encodingLength(data[pos:])
+			if err != nil {
+				return 0, err
+			}
+			pos += n
+		}
+	}
+
+	length := uint64(first)
+	if first > 0x7f {
+		nLengthBytes := int(first & 0x7f)
+		if pos+nLengthBytes > len(data) {
+			return 0, errors.New("invalid length")
+		}
+		length = 0
+		for i := 0; i < nLengthBytes; i++ {
+			if length > uint64(len(data)) {
+				return 0, errors.New("truncated value")
+			}
+			length = length<<8 | uint64(data[pos])
+			pos++
+		}
+	}
+
+	if length > uint64(len(data)-pos) {
+		return 0, errors.New("truncated value")
+	}
+
+	return pos + int(length), nil
+}
+
+// compareEncodedTags compares the tags of 2 encodings in canonical order (class, then number)
+func compareEncodedTags(a []byte, b []byte) int {
+	tagA, _, _ := DecodeTag(a)
+	tagB, _, _ := DecodeTag(b)
+	return tagA.Compare(tagB)
+}
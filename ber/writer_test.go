@@ -2,6 +2,7 @@ package ber
 
 import (
 	"bytes"
+	"math/big"
 	"testing"
 
 	"github.com/yafred/asn1-go/types"
@@ -397,3 +398,221 @@ func TestWriteObjectIdentifier7(t *testing.T) {
 		t.Fatal("Wrong")
 	}
 }
+
+func TestWriteBitStringUnusedBits(t *testing.T) {
+	writer := NewWriter(10)
+
+	bStringBytes := [...]byte{0xff, 0xff, 0xff}
+	bString := types.BitString{
+		Bytes: bStringBytes[0:], Length: 12,
+	}
+
+	var encoded = writer.WriteBitString(bString)
+	if encoded != 3 {
+		t.Fatal("Should be 3")
+	}
+	expectedBuffer := [...]byte{0x04, 0xff, 0xf0}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteBitStringEmpty(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteBitString(types.BitString{})
+	if encoded != 1 {
+		t.Fatal("Should be 1")
+	}
+	expectedBuffer := [...]byte{0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERWriterSortSetComponents(t *testing.T) {
+	writer := NewDERWriter(10)
+
+	// components written backwards: [1] 5, [APPLICATION 2] 6, [0] 7, BOOLEAN TRUE
+	nBytes := writer.WriteOctetString([]byte{0x81, 0x01, 0x05})
+	nBytes += writer.WriteOctetString([]byte{0x42, 0x01, 0x06})
+	nBytes += writer.WriteOctetString([]byte{0x80, 0x01, 0x07})
+	nBytes += writer.WriteOctetString([]byte{0x01, 0x01, 0xff})
+
+	err := writer.SortSetComponents(nBytes)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x01, 0x01, 0xff, 0x42, 0x01, 0x06, 0x80, 0x01, 0x07, 0x81, 0x01, 0x05}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERWriterSortSetOfComponents(t *testing.T) {
+	writer := NewDERWriter(10)
+
+	// already written bytes are not touched
+	writer.WriteOctetString([]byte{0x02, 0x01, 0x00})
+
+	nBytes := writer.WriteOctetString([]byte{0x04, 0x02, 0x01, 0x02})
+	nBytes += writer.WriteOctetString([]byte{0x04, 0x01, 0x03})
+	nBytes += writer.WriteOctetString([]byte{0x04, 0x01, 0x01})
+
+	err := writer.SortSetOfComponents(nBytes)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x04, 0x01, 0x01, 0x04, 0x01, 0x03, 0x04, 0x02, 0x01, 0x02, 0x02, 0x01, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestBERWriterSortSetOfComponents(t *testing.T) {
+	writer := NewWriter(10)
+
+	nBytes := writer.WriteOctetString([]byte{0x04, 0x01, 0x03})
+	nBytes += writer.WriteOctetString([]byte{0x04, 0x01, 0x01})
+
+	err := writer.SortSetOfComponents(nBytes)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x04, 0x01, 0x01, 0x04, 0x01, 0x03}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDERWriterSortSetOfComponentsError(t *testing.T) {
+	writer := NewDERWriter(10)
+
+	nBytes := writer.WriteOctetString([]byte{0x04, 0x05, 0x03})
+
+	err := writer.SortSetOfComponents(nBytes)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteInteger64(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteInteger64(-0x80000000000)
+	if encoded != 6 {
+		t.Fatal("Should be 6")
+	}
+	expectedBuffer := [...]byte{0xf8, 0x00, 0x00, 0x00, 0x00, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteInteger64Zero(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteInteger64(0)
+	if encoded != 1 {
+		t.Fatal("Should be 1")
+	}
+	expectedBuffer := [...]byte{0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteUnsignedInteger64(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteUnsignedInteger64(0x8000000000000001)
+	if encoded != 9 {
+		t.Fatal("Should be 9")
+	}
+	expectedBuffer := [...]byte{0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteBigInteger(t *testing.T) {
+	writer := NewWriter(10)
+
+	value, _ := new(big.Int).SetString("00ff00000000000000000000", 16)
+
+	var encoded = writer.WriteBigInteger(value)
+	if encoded != 12 {
+		t.Fatal("Should be 12")
+	}
+	expectedBuffer := [...]byte{0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteBigIntegerNegative(t *testing.T) {
+	values := []int64{0, -1, -128, -129, 127, 128, -25000000}
+	expected := [][]byte{{0x00}, {0xff}, {0x80}, {0xff, 0x7f}, {0x7f}, {0x00, 0x80}, {0xfe, 0x82, 0x87, 0xc0}}
+
+	for i, value := range values {
+		writer := NewWriter(10)
+		writer.WriteBigInteger(big.NewInt(value))
+		if false == bytes.Equal(writer.GetDataBuffer(), expected[i]) {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestWriteLength64(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteLength64(0x123456789a)
+	if encoded != 6 {
+		t.Fatal("Should be 6")
+	}
+	expectedBuffer := [...]byte{0x85, 0x12, 0x34, 0x56, 0x78, 0x9a}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteLength64Max(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteLength64(0xffffffffffffffff)
+	if encoded != 9 {
+		t.Fatal("Should be 9")
+	}
+	expectedBuffer := [...]byte{0x88, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteNull(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteNull()
+	if encoded != 0 {
+		t.Fatal("Should be 0")
+	}
+	if len(writer.GetDataBuffer()) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteEnumerated(t *testing.T) {
+	writer := NewWriter(10)
+
+	var encoded = writer.WriteEnumerated(200)
+	if encoded != 2 {
+		t.Fatal("Should be 2")
+	}
+	expectedBuffer := [...]byte{0x00, 0xc8}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
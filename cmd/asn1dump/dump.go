@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/yafred/asn1-go/ber"
+	"github.com/yafred/asn1-go/types"
+)
+
+// maxDumpedBytes is the number of contents octets shown in hex before the rest is elided
+const maxDumpedBytes = 32
+
+// universalNames are the names of the universal types (X.680 8.4)
+var universalNames = map[uint64]string{
+	0:  "END-OF-CONTENTS",
+	1:  "BOOLEAN",
+	2:  "INTEGER",
+	3:  "BIT STRING",
+	4:  "OCTET STRING",
+	5:  "NULL",
+	6:  "OBJECT IDENTIFIER",
+	7:  "ObjectDescriptor",
+	8:  "EXTERNAL",
+	9:  "REAL",
+	10: "ENUMERATED",
+	11: "EMBEDDED PDV",
+	12: "UTF8String",
+	13: "RELATIVE-OID",
+	14: "TIME",
+	16: "SEQUENCE",
+	17: "SET",
+	18: "NumericString",
+	19: "PrintableString",
+	20: "TeletexString",
+	21: "VideotexString",
+	22: "IA5String",
+	23: "UTCTime",
+	24: "GeneralizedTime",
+	25: "GraphicString",
+	26: "VisibleString",
+	27: "GeneralString",
+	28: "UniversalString",
+	29: "CHARACTER STRING",
+	30: "BMPString",
+	31: "DATE",
+	32: "TIME-OF-DAY",
+	33: "DATE-TIME",
+	34: "DURATION",
+	35: "OID-IRI",
+	36: "RELATIVE-OID-IRI",
+}
+
+// dumpNode writes a line per node of the tree: offset, header length, tag, form, length, type name and value of universal types
+// the lines of the children are indented by depth, which does not exceed ber.MaxNodeDepth as nodes are read with ReadNode
+func dumpNode(out io.Writer, node *ber.Node, depth int) error {
+	line := padLeft(strconv.FormatInt(node.Offset, 10), 6) + ": hl=" + strconv.Itoa(node.HeaderLength) + " " + strings.Repeat("  ", depth) + node.Tag.String()
+	if node.Tag.Constructed {
+		line += " cons"
+	} else {
+		line += " prim"
+	}
+	if node.Length < 0 {
+		line += " len=indefinite"
+	} else {
+		line += " len=" + strconv.FormatInt(node.Length, 10)
+	}
+	if node.Tag.Class == types.UniversalClass {
+		name, ok := universalNames[node.Tag.Number]
+		if !ok {
+			name = "UNKNOWN"
+		}
+		line += " " + name
+	}
+	if !node.Tag.Constructed {
+		value := interpret(node)
+		if value != "" {
+			line += " " + value
+		}
+	}
+
+	_, err := io.WriteString(out, line+"\n")
+	if err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		err = dumpNode(out, child, depth+1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// interpret returns the value of a primitive node as text, the contents in hex if the node is not of a universal type which can be decoded
+func interpret(node *ber.Node) string {
+	if node.Tag.Class != types.UniversalClass {
+		return formatHex(node.Value)
+	}
+
+	r := ber.NewReader(bytes.NewReader(node.Value))
+	n := len(node.Value)
+	var text string
+	var err error
+
+	switch node.Tag.Number {
+	case 0, 5: // END-OF-CONTENTS, NULL
+		if n == 0 {
+			return ""
+		}
+		return formatHex(node.Value) + " (invalid: contents not empty)"
+	case 1: // BOOLEAN
+		if n != 1 {
+			return formatHex(node.Value) + " (invalid: length is not 1)"
+		}
+		if node.Value[0] == 0 {
+			return "FALSE"
+		}
+		return "TRUE"
+	case 2, 10: // INTEGER, ENUMERATED
+		var value *big.Int
+		value, err = r.ReadBigInteger(n)
+		if err == nil {
+			text = value.String()
+		}
+	case 3: // BIT STRING
+		var value types.BitString
+		value, err = r.ReadBitString(n)
+		if err == nil {
+			text = formatBitString(value)
+		}
+	case 6: // OBJECT IDENTIFIER
+		if isLastArcTruncated(node.Value) {
+			return formatHex(node.Value) + " (invalid: last arc is truncated)"
+		}
+		var value types.ObjectIdentifier
+		value, err = r.ReadObjectIdentifier(n)
+		if err == nil {
+			text = value.String()
+		}
+	case 13: // RELATIVE-OID
+		if isLastArcTruncated(node.Value) {
+			return formatHex(node.Value) + " (invalid: last arc is truncated)"
+		}
+		var value types.RelativeOID
+		value, err = r.ReadRelativeOID(n)
+		if err == nil {
+			text = value.String()
+		}
+	case 9: // REAL
+		var value float64
+		value, err = r.ReadReal(n)
+		if err == nil {
+			text = formatReal(value)
+		}
+	case 23: // UTCTime
+		var value types.UTCTime
+		value, err = r.ReadUTCTime(n)
+		if err == nil {
+			text = formatTime(string(value), value.Time)
+		}
+	case 24: // GeneralizedTime
+		var value types.GeneralizedTime
+		value, err = r.ReadGeneralizedTime(n)
+		if err == nil {
+			text = formatTime(string(value), value.Time)
+		}
+	case 7, 12, 14, 18, 19, 20, 21, 22, 25, 26, 27, 31, 32, 33, 34, 35, 36:
+		return strconv.Quote(string(node.Value))
+	case 28: // UniversalString
+		if n%4 != 0 {
+			return formatHex(node.Value) + " (invalid: length is not a multiple of 4)"
+		}
+		runes := make([]rune, n/4)
+		for i := range runes {
+			runes[i] = rune(uint32(node.Value[4*i])<<24 | uint32(node.Value[4*i+1])<<16 | uint32(node.Value[4*i+2])<<8 | uint32(node.Value[4*i+3]))
+		}
+		return strconv.Quote(string(runes))
+	case 30: // BMPString
+		if n%2 != 0 {
+			return formatHex(node.Value) + " (invalid: length is not a multiple of 2)"
+		}
+		units := make([]uint16, n/2)
+		for i := range units {
+			units[i] = uint16(node.Value[2*i])<<8 | uint16(node.Value[2*i+1])
+		}
+		return strconv.Quote(string(utf16.Decode(units)))
+	default:
+		return formatHex(node.Value)
+	}
+
+	if err != nil {
+		return formatHex(node.Value) + " (invalid: " + err.Error() + ")"
+	}
+	return text
+}
+
+// formatHex returns bytes in hex, elided after maxDumpedBytes bytes
+func formatHex(value []byte) string {
+	if len(value) > maxDumpedBytes {
+		return strings.ToUpper(hex.EncodeToString(value[:maxDumpedBytes])) + "..."
+	}
+	return strings.ToUpper(hex.EncodeToString(value))
+}
+
+// formatBitString returns a BIT STRING as a bstring, e.g. '1010'B, or in hex with its number of bits if it is long
+func formatBitString(value types.BitString) string {
+	if value.Length > maxDumpedBytes*2 {
+		return formatHex(value.Bytes) + " (" + strconv.Itoa(value.Length) + " bits)"
+	}
+	bits := make([]byte, value.Length)
+	for i := range bits {
+		bits[i] = '0'
+		if value.Get(i) {
+			bits[i] = '1'
+		}
+	}
+	return "'" + string(bits) + "'B"
+}
+
+// isLastArcTruncated returns true if the contents of an OBJECT IDENTIFIER or RELATIVE-OID end in the middle of an arc
+func isLastArcTruncated(contents []byte) bool {
+	return len(contents) != 0 && contents[len(contents)-1]&0x80 != 0
+}
+
+// formatReal returns a REAL value as text, using the keywords of the special values
+func formatReal(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "PLUS-INFINITY"
+	case math.IsInf(value, -1):
+		return "MINUS-INFINITY"
+	case math.IsNaN(value):
+		return "NOT-A-NUMBER"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// formatTime returns a time value as its text followed by the time it stands for in RFC 3339 format
+func formatTime(text string, parse func() (time.Time, error)) string {
+	value, err := parse()
+	if err != nil {
+		return strconv.Quote(text) + " (invalid: " + err.Error() + ")"
+	}
+	return strconv.Quote(text) + " (" + value.Format(time.RFC3339Nano) + ")"
+}
+
+// padLeft returns text padded with spaces on the left up to width characters
+func padLeft(text string, width int) string {
+	if len(text) >= width {
+		return text
+	}
+	return strings.Repeat(" ", width-len(text)) + text
+}
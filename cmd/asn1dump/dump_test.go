@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	data := []byte{
+		0x30, 0x80,
+		0x02, 0x01, 0xfb,
+		0x03, 0x02, 0x04, 0xa0,
+		0x0c, 0x02, 'h', 'i',
+		0x18, 0x0f, '2', '0', '2', '4', '0', '2', '2', '9', '1', '2', '0', '0', '0', '0', 'Z',
+		0x09, 0x01, 0x40,
+		0x1e, 0x02, 0x00, 'A',
+		0x9f, 0x81, 0x48, 0x02, 0xca, 0xfe,
+		0x00, 0x00,
+	}
+	var out bytes.Buffer
+	err := dump(&out, data, false)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expected := strings.Join([]string{
+		"     0: hl=2 [UNIVERSAL 16] cons len=indefinite SEQUENCE",
+		"     2: hl=2   [UNIVERSAL 2] prim len=1 INTEGER -5",
+		"     5: hl=2   [UNIVERSAL 3] prim len=2 BIT STRING '1010'B",
+		"     9: hl=2   [UNIVERSAL 12] prim len=2 UTF8String \"hi\"",
+		"    13: hl=2   [UNIVERSAL 24] prim len=15 GeneralizedTime \"20240229120000Z\" (2024-02-29T12:00:00Z)",
+		"    30: hl=2   [UNIVERSAL 9] prim len=1 REAL PLUS-INFINITY",
+		"    33: hl=2   [UNIVERSAL 30] prim len=2 BMPString \"A\"",
+		"    37: hl=4   [200] prim len=2 CAFE",
+		"",
+	}, "\n")
+	if out.String() != expected {
+		t.Fatal("Wrong:", out.String())
+	}
+}
+
+func TestDumpInvalid(t *testing.T) {
+	var out bytes.Buffer
+	err := dump(&out, []byte{0x05, 0x00, 0x30, 0x80, 0x02, 0x01, 0x05}, false)
+	if err == nil || err.Error() != "invalid encoding at offset 2: truncated data" {
+		t.Fatal("Wrong:", err)
+	}
+	if out.String() != "     0: hl=2 [UNIVERSAL 5] prim len=0 NULL\n" {
+		t.Fatal("Wrong")
+	}
+
+	out.Reset()
+	err = dump(&out, []byte{0x30, 0x80, 0x00, 0x00}, true)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDumpTooDeep(t *testing.T) {
+	data := append([]byte{0x05, 0x00}, bytes.Repeat([]byte{0x30, 0x80}, 20000)...)
+	var out bytes.Buffer
+	err := dump(&out, data, false)
+	if err == nil || err.Error() != "invalid encoding at offset 202: encodings nested deeper than 100 levels" {
+		t.Fatal("Wrong:", err)
+	}
+	if out.String() != "     0: hl=2 [UNIVERSAL 5] prim len=0 NULL\n" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestInterpretInvalid(t *testing.T) {
+	var out bytes.Buffer
+	err := dump(&out, []byte{0x01, 0x02, 0x00, 0x00, 0x06, 0x01, 0x80}, false)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	if lines[0] != "     0: hl=2 [UNIVERSAL 1] prim len=2 BOOLEAN 0000 (invalid: length is not 1)" || false == strings.HasPrefix(lines[1], "     4: hl=2 [UNIVERSAL 6] prim len=1 OBJECT IDENTIFIER 80 (invalid: ") {
+		t.Fatal("Wrong:", out.String())
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"strings"
+)
+
+// input formats
+const (
+	autoFormat   = "auto"
+	binaryFormat = "binary"
+	hexFormat    = "hex"
+	base64Format = "base64"
+)
+
+// decodeInput returns the encodings held in data, given in format (autoFormat to guess it)
+// base64 input may be PEM, the blocks of which are concatenated
+func decodeInput(data []byte, format string) ([]byte, error) {
+	if format == autoFormat {
+		format = guessFormat(data)
+	}
+
+	switch format {
+	case binaryFormat:
+		return data, nil
+	case hexFormat:
+		text := removeSpaces(string(data))
+		text = strings.TrimPrefix(strings.TrimPrefix(text, "0x"), "0X")
+		decoded, err := hex.DecodeString(text)
+		if err != nil {
+			return nil, errors.New("invalid hex input: " + err.Error())
+		}
+		return decoded, nil
+	case base64Format:
+		if bytes.Contains(data, []byte("-----BEGIN")) {
+			return decodePEM(data)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(removeSpaces(string(data)))
+		if err != nil {
+			return nil, errors.New("invalid base64 input: " + err.Error())
+		}
+		return decoded, nil
+	}
+	return nil, errors.New("unknown input format " + format)
+}
+
+// decodePEM returns the concatenated contents of the PEM blocks of data
+func decodePEM(data []byte) ([]byte, error) {
+	var decoded []byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		decoded = append(decoded, block.Bytes...)
+	}
+	if decoded == nil {
+		return nil, errors.New("invalid PEM input")
+	}
+	return decoded, nil
+}
+
+// guessFormat returns the format of data: base64 if it is PEM or only has base64 characters, hex if it only has hex digits and spaces, binary otherwise
+func guessFormat(data []byte) string {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		return base64Format
+	}
+	text := removeSpaces(string(data))
+	if text == "" {
+		return binaryFormat
+	}
+	isHex, isBase64 := true, true
+	for _, c := range strings.TrimPrefix(text, "0x") {
+		isHexDigit := c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+		isHex = isHex && isHexDigit
+		isBase64 = isBase64 && (isHexDigit || c >= 'g' && c <= 'z' || c >= 'G' && c <= 'Z' || c == '+' || c == '/' || c == '=')
+	}
+	switch {
+	case isHex && len(text)%2 == 0:
+		return hexFormat
+	case isBase64 && len(text)%4 == 0:
+		return base64Format
+	}
+	return binaryFormat
+}
+
+// removeSpaces returns text without its white spaces
+func removeSpaces(text string) string {
+	return strings.Join(strings.Fields(text), "")
+}
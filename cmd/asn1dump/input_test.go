@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeInput(t *testing.T) {
+	expected := []byte{0x30, 0x03, 0x02, 0x01, 0x05}
+
+	inputs := []string{
+		"30 03 02 01 05\n",
+		"0x3003020105",
+		"MAMCAQU=",
+		"-----BEGIN DATA-----\nMAMC\nAQU=\n-----END DATA-----\n",
+		"\x30\x03\x02\x01\x05",
+	}
+	for _, input := range inputs {
+		decoded, err := decodeInput([]byte(input), autoFormat)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if false == bytes.Equal(decoded, expected) {
+			t.Fatal("Wrong:", input)
+		}
+	}
+
+	decoded, err := decodeInput([]byte("3003020105"), binaryFormat)
+	if err != nil || len(decoded) != 10 {
+		t.Fatal("Wrong")
+	}
+	decoded, err = decodeInput([]byte("MAMCAQU="), base64Format)
+	if err != nil || false == bytes.Equal(decoded, expected) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDecodeInputErrors(t *testing.T) {
+	_, err := decodeInput([]byte("3g"), hexFormat)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = decodeInput([]byte("MAM"), base64Format)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = decodeInput([]byte("-----BEGIN DATA-----\n"), base64Format)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	_, err = decodeInput([]byte("00"), "octal")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,99 @@
+// Command asn1dump prints the TLV encodings of BER or DER data as an indented tree, with the values of universal types
+//
+// usage: asn1dump [-format auto|binary|hex|base64] [-der] [file]
+//
+// data is read from stdin if no file is given, base64 input may be PEM
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/yafred/asn1-go/ber"
+)
+
+func main() {
+	format := flag.String("format", autoFormat, "input format: auto, binary, hex or base64 (PEM accepted)")
+	der := flag.Bool("der", false, "reject encodings which are not DER")
+	flag.Usage = func() {
+		os.Stderr.WriteString("usage: asn1dump [-format auto|binary|hex|base64] [-der] [file]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var data []byte
+	var err error
+	if flag.NArg() == 0 || flag.Arg(0) == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(flag.Arg(0))
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	data, err = decodeInput(data, *format)
+	if err != nil {
+		fail(err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	err = dump(out, data, *der)
+	flushErr := out.Flush()
+	if err != nil {
+		fail(err)
+	}
+	if flushErr != nil {
+		fail(flushErr)
+	}
+}
+
+// dump writes the trees of the consecutive encodings of data, raises an error after writing the trees which could be decoded if an encoding is invalid
+func dump(out io.Writer, data []byte, der bool) error {
+	in := ber.NewReader
+	if der {
+		in = ber.NewDERReader
+	}
+	reader := in(bytes.NewReader(data))
+	reader.SetMaxLength(int64(len(data)))
+
+	for reader.GetOffset() != int64(len(data)) {
+		offset := reader.GetOffset()
+		node, err := reader.ReadNode()
+		if depthErr, ok := err.(*ber.DepthError); ok {
+			offset = depthErr.Offset
+		}
+		if err != nil {
+			return errorAt(offset, err)
+		}
+		err = dumpNode(out, node, 0)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorAt returns an error giving the offset of the invalid encoding
+func errorAt(offset int64, err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = errors.New("truncated data")
+	}
+	return errors.New("invalid encoding at offset " + strconv.FormatInt(offset, 10) + ": " + err.Error())
+}
+
+// fail writes an error on stderr and exits
+func fail(err error) {
+	os.Stderr.WriteString("asn1dump: " + err.Error() + "\n")
+	os.Exit(1)
+}
@@ -0,0 +1,30 @@
+// Package gser implements the Generic String Encoding Rules (RFC 3641)
+// values are decoded into the Go values produced by the ber package (types.ObjectIdentifier, types.BitString, []byte...)
+// OBJECT IDENTIFIER values written as descriptors (e.g. rsaEncryption) are not supported, as they need a registry
+package gser
+
+// keywords of the GSER values
+const (
+	trueKeyword          = "TRUE"
+	falseKeyword         = "FALSE"
+	nullKeyword          = "NULL"
+	plusInfinityKeyword  = "PLUS-INFINITY"
+	minusInfinityKeyword = "MINUS-INFINITY"
+)
+
+// names of the components of a non-zero REAL value
+const (
+	mantissaComponent = "mantissa"
+	baseComponent     = "base"
+	exponentComponent = "exponent"
+)
+
+// isIdentifierStart returns true if c can start an identifier
+func isIdentifierStart(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+// isIdentifierPart returns true if c can be part of an identifier (or of a keyword)
+func isIdentifierPart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-'
+}
@@ -0,0 +1,505 @@
+package gser
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Reader helps decode ASN.1 values encoded in GSER
+type Reader struct {
+	// text read from the stream
+	in *bufio.Reader
+
+	// for each SEQUENCE, SET, SEQUENCE OF or SET OF value being read (innermost last), true if a component has been read
+	hasItems []bool
+}
+
+// NewReader creates a reader
+func NewReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.in = bufio.NewReader(in)
+	return r
+}
+
+// peekByte returns the next byte without consuming it, 0 at the end of the stream
+func (r *Reader) peekByte() byte {
+	buffer, err := r.in.Peek(1)
+	if err != nil {
+		return 0
+	}
+	return buffer[0]
+}
+
+// readByte reads the next byte, raises an error if end of stream is reached
+func (r *Reader) readByte() (byte, error) {
+	b, err := r.in.ReadByte()
+	if err == io.EOF {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return b, err
+}
+
+// readSymbol reads the character expected
+func (r *Reader) readSymbol(expected byte) error {
+	b, err := r.readByte()
+	if err != nil {
+		return err
+	}
+	if b != expected {
+		return errors.New("expecting " + strconv.QuoteRune(rune(expected)) + ", found " + strconv.QuoteRune(rune(b)))
+	}
+	return nil
+}
+
+// skipSpaces skips space characters and returns how many were skipped
+func (r *Reader) skipSpaces() int {
+	count := 0
+	for r.peekByte() == ' ' {
+		r.in.ReadByte()
+		count++
+	}
+	return count
+}
+
+// readWord reads a keyword, an identifier, a number or a dotted OBJECT IDENTIFIER
+func (r *Reader) readWord() (string, error) {
+	var builder strings.Builder
+	for c := r.peekByte(); isIdentifierPart(c) || c == '.'; c = r.peekByte() {
+		r.in.ReadByte()
+		builder.WriteByte(c)
+	}
+	if builder.Len() == 0 {
+		if r.peekByte() == 0 {
+			return "", io.ErrUnexpectedEOF
+		}
+		return "", errors.New("unexpected character " + strconv.QuoteRune(rune(r.peekByte())))
+	}
+	return builder.String(), nil
+}
+
+// readIdentifier reads an identifier
+func (r *Reader) readIdentifier() (string, error) {
+	word, err := r.readWord()
+	if err != nil {
+		return "", err
+	}
+	if !isIdentifierStart(word[0]) || strings.Contains(word, "--") || strings.Contains(word, ".") || word[len(word)-1] == '-' {
+		return "", errors.New("invalid identifier " + strconv.Quote(word))
+	}
+	return word, nil
+}
+
+// readQuoted reads the characters between single quotes and the letter following them ('...'B or '...'H)
+func (r *Reader) readQuoted() (string, byte, error) {
+	err := r.readSymbol('\'')
+	if err != nil {
+		return "", 0, err
+	}
+	var builder strings.Builder
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return "", 0, err
+		}
+		if b == '\'' {
+			break
+		}
+		builder.WriteByte(b)
+	}
+	suffix, err := r.readByte()
+	return builder.String(), suffix, err
+}
+
+// BeginSequence begins the decoding of a SEQUENCE or SET, components are then read with NextComponent
+func (r *Reader) BeginSequence() error {
+	err := r.readSymbol('{')
+	if err == nil {
+		r.hasItems = append(r.hasItems, false)
+	}
+	return err
+}
+
+// nextItem reads the separator before the next component of the value being read, returns false (reading the closing brace) if there is none
+func (r *Reader) nextItem() (bool, error) {
+	r.skipSpaces()
+	if r.peekByte() == '}' {
+		r.in.ReadByte()
+		r.hasItems = r.hasItems[:len(r.hasItems)-1]
+		return false, nil
+	}
+	if r.hasItems[len(r.hasItems)-1] {
+		err := r.readSymbol(',')
+		if err != nil {
+			return false, err
+		}
+		r.skipSpaces()
+	}
+	r.hasItems[len(r.hasItems)-1] = true
+	return true, nil
+}
+
+// NextComponent reads the identifier of the next component of the SEQUENCE or SET being read, returns false at the end of the value
+func (r *Reader) NextComponent() (string, bool, error) {
+	isComponent, err := r.nextItem()
+	if err != nil || !isComponent {
+		return "", false, err
+	}
+	identifier, err := r.readIdentifier()
+	if err != nil {
+		return "", false, err
+	}
+	if r.skipSpaces() == 0 {
+		return "", false, errors.New("expecting a space after component identifier " + identifier)
+	}
+	return identifier, true, nil
+}
+
+// BeginSequenceOf begins the decoding of a SEQUENCE OF or SET OF, components are then read after NextElement
+func (r *Reader) BeginSequenceOf() error {
+	return r.BeginSequence()
+}
+
+// NextElement returns true if another component of the SEQUENCE OF or SET OF being read follows, false at the end of the value
+func (r *Reader) NextElement() (bool, error) {
+	return r.nextItem()
+}
+
+// BeginChoice begins the decoding of a CHOICE and returns the identifier of the chosen alternative, whose value must be read next
+func (r *Reader) BeginChoice() (string, error) {
+	alternative, err := r.readIdentifier()
+	if err != nil {
+		return "", err
+	}
+	return alternative, r.readSymbol(':')
+}
+
+// SkipValue skips the next value, for instance the value of an unknown extension
+func (r *Reader) SkipValue() error {
+	switch r.peekByte() {
+	case '{':
+		r.BeginSequenceOf()
+		for {
+			// components of a SEQUENCE and alternatives of a CHOICE start with an identifier followed by a space or a colon
+			isElement, err := r.nextItem()
+			if err != nil || !isElement {
+				return err
+			}
+			err = r.SkipValue()
+			if err != nil {
+				return err
+			}
+			if r.skipSpaces() > 0 && r.peekByte() != ',' && r.peekByte() != '}' {
+				err = r.SkipValue()
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case '"':
+		_, err := r.ReadRestrictedCharacterString()
+		return err
+	case '\'':
+		_, _, err := r.readQuoted()
+		return err
+	}
+	_, err := r.readWord()
+	if err == nil && r.peekByte() == ':' {
+		r.in.ReadByte()
+		return r.SkipValue()
+	}
+	return err
+}
+
+// ReadBoolean decodes a BOOLEAN
+func (r *Reader) ReadBoolean() (bool, error) {
+	word, err := r.readWord()
+	if err != nil {
+		return false, err
+	}
+	switch word {
+	case trueKeyword:
+		return true, nil
+	case falseKeyword:
+		return false, nil
+	}
+	return false, errors.New("expecting TRUE or FALSE, found " + strconv.Quote(word))
+}
+
+// ReadNull decodes NULL
+func (r *Reader) ReadNull() error {
+	word, err := r.readWord()
+	if err == nil && word != nullKeyword {
+		return errors.New("expecting NULL, found " + strconv.Quote(word))
+	}
+	return err
+}
+
+// readNumber reads the text of a number, without leading zeros
+func (r *Reader) readNumber() (string, error) {
+	word, err := r.readWord()
+	if err != nil {
+		return "", err
+	}
+	digits := strings.TrimPrefix(word, "-")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" || len(digits) > 1 && digits[0] == '0' || word == "-0" {
+		return "", errors.New("invalid number " + strconv.Quote(word))
+	}
+	return word, nil
+}
+
+// ReadInteger decodes an INTEGER, raises an error if it does not fit in an int64
+func (r *Reader) ReadInteger() (int64, error) {
+	number, err := r.readNumber()
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid INTEGER " + number)
+	}
+	return value, nil
+}
+
+// ReadBigInteger decodes an INTEGER
+func (r *Reader) ReadBigInteger() (*big.Int, error) {
+	number, err := r.readNumber()
+	if err != nil {
+		return nil, err
+	}
+	value, _ := new(big.Int).SetString(number, 10)
+	return value, nil
+}
+
+// ReadEnumerated decodes an ENUMERATED value (or a named INTEGER value) and returns its identifier
+func (r *Reader) ReadEnumerated() (string, error) {
+	return r.readIdentifier()
+}
+
+// ReadReal decodes a REAL from 0, PLUS-INFINITY, MINUS-INFINITY or { mantissa m, base 2|10, exponent e }
+func (r *Reader) ReadReal() (float64, error) {
+	if r.peekByte() != '{' {
+		word, err := r.readWord()
+		if err != nil {
+			return 0, err
+		}
+		switch word {
+		case "0":
+			return 0, nil
+		case plusInfinityKeyword:
+			return math.Inf(1), nil
+		case minusInfinityKeyword:
+			return math.Inf(-1), nil
+		}
+		return 0, errors.New("invalid REAL " + strconv.Quote(word))
+	}
+
+	r.BeginSequence()
+	var mantissa *big.Int
+	var base, exponent int64
+	for _, expected := range []string{mantissaComponent, baseComponent, exponentComponent} {
+		name, isComponent, err := r.NextComponent()
+		if err != nil {
+			return 0, err
+		}
+		if !isComponent || name != expected {
+			return 0, errors.New("expecting REAL component " + expected)
+		}
+		switch name {
+		case mantissaComponent:
+			mantissa, err = r.ReadBigInteger()
+		case baseComponent:
+			base, err = r.ReadInteger()
+		case exponentComponent:
+			exponent, err = r.ReadInteger()
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	_, isComponent, err := r.NextComponent()
+	if err != nil || isComponent {
+		return 0, errors.New("unexpected component in REAL")
+	}
+
+	var value float64
+	switch base {
+	case 2:
+		if exponent < math.MinInt32 || exponent > math.MaxInt32 {
+			return 0, errors.New("REAL exponent out of range")
+		}
+		value, _ = new(big.Float).SetMantExp(new(big.Float).SetInt(mantissa), int(exponent)).Float64()
+	case 10:
+		value, err = strconv.ParseFloat(mantissa.String()+"E"+strconv.FormatInt(exponent, 10), 64)
+		if err != nil && !math.IsInf(value, 0) {
+			return 0, err
+		}
+	default:
+		return 0, errors.New("REAL base must be 2 or 10")
+	}
+	if math.IsInf(value, 0) {
+		return 0, errors.New("REAL out of range")
+	}
+	return value, nil
+}
+
+// ReadOctetString decodes an OCTET STRING from an hstring ('0123'H)
+func (r *Reader) ReadOctetString() ([]byte, error) {
+	digits, suffix, err := r.readQuoted()
+	if err != nil {
+		return nil, err
+	}
+	if suffix != 'H' || strings.Trim(digits, "0123456789ABCDEF") != "" || len(digits)%2 != 0 {
+		return nil, errors.New("invalid hstring '" + digits + "'" + string(suffix))
+	}
+	return hex.DecodeString(digits)
+}
+
+// ReadBitString decodes a BIT STRING from a bstring ('0101'B), an hstring ('5'H) or a list of named bits ({ read, write })
+// namedBits gives the position of the named bits, it can be nil if the type has no named bits
+func (r *Reader) ReadBitString(namedBits map[string]int) (types.BitString, error) {
+	if r.peekByte() == '{' {
+		return r.readNamedBitList(namedBits)
+	}
+	digits, suffix, err := r.readQuoted()
+	if err != nil {
+		return types.BitString{}, err
+	}
+	var value types.BitString
+	switch {
+	case suffix == 'B' && strings.Trim(digits, "01") == "":
+		value = types.BitString{Bytes: make([]byte, (len(digits)+7)/8), Length: len(digits)}
+		for i, c := range digits {
+			value.Set(i, c == '1')
+		}
+	case suffix == 'H' && strings.Trim(digits, "0123456789ABCDEF") == "":
+		value.Bytes, _ = hex.DecodeString(digits + strings.Repeat("0", len(digits)%2))
+		value.Length = 4 * len(digits)
+	default:
+		return types.BitString{}, errors.New("invalid BIT STRING '" + digits + "'" + string(suffix))
+	}
+	return value, nil
+}
+
+// readNamedBitList decodes a BIT STRING from a list of named bits, its length is the position of the last bit set plus one
+func (r *Reader) readNamedBitList(namedBits map[string]int) (types.BitString, error) {
+	value := types.BitString{}
+	r.BeginSequenceOf()
+	for {
+		isElement, err := r.NextElement()
+		if err != nil {
+			return types.BitString{}, err
+		}
+		if !isElement {
+			return value, nil
+		}
+		name, err := r.readIdentifier()
+		if err != nil {
+			return types.BitString{}, err
+		}
+		position, ok := namedBits[name]
+		if !ok {
+			return types.BitString{}, errors.New("unknown named bit " + name)
+		}
+		if position >= value.Length {
+			bytes := make([]byte, position/8+1)
+			copy(bytes, value.Bytes)
+			value = types.BitString{Bytes: bytes, Length: position + 1}
+		}
+		value.Set(position, true)
+	}
+}
+
+// ReadRestrictedCharacterString decodes a character string from a quoted string, quotation marks are doubled inside
+func (r *Reader) ReadRestrictedCharacterString() (string, error) {
+	err := r.readSymbol('"')
+	if err != nil {
+		return "", err
+	}
+	var builder strings.Builder
+	for {
+		b, err := r.readByte()
+		if err != nil {
+			return "", err
+		}
+		if b == '"' {
+			if r.peekByte() != '"' {
+				break
+			}
+			r.in.ReadByte()
+		}
+		builder.WriteByte(b)
+	}
+	if !utf8.ValidString(builder.String()) {
+		return "", errors.New("string is not valid UTF-8")
+	}
+	return builder.String(), nil
+}
+
+// readDottedNotation reads an OBJECT IDENTIFIER or RELATIVE-OID in dotted notation
+func (r *Reader) readDottedNotation() (string, error) {
+	word, err := r.readWord()
+	if err != nil {
+		return "", err
+	}
+	if word[0] < '0' || word[0] > '9' {
+		return "", errors.New("OBJECT IDENTIFIER descriptor " + strconv.Quote(word) + " is not supported")
+	}
+	return word, nil
+}
+
+// ReadObjectIdentifier decodes an OBJECT IDENTIFIER in dotted notation
+func (r *Reader) ReadObjectIdentifier() (types.ObjectIdentifier, error) {
+	text, err := r.readDottedNotation()
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseObjectIdentifier(text)
+}
+
+// ReadRelativeOID decodes a RELATIVE-OID in dotted notation
+func (r *Reader) ReadRelativeOID() (types.RelativeOID, error) {
+	text, err := r.readDottedNotation()
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseRelativeOID(text)
+}
+
+// ReadUTCTime decodes a UTCTime, raises an error if the string is not a valid UTCTime
+func (r *Reader) ReadUTCTime() (types.UTCTime, error) {
+	value, err := r.ReadRestrictedCharacterString()
+	if err != nil {
+		return "", err
+	}
+	_, err = types.UTCTime(value).Time()
+	return types.UTCTime(value), err
+}
+
+// ReadGeneralizedTime decodes a GeneralizedTime, raises an error if the string is not a valid GeneralizedTime
+func (r *Reader) ReadGeneralizedTime() (types.GeneralizedTime, error) {
+	value, err := r.ReadRestrictedCharacterString()
+	if err != nil {
+		return "", err
+	}
+	_, err = types.GeneralizedTime(value).Time()
+	return types.GeneralizedTime(value), err
+}
+
+// ReadTime decodes a TIME, DATE, TIME-OF-DAY, DATE-TIME or DURATION with the given properties (types.DateProperties...)
+// raises an error if the string does not match the properties
+func (r *Reader) ReadTime(properties types.TimeProperties) (string, error) {
+	value, err := r.ReadRestrictedCharacterString()
+	if err != nil {
+		return "", err
+	}
+	return value, types.Time(value).Validate(properties)
+}
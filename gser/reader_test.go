@@ -0,0 +1,187 @@
+package gser
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadSequence(t *testing.T) {
+	text := `{ version 2, unknown { a { 1, x:"}" }, b '01'B },oid 1.2.840.113549,  flags '1010'B, list { TRUE, NULL, name:"a ""b""" }, empty { } }`
+	reader := NewReader(strings.NewReader(text))
+
+	err := reader.BeginSequence()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	names := []string{}
+	for {
+		name, isComponent, err := reader.NextComponent()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if !isComponent {
+			break
+		}
+		names = append(names, name)
+		switch name {
+		case "version":
+			version, err := reader.ReadInteger()
+			if err != nil || version != 2 {
+				t.Fatal("Wrong")
+			}
+		case "oid":
+			oid, err := reader.ReadObjectIdentifier()
+			if err != nil || len(oid) != 4 || oid[3] != 113549 {
+				t.Fatal("Wrong")
+			}
+		case "flags":
+			flags, err := reader.ReadBitString(nil)
+			if err != nil || flags.Length != 4 || false == bytes.Equal(flags.Bytes, []byte{0xa0}) {
+				t.Fatal("Wrong")
+			}
+		case "list":
+			reader.BeginSequenceOf()
+			reader.NextElement()
+			value, err := reader.ReadBoolean()
+			if err != nil || value != true {
+				t.Fatal("Wrong")
+			}
+			reader.NextElement()
+			err = reader.ReadNull()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+			reader.NextElement()
+			alternative, err := reader.BeginChoice()
+			if err != nil || alternative != "name" {
+				t.Fatal("Wrong:", err)
+			}
+			name, err := reader.ReadRestrictedCharacterString()
+			if err != nil || name != `a "b"` {
+				t.Fatal("Wrong:", name)
+			}
+			isElement, err := reader.NextElement()
+			if err != nil || isElement {
+				t.Fatal("Wrong")
+			}
+		default:
+			err = reader.SkipValue()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+		}
+	}
+	if strings.Join(names, ",") != "version,unknown,oid,flags,list,empty" {
+		t.Fatal("Wrong:", names)
+	}
+}
+
+func TestReadPrimitives(t *testing.T) {
+	reader := NewReader(strings.NewReader(`'01AB'H`))
+	octets, err := reader.ReadOctetString()
+	if err != nil || false == bytes.Equal(octets, []byte{0x01, 0xab}) {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader(`'A8'H`))
+	bits, err := reader.ReadBitString(nil)
+	if err != nil || bits.Length != 8 || bits.Bytes[0] != 0xa8 {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader(`{ read, execute }`))
+	bits, err = reader.ReadBitString(map[string]int{"read": 0, "write": 1, "execute": 9})
+	if err != nil || bits.Length != 10 || false == bytes.Equal(bits.Bytes, []byte{0x80, 0x40}) {
+		t.Fatal("Wrong:", bits)
+	}
+
+	reader = NewReader(strings.NewReader(`8571.3`))
+	relativeOID, err := reader.ReadRelativeOID()
+	if err != nil || len(relativeOID) != 2 || relativeOID[0] != 8571 {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader(`-1180591620717411303424`))
+	bigValue, err := reader.ReadBigInteger()
+	if err != nil || bigValue.BitLen() != 71 || bigValue.Sign() != -1 {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader(`"19910506234540.5Z"`))
+	_, err = reader.ReadGeneralizedTime()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestReadReal(t *testing.T) {
+	texts := []string{"{ mantissa 3, base 2, exponent -1 }", "{ mantissa -15, base 10, exponent 1 }", "0", "PLUS-INFINITY", "MINUS-INFINITY"}
+	expected := []float64{1.5, -150, 0, math.Inf(1), math.Inf(-1)}
+	for i, text := range texts {
+		reader := NewReader(strings.NewReader(text))
+		value, err := reader.ReadReal()
+		if err != nil || value != expected[i] {
+			t.Fatal("Wrong:", text, err)
+		}
+	}
+
+	for _, text := range []string{"{ mantissa 3, base 8, exponent 1 }", "{ base 2, mantissa 3, exponent 1 }", "{ mantissa 1, base 2, exponent 5000 }", "1.5"} {
+		reader := NewReader(strings.NewReader(text))
+		_, err := reader.ReadReal()
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	reader := NewReader(strings.NewReader("{ version}"))
+	reader.BeginSequence()
+	_, _, err := reader.NextComponent()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader("{ a 1 b 2 }"))
+	reader.BeginSequence()
+	reader.NextComponent()
+	reader.ReadInteger()
+	_, _, err = reader.NextComponent()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	for _, text := range []string{"007", "-0", "1e3", "x"} {
+		reader = NewReader(strings.NewReader(text))
+		_, err = reader.ReadInteger()
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+
+	reader = NewReader(strings.NewReader("rsaEncryption"))
+	_, err = reader.ReadObjectIdentifier()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader("'0ab'H"))
+	_, err = reader.ReadOctetString()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader(`"abc`))
+	_, err = reader.ReadRestrictedCharacterString()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader(`{ unknown }`))
+	_, err = reader.ReadBitString(map[string]int{"read": 0})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
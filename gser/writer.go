@@ -0,0 +1,209 @@
+package gser
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Writer helps encode ASN.1 values in GSER, text is written forward
+type Writer struct {
+	// encoded data
+	dataBuffer []byte
+
+	// for each SEQUENCE, SET, SEQUENCE OF or SET OF value being written (innermost last), true if a component has been written
+	hasItems []bool
+
+	// true if a component identifier or a CHOICE alternative has been written and its value has not
+	afterName bool
+}
+
+// NewWriter creates a writer
+func NewWriter() *Writer {
+	w := new(Writer)
+	return w
+}
+
+// GetDataBuffer returns the encoded data
+func (w *Writer) GetDataBuffer() []byte {
+	return w.dataBuffer
+}
+
+// writeSeparator writes the separator of a component from the previous one (or from the opening brace)
+func (w *Writer) writeSeparator() {
+	if w.afterName {
+		w.afterName = false
+		return
+	}
+	if len(w.hasItems) == 0 {
+		return
+	}
+	if w.hasItems[len(w.hasItems)-1] {
+		w.dataBuffer = append(w.dataBuffer, ',')
+	}
+	w.dataBuffer = append(w.dataBuffer, ' ')
+	w.hasItems[len(w.hasItems)-1] = true
+}
+
+// writeValue writes the text of a value
+func (w *Writer) writeValue(value string) {
+	w.writeSeparator()
+	w.dataBuffer = append(w.dataBuffer, value...)
+}
+
+// BeginSequence begins the encoding of a SEQUENCE or SET, components are written with WriteName followed by their value
+func (w *Writer) BeginSequence() {
+	w.writeValue("{")
+	w.hasItems = append(w.hasItems, false)
+}
+
+// WriteName writes the identifier of the next component of the SEQUENCE or SET being written
+func (w *Writer) WriteName(identifier string) {
+	w.writeSeparator()
+	w.dataBuffer = append(w.dataBuffer, identifier...)
+	w.dataBuffer = append(w.dataBuffer, ' ')
+	w.afterName = true
+}
+
+// EndSequence ends the encoding of a SEQUENCE or SET
+func (w *Writer) EndSequence() {
+	w.hasItems = w.hasItems[:len(w.hasItems)-1]
+	w.dataBuffer = append(w.dataBuffer, " }"...)
+}
+
+// BeginSequenceOf begins the encoding of a SEQUENCE OF or SET OF, components are written as they come
+func (w *Writer) BeginSequenceOf() {
+	w.BeginSequence()
+}
+
+// EndSequenceOf ends the encoding of a SEQUENCE OF or SET OF
+func (w *Writer) EndSequenceOf() {
+	w.EndSequence()
+}
+
+// BeginChoice begins the encoding of a CHOICE (alternative:value), the value of the alternative must be written next
+func (w *Writer) BeginChoice(alternative string) {
+	w.writeSeparator()
+	w.dataBuffer = append(w.dataBuffer, alternative...)
+	w.dataBuffer = append(w.dataBuffer, ':')
+	w.afterName = true
+}
+
+// WriteBoolean encodes a BOOLEAN as TRUE or FALSE
+func (w *Writer) WriteBoolean(value bool) {
+	if value {
+		w.writeValue(trueKeyword)
+	} else {
+		w.writeValue(falseKeyword)
+	}
+}
+
+// WriteNull encodes NULL
+func (w *Writer) WriteNull() {
+	w.writeValue(nullKeyword)
+}
+
+// WriteInteger encodes an INTEGER as a number
+func (w *Writer) WriteInteger(value int64) {
+	w.writeValue(strconv.FormatInt(value, 10))
+}
+
+// WriteBigInteger encodes an INTEGER as a number
+func (w *Writer) WriteBigInteger(value *big.Int) {
+	w.writeValue(value.String())
+}
+
+// WriteEnumerated encodes an ENUMERATED value (or a named INTEGER value) as its identifier
+func (w *Writer) WriteEnumerated(identifier string) {
+	w.writeValue(identifier)
+}
+
+// WriteReal encodes a REAL as 0, PLUS-INFINITY, MINUS-INFINITY or { mantissa m, base 2, exponent e }
+// raises an error for NaN and minus zero which GSER cannot represent
+func (w *Writer) WriteReal(value float64) error {
+	switch {
+	case math.IsNaN(value):
+		return errors.New("NaN cannot be encoded in GSER")
+	case math.IsInf(value, 1):
+		w.writeValue(plusInfinityKeyword)
+	case math.IsInf(value, -1):
+		w.writeValue(minusInfinityKeyword)
+	case value == 0 && math.Signbit(value):
+		return errors.New("minus zero cannot be encoded in GSER")
+	case value == 0:
+		w.writeValue("0")
+	default:
+		fraction, exponent := math.Frexp(value)
+		mantissa := int64(math.Ldexp(fraction, 53))
+		exponent -= 53
+		for mantissa%2 == 0 {
+			mantissa /= 2
+			exponent++
+		}
+		w.BeginSequence()
+		w.WriteName(mantissaComponent)
+		w.WriteInteger(mantissa)
+		w.WriteName(baseComponent)
+		w.WriteInteger(2)
+		w.WriteName(exponentComponent)
+		w.WriteInteger(int64(exponent))
+		w.EndSequence()
+	}
+	return nil
+}
+
+// WriteOctetString encodes an OCTET STRING as an hstring ('0123'H)
+func (w *Writer) WriteOctetString(value []byte) {
+	w.writeValue("'" + strings.ToUpper(hex.EncodeToString(value)) + "'H")
+}
+
+// WriteBitString encodes a BIT STRING as a bstring ('0101'B)
+func (w *Writer) WriteBitString(value types.BitString) {
+	length := value.ValidLength()
+	var builder strings.Builder
+	builder.WriteByte('\'')
+	for i := 0; i < length; i++ {
+		if value.Get(i) {
+			builder.WriteByte('1')
+		} else {
+			builder.WriteByte('0')
+		}
+	}
+	builder.WriteString("'B")
+	w.writeValue(builder.String())
+}
+
+// WriteRestrictedCharacterString encodes a character string as a quoted string, quotation marks are doubled
+func (w *Writer) WriteRestrictedCharacterString(value string) {
+	w.writeValue(`"` + strings.ReplaceAll(value, `"`, `""`) + `"`)
+}
+
+// WriteObjectIdentifier encodes an OBJECT IDENTIFIER in dotted notation
+func (w *Writer) WriteObjectIdentifier(value types.ObjectIdentifier) {
+	w.writeValue(value.String())
+}
+
+// WriteRelativeOID encodes a RELATIVE-OID in dotted notation
+func (w *Writer) WriteRelativeOID(value types.RelativeOID) {
+	w.writeValue(value.String())
+}
+
+// WriteUTCTime encodes a UTCTime as a quoted string
+func (w *Writer) WriteUTCTime(value types.UTCTime) {
+	w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteGeneralizedTime encodes a GeneralizedTime as a quoted string
+func (w *Writer) WriteGeneralizedTime(value types.GeneralizedTime) {
+	w.WriteRestrictedCharacterString(string(value))
+}
+
+// WriteTime encodes a TIME, DATE, TIME-OF-DAY, DATE-TIME or DURATION as a quoted string
+func (w *Writer) WriteTime(value string) {
+	w.WriteRestrictedCharacterString(value)
+}
@@ -0,0 +1,74 @@
+package gser
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestWriteSequence(t *testing.T) {
+	writer := NewWriter()
+
+	writer.BeginSequence()
+	writer.WriteName("version")
+	writer.WriteInteger(2)
+	writer.WriteName("oid")
+	writer.WriteObjectIdentifier(types.ObjectIdentifier{1, 2, 840, 113549})
+	writer.WriteName("flags")
+	writer.WriteBitString(types.BitString{Bytes: []byte{0xaf}, Length: 4})
+	writer.WriteName("list")
+	writer.BeginSequenceOf()
+	writer.WriteBoolean(true)
+	writer.WriteNull()
+	writer.BeginChoice("name")
+	writer.WriteRestrictedCharacterString(`a "b"`)
+	writer.EndSequenceOf()
+	writer.WriteName("empty")
+	writer.BeginSequenceOf()
+	writer.EndSequenceOf()
+	writer.EndSequence()
+
+	expected := `{ version 2, oid 1.2.840.113549, flags '1010'B, list { TRUE, NULL, name:"a ""b""" }, empty { } }`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWritePrimitives(t *testing.T) {
+	writer := NewWriter()
+
+	writer.BeginSequenceOf()
+	writer.WriteOctetString([]byte{0x01, 0xab})
+	writer.WriteRelativeOID(types.RelativeOID{8571, 3})
+	writer.WriteEnumerated("red")
+	writer.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 70))
+	writer.WriteInteger(-5)
+	writer.WriteUTCTime(types.UTCTime("910506234540Z"))
+	writer.WriteGeneralizedTime(types.GeneralizedTime("19910506234540.5Z"))
+	writer.WriteTime("2020-01-31")
+	writer.EndSequenceOf()
+
+	expected := `{ '01AB'H, 8571.3, red, 1180591620717411303424, -5, "910506234540Z", "19910506234540.5Z", "2020-01-31" }`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWriteReal(t *testing.T) {
+	values := []float64{1.5, -12, 0, math.Inf(1), math.Inf(-1)}
+	expected := []string{"{ mantissa 3, base 2, exponent -1 }", "{ mantissa -3, base 2, exponent 2 }", "0", "PLUS-INFINITY", "MINUS-INFINITY"}
+	for i, value := range values {
+		writer := NewWriter()
+		err := writer.WriteReal(value)
+		if err != nil || string(writer.GetDataBuffer()) != expected[i] {
+			t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+		}
+	}
+
+	writer := NewWriter()
+	if writer.WriteReal(math.NaN()) == nil || writer.WriteReal(math.Copysign(0, -1)) == nil {
+		t.Fatal("Wrong")
+	}
+}
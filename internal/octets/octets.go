@@ -0,0 +1,44 @@
+// Package octets holds the helpers shared by the encoding rules packages
+package octets
+
+import (
+	"math/bits"
+)
+
+// DefaultMaxLength is the maximum length accepted by the readers unless another maximum is set
+const DefaultMaxLength int64 = 0xFFFFFFFF
+
+// UnsignedBytes returns the minimum number of octets of a non-negative binary integer (at least 1)
+func UnsignedBytes(value uint64) int {
+	n := (bits.Len64(value) + 7) / 8
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// SignedBytes returns the minimum number of octets of a 2's complement binary integer
+func SignedBytes(value int64) int {
+	n := 1
+	for rest := value >> 7; rest != 0 && rest != -1; rest = rest >> 8 {
+		n++
+	}
+	return n
+}
+
+// CompareSetOfEncodings compares two encodings as octet strings, the shorter being padded with zero octets (X.690 11.6)
+func CompareSetOfEncodings(a []byte, b []byte) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aByte, bByte byte
+		if i < len(a) {
+			aByte = a[i]
+		}
+		if i < len(b) {
+			bByte = b[i]
+		}
+		if aByte != bByte {
+			return int(aByte) - int(bByte)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,39 @@
+package octets
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnsignedBytes(t *testing.T) {
+	values := []uint64{0, 1, 255, 256, math.MaxUint32, math.MaxUint64}
+	expected := []int{1, 1, 1, 2, 4, 8}
+	for i, value := range values {
+		if UnsignedBytes(value) != expected[i] {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestSignedBytes(t *testing.T) {
+	values := []int64{0, 127, 128, -128, -129, math.MaxInt64, math.MinInt64}
+	expected := []int{1, 1, 2, 1, 2, 8, 8}
+	for i, value := range values {
+		if SignedBytes(value) != expected[i] {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestCompareSetOfEncodings(t *testing.T) {
+	if CompareSetOfEncodings([]byte{0x01, 0x02}, []byte{0x01, 0x03}) >= 0 {
+		t.Fatal("Wrong")
+	}
+	if CompareSetOfEncodings([]byte{0x02}, []byte{0x01, 0xff}) <= 0 {
+		t.Fatal("Wrong")
+	}
+	// the shorter encoding is padded with zero octets
+	if CompareSetOfEncodings([]byte{0x01}, []byte{0x01, 0x00}) != 0 {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,10 @@
+// Package jer implements the JSON Encoding Rules (X.697)
+package jer
+
+// JSON strings encoding the special REAL values
+const (
+	plusInfinity  = "INF"
+	minusInfinity = "-INF"
+	notANumber    = "NaN"
+	minusZero     = "-0"
+)
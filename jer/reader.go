@@ -0,0 +1,319 @@
+package jer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Reader helps decode ASN.1 values encoded in JER
+type Reader struct {
+	// JSON tokens read from the stream
+	decoder *json.Decoder
+}
+
+// NewReader creates a reader
+func NewReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.decoder = json.NewDecoder(in)
+	r.decoder.UseNumber()
+	return r
+}
+
+// readToken reads the next JSON token, raises an error if end of stream is reached
+func (r *Reader) readToken() (json.Token, error) {
+	token, err := r.decoder.Token()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return token, err
+}
+
+// readDelim reads the JSON delimiter expected
+func (r *Reader) readDelim(expected json.Delim) error {
+	token, err := r.readToken()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return errors.New("expecting " + expected.String())
+	}
+	return nil
+}
+
+// readString reads a JSON string
+func (r *Reader) readString() (string, error) {
+	token, err := r.readToken()
+	if err != nil {
+		return "", err
+	}
+	value, ok := token.(string)
+	if !ok {
+		return "", errors.New("expecting a string")
+	}
+	return value, nil
+}
+
+// readNumber reads a JSON number
+func (r *Reader) readNumber() (json.Number, error) {
+	token, err := r.readToken()
+	if err != nil {
+		return "", err
+	}
+	value, ok := token.(json.Number)
+	if !ok {
+		return "", errors.New("expecting a number")
+	}
+	return value, nil
+}
+
+// BeginObject begins the decoding of a SEQUENCE or SET, members are then read with NextMember
+func (r *Reader) BeginObject() error {
+	return r.readDelim('{')
+}
+
+// NextMember reads the name of the next member of the object being read, returns false at the end of the object
+func (r *Reader) NextMember() (string, bool, error) {
+	if !r.decoder.More() {
+		return "", false, r.readDelim('}')
+	}
+	name, err := r.readString()
+	return name, err == nil, err
+}
+
+// BeginArray begins the decoding of a SEQUENCE OF or SET OF, elements are then read after NextElement
+func (r *Reader) BeginArray() error {
+	return r.readDelim('[')
+}
+
+// NextElement returns true if another element of the array being read follows, false at the end of the array
+func (r *Reader) NextElement() (bool, error) {
+	if !r.decoder.More() {
+		return false, r.readDelim(']')
+	}
+	return true, nil
+}
+
+// BeginChoice begins the decoding of a CHOICE and returns the identifier of the chosen alternative, whose value must be read next
+func (r *Reader) BeginChoice() (string, error) {
+	err := r.BeginObject()
+	if err != nil {
+		return "", err
+	}
+	alternative, isMember, err := r.NextMember()
+	if err == nil && !isMember {
+		return "", errors.New("CHOICE object must have a member")
+	}
+	return alternative, err
+}
+
+// EndChoice ends the decoding of a CHOICE, raises an error if the object has more than one member
+func (r *Reader) EndChoice() error {
+	if r.decoder.More() {
+		return errors.New("CHOICE object must have a single member")
+	}
+	return r.readDelim('}')
+}
+
+// SkipValue skips the next value, for instance the value of an unknown extension
+func (r *Reader) SkipValue() error {
+	var value json.RawMessage
+	return r.decoder.Decode(&value)
+}
+
+// ReadBoolean decodes a BOOLEAN
+func (r *Reader) ReadBoolean() (bool, error) {
+	token, err := r.readToken()
+	if err != nil {
+		return false, err
+	}
+	value, ok := token.(bool)
+	if !ok {
+		return false, errors.New("expecting true or false")
+	}
+	return value, nil
+}
+
+// ReadNull decodes NULL
+func (r *Reader) ReadNull() error {
+	token, err := r.readToken()
+	if err != nil {
+		return err
+	}
+	if token != nil {
+		return errors.New("expecting null")
+	}
+	return nil
+}
+
+// ReadInteger decodes an INTEGER, raises an error if it does not fit in an int64
+func (r *Reader) ReadInteger() (int64, error) {
+	number, err := r.readNumber()
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseInt(number.String(), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid INTEGER " + number.String())
+	}
+	return value, nil
+}
+
+// ReadBigInteger decodes an INTEGER
+func (r *Reader) ReadBigInteger() (*big.Int, error) {
+	number, err := r.readNumber()
+	if err != nil {
+		return nil, err
+	}
+	value, ok := new(big.Int).SetString(number.String(), 10)
+	if !ok {
+		return nil, errors.New("invalid INTEGER " + number.String())
+	}
+	return value, nil
+}
+
+// ReadEnumerated decodes an ENUMERATED value and returns its identifier
+func (r *Reader) ReadEnumerated() (string, error) {
+	return r.readString()
+}
+
+// ReadReal decodes a REAL from a number or from one of the strings "INF", "-INF", "NaN" and "-0"
+func (r *Reader) ReadReal() (float64, error) {
+	token, err := r.readToken()
+	if err != nil {
+		return 0, err
+	}
+	switch value := token.(type) {
+	case json.Number:
+		return strconv.ParseFloat(value.String(), 64)
+	case string:
+		switch value {
+		case plusInfinity:
+			return math.Inf(1), nil
+		case minusInfinity:
+			return math.Inf(-1), nil
+		case notANumber:
+			return math.NaN(), nil
+		case minusZero:
+			return math.Copysign(0, -1), nil
+		}
+	}
+	return 0, errors.New("invalid REAL")
+}
+
+// ReadOctetString decodes an OCTET STRING from a string of hexadecimal digits
+func (r *Reader) ReadOctetString() ([]byte, error) {
+	value, err := r.readString()
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(value)
+}
+
+// ReadBitString decodes a BIT STRING from an object with the members "value" and "length"
+func (r *Reader) ReadBitString() (types.BitString, error) {
+	err := r.BeginObject()
+	if err != nil {
+		return types.BitString{}, err
+	}
+	var buffer []byte
+	length := int64(-1)
+	for {
+		name, isMember, err := r.NextMember()
+		if err != nil {
+			return types.BitString{}, err
+		}
+		if !isMember {
+			break
+		}
+		switch name {
+		case "value":
+			buffer, err = r.ReadOctetString()
+		case "length":
+			length, err = r.ReadInteger()
+		default:
+			err = errors.New("unexpected member " + strconv.Quote(name) + " in BIT STRING")
+		}
+		if err != nil {
+			return types.BitString{}, err
+		}
+	}
+	if buffer == nil || length < 0 {
+		return types.BitString{}, errors.New("BIT STRING must have a value and a length")
+	}
+	if length > int64(8*len(buffer)) || length <= int64(8*len(buffer)-8) && len(buffer) > 0 {
+		return types.BitString{}, errors.New("BIT STRING length does not match its value")
+	}
+	return types.BitString{Bytes: buffer, Length: int(length)}, nil
+}
+
+// ReadFixedSizeBitString decodes a BIT STRING with a fixed SIZE constraint of length bits from a string of hexadecimal digits
+func (r *Reader) ReadFixedSizeBitString(length int) (types.BitString, error) {
+	buffer, err := r.ReadOctetString()
+	if err != nil {
+		return types.BitString{}, err
+	}
+	if len(buffer) != (length+7)/8 {
+		return types.BitString{}, errors.New("BIT STRING length does not match its SIZE constraint")
+	}
+	return types.BitString{Bytes: buffer, Length: length}, nil
+}
+
+// ReadRestrictedCharacterString decodes a character string
+func (r *Reader) ReadRestrictedCharacterString() (string, error) {
+	return r.readString()
+}
+
+// ReadObjectIdentifier decodes an OBJECT IDENTIFIER from a string in dotted notation
+func (r *Reader) ReadObjectIdentifier() (types.ObjectIdentifier, error) {
+	value, err := r.readString()
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseObjectIdentifier(value)
+}
+
+// ReadRelativeOID decodes a RELATIVE-OID from a string in dotted notation
+func (r *Reader) ReadRelativeOID() (types.RelativeOID, error) {
+	value, err := r.readString()
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseRelativeOID(value)
+}
+
+// ReadUTCTime decodes a UTCTime, raises an error if the string is not a valid UTCTime
+func (r *Reader) ReadUTCTime() (types.UTCTime, error) {
+	value, err := r.readString()
+	if err != nil {
+		return "", err
+	}
+	_, err = types.UTCTime(value).Time()
+	return types.UTCTime(value), err
+}
+
+// ReadGeneralizedTime decodes a GeneralizedTime, raises an error if the string is not a valid GeneralizedTime
+func (r *Reader) ReadGeneralizedTime() (types.GeneralizedTime, error) {
+	value, err := r.readString()
+	if err != nil {
+		return "", err
+	}
+	_, err = types.GeneralizedTime(value).Time()
+	return types.GeneralizedTime(value), err
+}
+
+// ReadTime decodes a TIME, DATE, TIME-OF-DAY, DATE-TIME or DURATION with the given properties (types.DateProperties...)
+// raises an error if the string does not match the properties
+func (r *Reader) ReadTime(properties types.TimeProperties) (string, error) {
+	value, err := r.readString()
+	if err != nil {
+		return "", err
+	}
+	return value, types.Time(value).Validate(properties)
+}
@@ -0,0 +1,220 @@
+package jer
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestReadSequence(t *testing.T) {
+	text := `{ "version": 2, "unknown": { "a": [1, 2] }, "oid": "1.2.840.113549", "flags": { "length": 4, "value": "a0" },
+		"list": [ true, null, { "name": "a \"b\"" } ] }`
+	reader := NewReader(strings.NewReader(text))
+
+	err := reader.BeginObject()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	names := []string{}
+	for {
+		name, isMember, err := reader.NextMember()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if !isMember {
+			break
+		}
+		names = append(names, name)
+		switch name {
+		case "version":
+			version, err := reader.ReadInteger()
+			if err != nil || version != 2 {
+				t.Fatal("Wrong")
+			}
+		case "oid":
+			oid, err := reader.ReadObjectIdentifier()
+			if err != nil || len(oid) != 4 || oid[3] != 113549 {
+				t.Fatal("Wrong")
+			}
+		case "flags":
+			flags, err := reader.ReadBitString()
+			if err != nil || flags.Length != 4 || false == bytes.Equal(flags.Bytes, []byte{0xa0}) {
+				t.Fatal("Wrong")
+			}
+		case "list":
+			err = reader.BeginArray()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+			reader.NextElement()
+			value, err := reader.ReadBoolean()
+			if err != nil || value != true {
+				t.Fatal("Wrong")
+			}
+			reader.NextElement()
+			err = reader.ReadNull()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+			reader.NextElement()
+			alternative, err := reader.BeginChoice()
+			if err != nil || alternative != "name" {
+				t.Fatal("Wrong")
+			}
+			name, err := reader.ReadRestrictedCharacterString()
+			if err != nil || name != "a \"b\"" {
+				t.Fatal("Wrong")
+			}
+			err = reader.EndChoice()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+			more, err := reader.NextElement()
+			if err != nil || more {
+				t.Fatal("Wrong")
+			}
+		default:
+			err = reader.SkipValue()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+		}
+	}
+	if strings.Join(names, ",") != "version,unknown,oid,flags,list" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadPrimitives(t *testing.T) {
+	text := `["01AB","FFF0","8571.3","red",1180591620717411303424,"910506234540Z","19910506234540.5Z","2020-01-31"]`
+	reader := NewReader(strings.NewReader(text))
+
+	reader.BeginArray()
+	octets, err := reader.ReadOctetString()
+	if err != nil || false == bytes.Equal(octets, []byte{0x01, 0xab}) {
+		t.Fatal("Wrong")
+	}
+	bits, err := reader.ReadFixedSizeBitString(12)
+	if err != nil || bits.Length != 12 || false == bytes.Equal(bits.Bytes, []byte{0xff, 0xf0}) {
+		t.Fatal("Wrong")
+	}
+	relativeOID, err := reader.ReadRelativeOID()
+	if err != nil || len(relativeOID) != 2 || relativeOID[0] != 8571 {
+		t.Fatal("Wrong")
+	}
+	enumerated, err := reader.ReadEnumerated()
+	if err != nil || enumerated != "red" {
+		t.Fatal("Wrong")
+	}
+	bigValue, err := reader.ReadBigInteger()
+	if err != nil || bigValue.String() != "1180591620717411303424" {
+		t.Fatal("Wrong")
+	}
+	utcTime, err := reader.ReadUTCTime()
+	if err != nil || utcTime != "910506234540Z" {
+		t.Fatal("Wrong")
+	}
+	generalizedTime, err := reader.ReadGeneralizedTime()
+	if err != nil || generalizedTime != "19910506234540.5Z" {
+		t.Fatal("Wrong")
+	}
+	date, err := reader.ReadTime(types.DateProperties)
+	if err != nil || date != "2020-01-31" {
+		t.Fatal("Wrong")
+	}
+	more, err := reader.NextElement()
+	if err != nil || more {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadReal(t *testing.T) {
+	text := `[1.5, -1e300, 0, "-0", "INF", "-INF", "NaN"]`
+	reader := NewReader(strings.NewReader(text))
+
+	reader.BeginArray()
+	for _, expected := range []float64{1.5, -1e300, 0} {
+		value, err := reader.ReadReal()
+		if err != nil || value != expected {
+			t.Fatal("Wrong")
+		}
+	}
+	value, err := reader.ReadReal()
+	if err != nil || value != 0 || !math.Signbit(value) {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadReal()
+	if err != nil || !math.IsInf(value, 1) {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadReal()
+	if err != nil || !math.IsInf(value, -1) {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadReal()
+	if err != nil || !math.IsNaN(value) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		read func(r *Reader) error
+	}{
+		{`1.5`, func(r *Reader) error {
+			_, err := r.ReadInteger()
+			return err
+		}},
+		{`"1.2.x"`, func(r *Reader) error {
+			_, err := r.ReadObjectIdentifier()
+			return err
+		}},
+		{`"3.1"`, func(r *Reader) error {
+			_, err := r.ReadObjectIdentifier()
+			return err
+		}},
+		{`{"value":"A0","length":9}`, func(r *Reader) error {
+			_, err := r.ReadBitString()
+			return err
+		}},
+		{`{"value":"A0"}`, func(r *Reader) error {
+			_, err := r.ReadBitString()
+			return err
+		}},
+		{`{"a":1,"b":2}`, func(r *Reader) error {
+			r.BeginChoice()
+			r.ReadInteger()
+			return r.EndChoice()
+		}},
+		{`{}`, func(r *Reader) error {
+			_, err := r.BeginChoice()
+			return err
+		}},
+		{`"+INF"`, func(r *Reader) error {
+			_, err := r.ReadReal()
+			return err
+		}},
+		{`"0A1"`, func(r *Reader) error {
+			_, err := r.ReadOctetString()
+			return err
+		}},
+		{`"991301000000Z"`, func(r *Reader) error {
+			_, err := r.ReadUTCTime()
+			return err
+		}},
+		{``, func(r *Reader) error {
+			return r.ReadNull()
+		}},
+	}
+
+	for i, test := range tests {
+		err := test.read(NewReader(strings.NewReader(test.text)))
+		if err == nil {
+			t.Fatal("Wrong:", i)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package jer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Writer helps encode ASN.1 values in JER, JSON text is written forward without white space
+type Writer struct {
+	// encoded data
+	dataBuffer []byte
+
+	// for each object or array being written (innermost last), true if a member or element has been written
+	hasItems []bool
+
+	// true if a member name has been written and its value has not
+	afterName bool
+}
+
+// NewWriter creates a writer
+func NewWriter() *Writer {
+	w := new(Writer)
+	return w
+}
+
+// GetDataBuffer returns the encoded data
+func (w *Writer) GetDataBuffer() []byte {
+	return w.dataBuffer
+}
+
+// writeSeparator writes the comma separating a member or an element from the previous one
+func (w *Writer) writeSeparator() {
+	if w.afterName {
+		w.afterName = false
+		return
+	}
+	if len(w.hasItems) == 0 {
+		return
+	}
+	if w.hasItems[len(w.hasItems)-1] {
+		w.dataBuffer = append(w.dataBuffer, ',')
+	}
+	w.hasItems[len(w.hasItems)-1] = true
+}
+
+// writeValue writes a JSON value
+func (w *Writer) writeValue(value string) {
+	w.writeSeparator()
+	w.dataBuffer = append(w.dataBuffer, value...)
+}
+
+// writeString writes a JSON string, only the characters JSON requires are escaped
+func (w *Writer) writeString(value string) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	w.writeValue(strings.TrimSuffix(buffer.String(), "\n"))
+}
+
+// BeginObject begins the encoding of a SEQUENCE or SET, components are written with WriteName followed by their value
+func (w *Writer) BeginObject() {
+	w.writeValue("{")
+	w.hasItems = append(w.hasItems, false)
+}
+
+// WriteName writes the name of the next member of the object being written
+func (w *Writer) WriteName(name string) {
+	w.writeString(name)
+	w.dataBuffer = append(w.dataBuffer, ':')
+	w.afterName = true
+}
+
+// EndObject ends the encoding of a SEQUENCE or SET
+func (w *Writer) EndObject() {
+	w.hasItems = w.hasItems[:len(w.hasItems)-1]
+	w.dataBuffer = append(w.dataBuffer, '}')
+}
+
+// BeginArray begins the encoding of a SEQUENCE OF or SET OF, components are written as they come
+func (w *Writer) BeginArray() {
+	w.writeValue("[")
+	w.hasItems = append(w.hasItems, false)
+}
+
+// EndArray ends the encoding of a SEQUENCE OF or SET OF
+func (w *Writer) EndArray() {
+	w.hasItems = w.hasItems[:len(w.hasItems)-1]
+	w.dataBuffer = append(w.dataBuffer, ']')
+}
+
+// BeginChoice begins the encoding of a CHOICE as an object with a single member, the value of the alternative must be written next
+func (w *Writer) BeginChoice(alternative string) {
+	w.BeginObject()
+	w.WriteName(alternative)
+}
+
+// EndChoice ends the encoding of a CHOICE
+func (w *Writer) EndChoice() {
+	w.EndObject()
+}
+
+// WriteBoolean encodes a BOOLEAN as true or false
+func (w *Writer) WriteBoolean(value bool) {
+	w.writeValue(strconv.FormatBool(value))
+}
+
+// WriteNull encodes NULL as null
+func (w *Writer) WriteNull() {
+	w.writeValue("null")
+}
+
+// WriteInteger encodes an INTEGER as a number
+func (w *Writer) WriteInteger(value int64) {
+	w.writeValue(strconv.FormatInt(value, 10))
+}
+
+// WriteBigInteger encodes an INTEGER as a number
+func (w *Writer) WriteBigInteger(value *big.Int) {
+	w.writeValue(value.String())
+}
+
+// WriteEnumerated encodes an ENUMERATED value as the string of its identifier
+func (w *Writer) WriteEnumerated(identifier string) {
+	w.writeString(identifier)
+}
+
+// WriteReal encodes a REAL as a number, or as "INF", "-INF", "NaN" or "-0" for the special values
+func (w *Writer) WriteReal(value float64) {
+	switch {
+	case math.IsInf(value, 1):
+		w.writeString(plusInfinity)
+	case math.IsInf(value, -1):
+		w.writeString(minusInfinity)
+	case math.IsNaN(value):
+		w.writeString(notANumber)
+	case value == 0 && math.Signbit(value):
+		w.writeString(minusZero)
+	default:
+		w.writeValue(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+}
+
+// WriteOctetString encodes an OCTET STRING as a string of hexadecimal digits
+func (w *Writer) WriteOctetString(value []byte) {
+	w.writeString(strings.ToUpper(hex.EncodeToString(value)))
+}
+
+// WriteBitString encodes a BIT STRING as an object with the members "value" (hexadecimal digits) and "length" (number of bits)
+func (w *Writer) WriteBitString(value types.BitString) {
+	hexValue, length := bitStringToHex(value)
+	w.BeginObject()
+	w.WriteName("value")
+	w.writeString(hexValue)
+	w.WriteName("length")
+	w.WriteInteger(int64(length))
+	w.EndObject()
+}
+
+// WriteFixedSizeBitString encodes a BIT STRING with a fixed SIZE constraint as a string of hexadecimal digits
+func (w *Writer) WriteFixedSizeBitString(value types.BitString) {
+	hexValue, _ := bitStringToHex(value)
+	w.writeString(hexValue)
+}
+
+// bitStringToHex returns the hexadecimal digits of a BIT STRING, unused bits set to zero, and its length
+func bitStringToHex(value types.BitString) (string, int) {
+	length := value.ValidLength()
+	buffer := make([]byte, (length+7)/8)
+	copy(buffer, value.Bytes)
+	if length%8 != 0 {
+		buffer[len(buffer)-1] &= 0xFF << uint(8-length%8)
+	}
+	return strings.ToUpper(hex.EncodeToString(buffer)), length
+}
+
+// WriteRestrictedCharacterString encodes a character string as a string
+func (w *Writer) WriteRestrictedCharacterString(value string) {
+	w.writeString(value)
+}
+
+// WriteObjectIdentifier encodes an OBJECT IDENTIFIER as a string in dotted notation
+func (w *Writer) WriteObjectIdentifier(value types.ObjectIdentifier) {
+	w.writeString(value.String())
+}
+
+// WriteRelativeOID encodes a RELATIVE-OID as a string in dotted notation
+func (w *Writer) WriteRelativeOID(value types.RelativeOID) {
+	w.writeString(value.String())
+}
+
+// WriteUTCTime encodes a UTCTime as a string
+func (w *Writer) WriteUTCTime(value types.UTCTime) {
+	w.writeString(string(value))
+}
+
+// WriteGeneralizedTime encodes a GeneralizedTime as a string
+func (w *Writer) WriteGeneralizedTime(value types.GeneralizedTime) {
+	w.writeString(string(value))
+}
+
+// WriteTime encodes a TIME, DATE, TIME-OF-DAY, DATE-TIME or DURATION as a string
+func (w *Writer) WriteTime(value string) {
+	w.writeString(value)
+}
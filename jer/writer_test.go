@@ -0,0 +1,78 @@
+package jer
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestWriteSequence(t *testing.T) {
+	writer := NewWriter()
+
+	writer.BeginObject()
+	writer.WriteName("version")
+	writer.WriteInteger(2)
+	writer.WriteName("oid")
+	writer.WriteObjectIdentifier(types.ObjectIdentifier{1, 2, 840, 113549})
+	writer.WriteName("flags")
+	writer.WriteBitString(types.BitString{Bytes: []byte{0xaf}, Length: 4})
+	writer.WriteName("list")
+	writer.BeginArray()
+	writer.WriteBoolean(true)
+	writer.WriteNull()
+	writer.BeginChoice("name")
+	writer.WriteRestrictedCharacterString("a \"b\" <c>")
+	writer.EndChoice()
+	writer.EndArray()
+	writer.WriteName("empty")
+	writer.BeginArray()
+	writer.EndArray()
+	writer.EndObject()
+
+	expected := `{"version":2,"oid":"1.2.840.113549","flags":{"value":"A0","length":4},"list":[true,null,{"name":"a \"b\" <c>"}],"empty":[]}`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWritePrimitives(t *testing.T) {
+	writer := NewWriter()
+
+	writer.BeginArray()
+	writer.WriteOctetString([]byte{0x01, 0xab})
+	writer.WriteFixedSizeBitString(types.BitString{Bytes: []byte{0xff, 0xff}, Length: 12})
+	writer.WriteRelativeOID(types.RelativeOID{8571, 3})
+	writer.WriteEnumerated("red")
+	writer.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 70))
+	writer.WriteInteger(-5)
+	writer.WriteUTCTime(types.UTCTime("910506234540Z"))
+	writer.WriteGeneralizedTime(types.GeneralizedTime("19910506234540.5Z"))
+	writer.WriteTime("2020-01-31")
+	writer.EndArray()
+
+	expected := `["01AB","FFF0","8571.3","red",1180591620717411303424,-5,"910506234540Z","19910506234540.5Z","2020-01-31"]`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWriteReal(t *testing.T) {
+	writer := NewWriter()
+
+	writer.BeginArray()
+	writer.WriteReal(1.5)
+	writer.WriteReal(-1e300)
+	writer.WriteReal(0)
+	writer.WriteReal(math.Copysign(0, -1))
+	writer.WriteReal(math.Inf(1))
+	writer.WriteReal(math.Inf(-1))
+	writer.WriteReal(math.NaN())
+	writer.EndArray()
+
+	expected := `[1.5,-1e+300,0,"-0","INF","-INF","NaN"]`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
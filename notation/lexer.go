@@ -0,0 +1,266 @@
+package notation
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// token kinds
+const (
+	wordToken    = iota // identifier or keyword
+	numberToken         // number or realnumber
+	cstringToken        // "..." (text without the quotes, "" unescaped)
+	bstringToken        // '...'B (binary digits)
+	hstringToken        // '...'H (hexadecimal digits)
+	symbolToken         // { } , : ( ) -
+	endToken            // end of text
+)
+
+// token is a lexical item of the value notation
+type token struct {
+	kind   int
+	text   string
+	line   int
+	column int
+}
+
+// describe returns the token as it should appear in an error message
+func (t token) describe() string {
+	switch t.kind {
+	case endToken:
+		return "end of text"
+	case cstringToken:
+		return strconv.Quote(t.text)
+	case bstringToken:
+		return "'" + t.text + "'B"
+	case hstringToken:
+		return "'" + t.text + "'H"
+	}
+	return t.text
+}
+
+// lexer splits a text into tokens, skipping white space and comments
+type lexer struct {
+	// text being split
+	text []rune
+
+	// current position in text
+	offset int
+	line   int
+	column int
+}
+
+// positionError returns an error located at a line and column
+func positionError(line int, column int, message string) error {
+	return errors.New("line " + strconv.Itoa(line) + ", column " + strconv.Itoa(column) + ": " + message)
+}
+
+// tokenize returns the tokens of a text, the last one being an endToken
+func tokenize(text string) ([]token, error) {
+	l := &lexer{text: []rune(text), line: 1, column: 1}
+	tokens := []token{}
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.kind == endToken {
+			return tokens, nil
+		}
+	}
+}
+
+// peek returns the rune at offset i from the current position, 0 past the end
+func (l *lexer) peek(i int) rune {
+	if l.offset+i >= len(l.text) {
+		return 0
+	}
+	return l.text[l.offset+i]
+}
+
+// advance moves the current position by one rune
+func (l *lexer) advance() {
+	if l.text[l.offset] == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	l.offset++
+}
+
+// skipSpaceAndComments skips white space, -- comments (ended by -- or by the end of the line) and /* */ comments (which nest)
+func (l *lexer) skipSpaceAndComments() error {
+	for l.offset < len(l.text) {
+		switch {
+		case unicode.IsSpace(l.peek(0)):
+			l.advance()
+		case l.peek(0) == '-' && l.peek(1) == '-':
+			l.advance()
+			l.advance()
+			for l.offset < len(l.text) && l.peek(0) != '\n' && l.peek(0) != '\r' {
+				if l.peek(0) == '-' && l.peek(1) == '-' {
+					l.advance()
+					l.advance()
+					break
+				}
+				l.advance()
+			}
+		case l.peek(0) == '/' && l.peek(1) == '*':
+			line, column := l.line, l.column
+			depth := 0
+			for {
+				if l.offset >= len(l.text) {
+					return positionError(line, column, "unterminated comment")
+				}
+				if l.peek(0) == '/' && l.peek(1) == '*' {
+					depth++
+					l.advance()
+				} else if l.peek(0) == '*' && l.peek(1) == '/' {
+					depth--
+					l.advance()
+				}
+				l.advance()
+				if depth == 0 {
+					break
+				}
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// next returns the next token
+func (l *lexer) next() (token, error) {
+	err := l.skipSpaceAndComments()
+	if err != nil {
+		return token{}, err
+	}
+	t := token{line: l.line, column: l.column}
+	if l.offset >= len(l.text) {
+		t.kind = endToken
+		return t, nil
+	}
+	c := l.peek(0)
+	switch {
+	case isLetter(c):
+		t.kind = wordToken
+		t.text = l.readWord()
+	case isDigit(c):
+		t.kind = numberToken
+		t.text, err = l.readNumber()
+	case c == '"':
+		t.kind = cstringToken
+		t.text, err = l.readCString()
+	case c == '\'':
+		t.kind, t.text, err = l.readBinaryString()
+	case strings.ContainsRune("{},:()-", c):
+		t.kind = symbolToken
+		t.text = string(c)
+		l.advance()
+	default:
+		err = errors.New("unexpected character " + strconv.QuoteRune(c))
+	}
+	if err != nil {
+		return token{}, positionError(t.line, t.column, err.Error())
+	}
+	return t, nil
+}
+
+// readWord reads an identifier or a keyword: letters, digits and hyphens not followed by another hyphen
+func (l *lexer) readWord() string {
+	start := l.offset
+	for isLetter(l.peek(0)) || isDigit(l.peek(0)) || l.peek(0) == '-' && l.peek(1) != '-' && (isLetter(l.peek(1)) || isDigit(l.peek(1))) {
+		l.advance()
+	}
+	return string(l.text[start:l.offset])
+}
+
+// readNumber reads a number, or a realnumber with a fraction and/or an exponent
+func (l *lexer) readNumber() (string, error) {
+	start := l.offset
+	l.readDigits()
+	if l.peek(0) == '.' && isDigit(l.peek(1)) {
+		l.advance()
+		l.readDigits()
+	}
+	if (l.peek(0) == 'e' || l.peek(0) == 'E') && (isDigit(l.peek(1)) || l.peek(1) == '-' && isDigit(l.peek(2))) {
+		l.advance()
+		if l.peek(0) == '-' {
+			l.advance()
+		}
+		l.readDigits()
+	}
+	text := string(l.text[start:l.offset])
+	if len(text) > 1 && text[0] == '0' && isDigit(rune(text[1])) {
+		return "", errors.New("number " + text + " has leading zeros")
+	}
+	if isLetter(l.peek(0)) {
+		return "", errors.New("unexpected character " + strconv.QuoteRune(l.peek(0)) + " after number")
+	}
+	return text, nil
+}
+
+// readDigits reads decimal digits
+func (l *lexer) readDigits() {
+	for isDigit(l.peek(0)) {
+		l.advance()
+	}
+}
+
+// readCString reads a cstring, a quotation mark inside the string is written twice
+func (l *lexer) readCString() (string, error) {
+	var builder strings.Builder
+	l.advance()
+	for {
+		if l.offset >= len(l.text) {
+			return "", errors.New("unterminated cstring")
+		}
+		c := l.peek(0)
+		l.advance()
+		if c == '"' {
+			if l.peek(0) != '"' {
+				return builder.String(), nil
+			}
+			l.advance()
+		}
+		builder.WriteRune(c)
+	}
+}
+
+// readBinaryString reads a bstring ('...'B) or an hstring ('...'H), white space inside the quotes is ignored
+func (l *lexer) readBinaryString() (int, string, error) {
+	var builder strings.Builder
+	l.advance()
+	for l.peek(0) != '\'' {
+		if l.offset >= len(l.text) {
+			return 0, "", errors.New("unterminated bstring or hstring")
+		}
+		if !unicode.IsSpace(l.peek(0)) {
+			builder.WriteRune(l.peek(0))
+		}
+		l.advance()
+	}
+	l.advance()
+	digits := builder.String()
+	switch l.peek(0) {
+	case 'B':
+		l.advance()
+		if strings.Trim(digits, "01") != "" {
+			return 0, "", errors.New("invalid bstring '" + digits + "'B")
+		}
+		return bstringToken, digits, nil
+	case 'H':
+		l.advance()
+		if strings.Trim(digits, "0123456789ABCDEF") != "" {
+			return 0, "", errors.New("invalid hstring '" + digits + "'H")
+		}
+		return hstringToken, digits, nil
+	}
+	return 0, "", errors.New("expecting B or H after '" + digits + "'")
+}
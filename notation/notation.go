@@ -0,0 +1,96 @@
+// Package notation prints and parses values in ASN.1 value notation (X.680), e.g. { version 2, oid { 1 2 840 113549 }, flags '1010'B }
+// values are represented without their type:
+// BOOLEAN: bool, NULL: nil, INTEGER: int64 (or any Go integer when printing) or *big.Int, REAL: float64
+// ENUMERATED and named values: Identifier, character strings and time types: string
+// OCTET STRING: []byte, BIT STRING: types.BitString, OBJECT IDENTIFIER: types.ObjectIdentifier (types.RelativeOID when printing)
+// SEQUENCE and SET: Sequence, SEQUENCE OF and SET OF: SequenceOf, CHOICE: Choice
+package notation
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Identifier is a value written as an identifier, for instance an ENUMERATED value
+type Identifier string
+
+// Component is a named component of a SEQUENCE or SET value
+type Component struct {
+	Name  string
+	Value interface{}
+}
+
+// Sequence is a SEQUENCE or SET value, written { name value, ... }
+type Sequence []Component
+
+// SequenceOf is a SEQUENCE OF or SET OF value, written { value, ... }
+type SequenceOf []interface{}
+
+// Choice is a CHOICE value, written alternative : value
+type Choice struct {
+	Alternative string
+	Value       interface{}
+}
+
+// Get returns the value of the component with the given name, false if the SEQUENCE has no such component
+func (s Sequence) Get(name string) (interface{}, bool) {
+	for _, component := range s {
+		if component.Name == name {
+			return component.Value, true
+		}
+	}
+	return nil, false
+}
+
+// keywords which are values rather than identifiers
+const (
+	trueKeyword          = "TRUE"
+	falseKeyword         = "FALSE"
+	nullKeyword          = "NULL"
+	plusInfinityKeyword  = "PLUS-INFINITY"
+	minusInfinityKeyword = "MINUS-INFINITY"
+	notANumberKeyword    = "NOT-A-NUMBER"
+)
+
+// isIdentifier returns true if name is a valid identifier: a lower case letter followed by letters, digits and single hyphens, not ending with a hyphen
+func isIdentifier(name string) bool {
+	if name == "" || name[0] < 'a' || name[0] > 'z' || name[len(name)-1] == '-' || strings.Contains(name, "--") {
+		return false
+	}
+	for _, c := range name {
+		if !isLetter(c) && !isDigit(c) && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// isLetter returns true if c is an ASCII letter
+func isLetter(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isDigit returns true if c is a decimal digit
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
+
+// formatReal returns the realnumber text of a finite REAL value, e.g. 1.5E2 or 0.0
+func formatReal(value float64) string {
+	if value == 0 {
+		return "0.0"
+	}
+	text := strconv.FormatFloat(value, 'E', -1, 64)
+	index := strings.IndexByte(text, 'E')
+	mantissa, exponent := text[:index], text[index+1:]
+	exponent = strings.TrimPrefix(exponent, "+")
+	negative := strings.HasPrefix(exponent, "-")
+	exponent = strings.TrimLeft(strings.TrimPrefix(exponent, "-"), "0")
+	if exponent == "" {
+		return mantissa + "E0"
+	}
+	if negative {
+		exponent = "-" + exponent
+	}
+	return mantissa + "E" + exponent
+}
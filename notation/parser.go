@@ -0,0 +1,270 @@
+package notation
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Parse returns the value written in value notation in text, errors give the line and column of the offending token
+// the type of the value is not known, so that:
+// a number is an int64 (a *big.Int if it does not fit), a number with a fraction or an exponent is a float64
+// arcs in braces without commas, e.g. { 1 2 840 113549 } or { iso(1) member-body(2) }, are a types.ObjectIdentifier (a types.RelativeOID if they cannot start an OBJECT IDENTIFIER)
+// braces holding identifiers followed by values are a Sequence, other braces are a SequenceOf ({ 5 } is a SequenceOf)
+func Parse(text string) (interface{}, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if p.current().kind != endToken {
+		return nil, p.unexpected("end of text")
+	}
+	return value, nil
+}
+
+// parser builds values from tokens
+type parser struct {
+	// tokens of the text, the last one being an endToken
+	tokens []token
+
+	// index of the current token
+	index int
+}
+
+// current returns the current token
+func (p *parser) current() token {
+	return p.tokens[p.index]
+}
+
+// lookAhead returns the token following the current one by n
+func (p *parser) lookAhead(n int) token {
+	if p.index+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.index+n]
+}
+
+// isSymbol returns true if t is the symbol given
+func isSymbol(t token, symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+// unexpected returns an error located at the current token
+func (p *parser) unexpected(expected string) error {
+	t := p.current()
+	return positionError(t.line, t.column, "expecting "+expected+", found "+t.describe())
+}
+
+// expectSymbol consumes the symbol given, raises an error if it is not the current token
+func (p *parser) expectSymbol(symbol string) error {
+	if !isSymbol(p.current(), symbol) {
+		return p.unexpected(symbol)
+	}
+	p.index++
+	return nil
+}
+
+// parseValue parses any value
+func (p *parser) parseValue() (interface{}, error) {
+	t := p.current()
+	switch t.kind {
+	case numberToken:
+		p.index++
+		return parseNumber(t, false)
+	case cstringToken:
+		p.index++
+		return t.text, nil
+	case bstringToken:
+		p.index++
+		value := types.BitString{Bytes: make([]byte, (len(t.text)+7)/8), Length: len(t.text)}
+		for i, c := range t.text {
+			value.Set(i, c == '1')
+		}
+		return value, nil
+	case hstringToken:
+		p.index++
+		digits := t.text
+		if len(digits)%2 != 0 {
+			digits += "0"
+		}
+		return hex.DecodeString(digits)
+	case wordToken:
+		return p.parseWord()
+	}
+	switch {
+	case isSymbol(t, "-"):
+		p.index++
+		number := p.current()
+		if number.kind != numberToken {
+			return nil, p.unexpected("a number")
+		}
+		p.index++
+		return parseNumber(number, true)
+	case isSymbol(t, "{"):
+		return p.parseBraces()
+	}
+	return nil, p.unexpected("a value")
+}
+
+// parseNumber returns the value of a number (int64 or *big.Int) or of a realnumber (float64)
+func parseNumber(t token, negative bool) (interface{}, error) {
+	text := t.text
+	if negative {
+		text = "-" + text
+	}
+	if strings.ContainsAny(text, ".eE") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsInf(value, 0) {
+			return nil, positionError(t.line, t.column, "invalid realnumber "+text)
+		}
+		return value, nil
+	}
+	if text == "-0" {
+		return nil, positionError(t.line, t.column, "-0 is not a valid number")
+	}
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err == nil {
+		return value, nil
+	}
+	bigValue, _ := new(big.Int).SetString(text, 10)
+	return bigValue, nil
+}
+
+// parseWord parses a keyword value, an identifier value or a CHOICE value (alternative : value)
+func (p *parser) parseWord() (interface{}, error) {
+	t := p.current()
+	p.index++
+	switch t.text {
+	case trueKeyword:
+		return true, nil
+	case falseKeyword:
+		return false, nil
+	case nullKeyword:
+		return nil, nil
+	case plusInfinityKeyword:
+		return math.Inf(1), nil
+	case minusInfinityKeyword:
+		return math.Inf(-1), nil
+	case notANumberKeyword:
+		return math.NaN(), nil
+	}
+	if !isIdentifier(t.text) {
+		p.index--
+		return nil, p.unexpected("a value")
+	}
+	if !isSymbol(p.current(), ":") {
+		return Identifier(t.text), nil
+	}
+	p.index++
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return Choice{Alternative: t.text, Value: value}, nil
+}
+
+// parseBraces parses a value in braces: arcs, a SEQUENCE or SET value or a SEQUENCE OF or SET OF value
+func (p *parser) parseBraces() (interface{}, error) {
+	err := p.expectSymbol("{")
+	if err != nil {
+		return nil, err
+	}
+	if isSymbol(p.current(), "}") {
+		p.index++
+		return SequenceOf{}, nil
+	}
+	arcs, isArcs := p.parseArcs()
+	if isArcs {
+		if len(arcs) >= 2 && arcs[0] <= 2 && (arcs[0] == 2 || arcs[1] <= 39) {
+			return types.ObjectIdentifier(arcs), nil
+		}
+		return types.RelativeOID(arcs), nil
+	}
+	first, second := p.current(), p.lookAhead(1)
+	if first.kind == wordToken && isIdentifier(first.text) && !isSymbol(second, ",") && !isSymbol(second, "}") && !isSymbol(second, ":") {
+		return p.parseSequence()
+	}
+	return p.parseSequenceOf()
+}
+
+// parseArcs parses the arcs of an OBJECT IDENTIFIER or RELATIVE-OID up to the closing brace, returns false (without consuming anything) if the braces hold something else
+func (p *parser) parseArcs() ([]int64, bool) {
+	start := p.index
+	arcs := []int64{}
+	for !isSymbol(p.current(), "}") {
+		t := p.current()
+		if t.kind == wordToken && isIdentifier(t.text) && isSymbol(p.lookAhead(1), "(") && isSymbol(p.lookAhead(3), ")") {
+			p.index += 2
+			t = p.current()
+			p.index += 2
+		} else {
+			p.index++
+		}
+		arc, err := strconv.ParseInt(t.text, 10, 64)
+		if t.kind != numberToken || err != nil {
+			p.index = start
+			return nil, false
+		}
+		arcs = append(arcs, arc)
+	}
+	if len(arcs) < 2 {
+		p.index = start
+		return nil, false
+	}
+	p.index++
+	return arcs, true
+}
+
+// parseSequence parses the components of a SEQUENCE or SET value up to the closing brace
+func (p *parser) parseSequence() (interface{}, error) {
+	value := Sequence{}
+	for {
+		t := p.current()
+		if t.kind != wordToken || !isIdentifier(t.text) {
+			return nil, p.unexpected("a component identifier")
+		}
+		p.index++
+		component, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, Component{Name: t.text, Value: component})
+		if isSymbol(p.current(), "}") {
+			p.index++
+			return value, nil
+		}
+		if !isSymbol(p.current(), ",") {
+			return nil, p.unexpected(", or }")
+		}
+		p.index++
+	}
+}
+
+// parseSequenceOf parses the elements of a SEQUENCE OF or SET OF value up to the closing brace
+func (p *parser) parseSequenceOf() (interface{}, error) {
+	value := SequenceOf{}
+	for {
+		element, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		value = append(value, element)
+		if isSymbol(p.current(), "}") {
+			p.index++
+			return value, nil
+		}
+		if !isSymbol(p.current(), ",") {
+			return nil, p.unexpected(", or }")
+		}
+		p.index++
+	}
+}
@@ -0,0 +1,112 @@
+package notation
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestParseSequence(t *testing.T) {
+	text := `{ version 2, -- comment
+		oid { iso(1) member-body(2) 840 113549 }, /* comment /* nested */ */
+		flags '1010'B, name nickname : "say ""hi""", list { TRUE, NULL, red, '01A'H, -1.5e-1 }, empty {} }`
+	value, err := Parse(text)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expected := Sequence{
+		{Name: "version", Value: int64(2)},
+		{Name: "oid", Value: types.ObjectIdentifier{1, 2, 840, 113549}},
+		{Name: "flags", Value: types.BitString{Bytes: []byte{0xa0}, Length: 4}},
+		{Name: "name", Value: Choice{Alternative: "nickname", Value: `say "hi"`}},
+		{Name: "list", Value: SequenceOf{true, nil, Identifier("red"), []byte{0x01, 0xa0}, -0.15}},
+		{Name: "empty", Value: SequenceOf{}},
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Fatal("Wrong:", value)
+	}
+	flags, _ := value.(Sequence).Get("flags")
+	if !bytes.Equal(flags.(types.BitString).Bytes, []byte{0xa0}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	texts := []string{
+		`{ version 2, oid { 1 2 840 113549 }, flags '1010'B }`,
+		`{ { 8571 3 }, { 1, 2 }, { a 1 }, { 5 } }`,
+		`big : 1180591620717411303424`,
+		`{ 1.5E2, -2.5E-3, 0.0, -0.0, PLUS-INFINITY, MINUS-INFINITY, -5, 0 }`,
+		`{ read, write }`,
+		`{}`,
+	}
+	for _, text := range texts {
+		value, err := Parse(text)
+		if err != nil {
+			t.Fatal("Wrong:", text, err)
+		}
+		formatted, err := Format(value)
+		if err != nil || formatted != text {
+			t.Fatal("Wrong:", formatted, err)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	value, _ := Parse(`{ 3 5 }`)
+	if !reflect.DeepEqual(value, types.RelativeOID{3, 5}) {
+		t.Fatal("Wrong:", value)
+	}
+	value, _ = Parse(`1180591620717411303424`)
+	if value.(*big.Int).Cmp(new(big.Int).Lsh(big.NewInt(1), 70)) != 0 {
+		t.Fatal("Wrong:", value)
+	}
+	value, _ = Parse(`NOT-A-NUMBER`)
+	if !math.IsNaN(value.(float64)) {
+		t.Fatal("Wrong:", value)
+	}
+	value, _ = Parse(`"line 1
+line 2"`)
+	if value != "line 1\nline 2" {
+		t.Fatal("Wrong:", value)
+	}
+	value, _ = Parse(`''B`)
+	if value.(types.BitString).Length != 0 {
+		t.Fatal("Wrong:", value)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	texts := []string{
+		`{ version 2 oid { 1 2 } }`,
+		`{ version 2,`,
+		`"abc`,
+		`'102'B`,
+		`'0a'H`,
+		`'01'X`,
+		`007`,
+		`-0`,
+		`-TRUE`,
+		`Version`,
+		`1 2`,
+		`{ a 1, 2 }`,
+		`/* unterminated`,
+		`#`,
+	}
+	for _, text := range texts {
+		_, err := Parse(text)
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+
+	_, err := Parse("{ version 2,\n  oid { 1 2 } flags '1'B }")
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2, column 15:") {
+		t.Fatal("Wrong:", err)
+	}
+}
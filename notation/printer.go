@@ -0,0 +1,176 @@
+package notation
+
+import (
+	"encoding/hex"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Format returns the value notation of a value, raises an error if the value (or a component) cannot be represented
+func Format(value interface{}) (string, error) {
+	var builder strings.Builder
+	err := format(&builder, value)
+	if err != nil {
+		return "", err
+	}
+	return builder.String(), nil
+}
+
+// format writes the value notation of a value
+func format(builder *strings.Builder, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		builder.WriteString(nullKeyword)
+	case bool:
+		if v {
+			builder.WriteString(trueKeyword)
+		} else {
+			builder.WriteString(falseKeyword)
+		}
+	case *big.Int:
+		builder.WriteString(v.String())
+	case float64:
+		formatRealValue(builder, v)
+	case float32:
+		formatRealValue(builder, float64(v))
+	case Identifier:
+		if !isIdentifier(string(v)) {
+			return errors.New("invalid identifier " + strconv.Quote(string(v)))
+		}
+		builder.WriteString(string(v))
+	case string:
+		builder.WriteString(`"` + strings.ReplaceAll(v, `"`, `""`) + `"`)
+	case types.UTCTime:
+		return format(builder, string(v))
+	case types.GeneralizedTime:
+		return format(builder, string(v))
+	case []byte:
+		builder.WriteString("'" + strings.ToUpper(hex.EncodeToString(v)) + "'H")
+	case types.BitString:
+		formatBitString(builder, v)
+	case types.ObjectIdentifier:
+		formatArcs(builder, v)
+	case types.RelativeOID:
+		formatArcs(builder, v)
+	case Sequence:
+		return formatSequence(builder, v)
+	case SequenceOf:
+		return formatSequenceOf(builder, v)
+	case []interface{}:
+		return formatSequenceOf(builder, v)
+	case Choice:
+		if !isIdentifier(v.Alternative) {
+			return errors.New("invalid alternative identifier " + strconv.Quote(v.Alternative))
+		}
+		builder.WriteString(v.Alternative + " : ")
+		return format(builder, v.Value)
+	default:
+		return formatInteger(builder, value)
+	}
+	return nil
+}
+
+// formatInteger writes a value of any Go integer type
+func formatInteger(builder *strings.Builder, value interface{}) error {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		builder.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		builder.WriteString(strconv.FormatUint(v.Uint(), 10))
+	default:
+		return errors.New("cannot format a value of type " + v.Type().String())
+	}
+	return nil
+}
+
+// formatRealValue writes a REAL value, special values are written PLUS-INFINITY, MINUS-INFINITY and NOT-A-NUMBER
+func formatRealValue(builder *strings.Builder, value float64) {
+	switch {
+	case math.IsInf(value, 1):
+		builder.WriteString(plusInfinityKeyword)
+	case math.IsInf(value, -1):
+		builder.WriteString(minusInfinityKeyword)
+	case math.IsNaN(value):
+		builder.WriteString(notANumberKeyword)
+	case math.Signbit(value):
+		builder.WriteString("-" + formatReal(-value))
+	default:
+		builder.WriteString(formatReal(value))
+	}
+}
+
+// formatBitString writes a BIT STRING as a bstring, e.g. '1010'B
+func formatBitString(builder *strings.Builder, value types.BitString) {
+	length := value.ValidLength()
+	builder.WriteByte('\'')
+	for i := 0; i < length; i++ {
+		if value.Get(i) {
+			builder.WriteByte('1')
+		} else {
+			builder.WriteByte('0')
+		}
+	}
+	builder.WriteString("'B")
+}
+
+// formatArcs writes the arcs of an OBJECT IDENTIFIER or RELATIVE-OID, e.g. { 1 2 840 113549 }
+func formatArcs(builder *strings.Builder, arcs []int64) {
+	builder.WriteString("{")
+	for _, arc := range arcs {
+		builder.WriteString(" " + strconv.FormatInt(arc, 10))
+	}
+	builder.WriteString(" }")
+}
+
+// formatSequence writes a SEQUENCE or SET value, e.g. { version 2, flags '1010'B }
+func formatSequence(builder *strings.Builder, value Sequence) error {
+	if len(value) == 0 {
+		builder.WriteString("{}")
+		return nil
+	}
+	for i, component := range value {
+		if !isIdentifier(component.Name) {
+			return errors.New("invalid component identifier " + strconv.Quote(component.Name))
+		}
+		if i == 0 {
+			builder.WriteString("{ ")
+		} else {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(component.Name + " ")
+		err := format(builder, component.Value)
+		if err != nil {
+			return err
+		}
+	}
+	builder.WriteString(" }")
+	return nil
+}
+
+// formatSequenceOf writes a SEQUENCE OF or SET OF value, e.g. { 1, 2, 3 }
+func formatSequenceOf(builder *strings.Builder, value []interface{}) error {
+	if len(value) == 0 {
+		builder.WriteString("{}")
+		return nil
+	}
+	for i, element := range value {
+		if i == 0 {
+			builder.WriteString("{ ")
+		} else {
+			builder.WriteString(", ")
+		}
+		err := format(builder, element)
+		if err != nil {
+			return err
+		}
+	}
+	builder.WriteString(" }")
+	return nil
+}
@@ -0,0 +1,51 @@
+package notation
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestFormatSequence(t *testing.T) {
+	value := Sequence{
+		{Name: "version", Value: 2},
+		{Name: "oid", Value: types.ObjectIdentifier{1, 2, 840, 113549}},
+		{Name: "flags", Value: types.BitString{Bytes: []byte{0xaf}, Length: 4}},
+		{Name: "name", Value: Choice{Alternative: "nickname", Value: `say "hi"`}},
+		{Name: "list", Value: SequenceOf{true, nil, Identifier("red"), []byte{0x01, 0xab}}},
+		{Name: "empty", Value: SequenceOf{}},
+	}
+	text, err := Format(value)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expected := `{ version 2, oid { 1 2 840 113549 }, flags '1010'B, name nickname : "say ""hi""", list { TRUE, NULL, red, '01AB'H }, empty {} }`
+	if text != expected {
+		t.Fatal("Wrong:", text)
+	}
+}
+
+func TestFormatNumbers(t *testing.T) {
+	values := []interface{}{int8(-5), uint64(math.MaxUint64), new(big.Int).Lsh(big.NewInt(1), 70), 150.0, -0.0025, 0.0,
+		math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.NaN(), types.RelativeOID{8571, 3}}
+	expected := []string{"-5", "18446744073709551615", "1180591620717411303424", "1.5E2", "-2.5E-3", "0.0",
+		"-0.0", "PLUS-INFINITY", "MINUS-INFINITY", "NOT-A-NUMBER", "{ 8571 3 }"}
+	for i, value := range values {
+		text, err := Format(value)
+		if err != nil || text != expected[i] {
+			t.Fatal("Wrong:", text, err)
+		}
+	}
+}
+
+func TestFormatInvalid(t *testing.T) {
+	values := []interface{}{Identifier("Red"), Sequence{{Name: "a-", Value: 1}}, Choice{Alternative: "", Value: 1}, struct{}{}, SequenceOf{1, map[string]int{}}}
+	for _, value := range values {
+		_, err := Format(value)
+		if err == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
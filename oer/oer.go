@@ -0,0 +1,84 @@
+// Package oer implements the Octet Encoding Rules (X.696), BASIC-OER and CANONICAL-OER
+package oer
+
+import (
+	"math"
+
+	"github.com/yafred/asn1-go/internal/octets"
+)
+
+// DefaultMaxLength is the maximum length determinant accepted by a Reader unless SetMaxLength is called
+const DefaultMaxLength = octets.DefaultMaxLength
+
+// tag classes of the alternatives of a CHOICE
+const (
+	UniversalClass   = 0
+	ApplicationClass = 1
+	ContextClass     = 2
+	PrivateClass     = 3
+)
+
+// IntegerConstraint is the OER-visible constraint of an INTEGER, the zero value means unconstrained
+// extensible constraints are not OER-visible
+type IntegerConstraint struct {
+	Lb         int64
+	Ub         int64
+	HasLb      bool
+	HasUb      bool
+	Extensible bool
+}
+
+// Size is the OER-visible SIZE constraint of a string, the zero value means SIZE(0..MAX)
+type Size struct {
+	Lb         int
+	Ub         int
+	HasUb      bool
+	Extensible bool
+}
+
+// contains returns true if value satisfies the constraint
+func (c IntegerConstraint) contains(value int64) bool {
+	return (!c.HasLb || value >= c.Lb) && (!c.HasUb || value <= c.Ub)
+}
+
+// fixedSize returns the number of octets of an INTEGER encoded without length determinant and true if the encoding is signed,
+// the number of octets is 0 if a length determinant is needed
+func (c IntegerConstraint) fixedSize() (int, bool) {
+	if c.Extensible || !c.HasLb {
+		return 0, true
+	}
+	if c.Lb >= 0 {
+		switch {
+		case !c.HasUb:
+			return 0, false
+		case c.Ub <= math.MaxUint8:
+			return 1, false
+		case c.Ub <= math.MaxUint16:
+			return 2, false
+		case c.Ub <= math.MaxUint32:
+			return 4, false
+		}
+		return 8, false
+	}
+	switch {
+	case !c.HasUb:
+		return 0, true
+	case c.Lb >= math.MinInt8 && c.Ub <= math.MaxInt8:
+		return 1, true
+	case c.Lb >= math.MinInt16 && c.Ub <= math.MaxInt16:
+		return 2, true
+	case c.Lb >= math.MinInt32 && c.Ub <= math.MaxInt32:
+		return 4, true
+	}
+	return 8, true
+}
+
+// contains returns true if length satisfies the constraint
+func (s Size) contains(length int) bool {
+	return length >= s.Lb && (!s.HasUb || length <= s.Ub)
+}
+
+// isFixed returns true if the size is fixed, values are then encoded without length determinant
+func (s Size) isFixed() bool {
+	return !s.Extensible && s.HasUb && s.Lb == s.Ub
+}
@@ -0,0 +1,458 @@
+package oer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/yafred/asn1-go/ber"
+	"github.com/yafred/asn1-go/internal/octets"
+	"github.com/yafred/asn1-go/types"
+)
+
+// Reader helps decode ASN.1 values encoded in OER
+type Reader struct {
+	// stream to read from
+	in io.Reader
+
+	// CANONICAL-OER, non-canonical encodings are rejected
+	canonical bool
+
+	// maximum length determinant accepted (DefaultMaxLength if not set)
+	maxLength int64
+
+	// number of bytes read from the stream
+	offset int64
+
+	// encodings of the SET OF components being read (innermost last), recorded to check their order
+	setOfComponents [][]byte
+}
+
+// NewReader creates a reader for BASIC-OER
+func NewReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.in = in
+	return r
+}
+
+// NewCanonicalReader creates a reader rejecting anything that is not valid CANONICAL-OER
+func NewCanonicalReader(in io.Reader) *Reader {
+	r := NewReader(in)
+	r.canonical = true
+	return r
+}
+
+// IsCanonical returns true if the reader rejects encodings which are not CANONICAL-OER
+func (r *Reader) IsCanonical() bool {
+	return r.canonical
+}
+
+// SetMaxLength sets the maximum length determinant accepted by ReadLength
+func (r *Reader) SetMaxLength(value int64) {
+	r.maxLength = value
+}
+
+// GetOffset returns the number of bytes read from the stream
+func (r *Reader) GetOffset() int64 {
+	return r.offset
+}
+
+// read fills buffer from the stream, raises an error if end of stream is reached
+func (r *Reader) read(buffer []byte) error {
+	n, err := io.ReadFull(r.in, buffer)
+	r.offset += int64(n)
+	for i := range r.setOfComponents {
+		r.setOfComponents[i] = append(r.setOfComponents[i], buffer[:n]...)
+	}
+	return err
+}
+
+// readByte reads a single byte, raises an error if end of stream is reached
+func (r *Reader) readByte() (byte, error) {
+	buffer := make([]byte, 1)
+	err := r.read(buffer)
+	return buffer[0], err
+}
+
+// readBytes reads nBytes bytes, raises an error if end of stream is reached
+func (r *Reader) readBytes(nBytes int) ([]byte, error) {
+	buffer := make([]byte, nBytes)
+	err := r.read(buffer)
+	return buffer, err
+}
+
+// readUnsigned reads a non-negative binary integer of nBytes octets (at most 8)
+func (r *Reader) readUnsigned(nBytes int) (uint64, error) {
+	if nBytes > 8 {
+		return 0, errors.New("integers over 8 bytes not supported")
+	}
+	buffer, err := r.readBytes(nBytes)
+	if err != nil {
+		return 0, err
+	}
+	var value uint64
+	for _, aByte := range buffer {
+		value = value<<8 | uint64(aByte)
+	}
+	return value, nil
+}
+
+// readSigned reads a 2's complement binary integer of nBytes octets (at most 8)
+func (r *Reader) readSigned(nBytes int) (int64, error) {
+	value, err := r.readUnsigned(nBytes)
+	if err != nil || nBytes == 0 {
+		return 0, err
+	}
+	shift := uint(64 - 8*nBytes)
+	return int64(value<<shift) >> shift, nil // sign extension
+}
+
+// ReadLength decodes a length determinant, raises an error if it is greater than the maximum length
+func (r *Reader) ReadLength() (int, error) {
+	first, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	if first < 128 {
+		return int(first), nil
+	}
+	nBytes := int(first & 0x7F)
+	if nBytes == 0 {
+		return 0, errors.New("invalid length determinant")
+	}
+	buffer, err := r.readBytes(nBytes)
+	if err != nil {
+		return 0, err
+	}
+	if r.canonical && buffer[0] == 0 {
+		return 0, errors.New("non-minimal length determinant")
+	}
+	for len(buffer) > 0 && buffer[0] == 0 {
+		buffer = buffer[1:]
+	}
+	if len(buffer) > 8 {
+		return 0, errors.New("lengths over 8 bytes not supported")
+	}
+	var length uint64
+	for _, aByte := range buffer {
+		length = length<<8 | uint64(aByte)
+	}
+	if r.canonical && length < 128 {
+		return 0, errors.New("non-minimal length determinant")
+	}
+	maxLength := r.maxLength
+	if maxLength == 0 {
+		maxLength = DefaultMaxLength
+	}
+	if length > uint64(maxLength) {
+		return 0, errors.New("length exceeds maximum length")
+	}
+	return int(length), nil
+}
+
+// ReadBoolean decodes a BOOLEAN, CANONICAL-OER only accepts 0x00 and 0xFF
+func (r *Reader) ReadBoolean() (bool, error) {
+	value, err := r.readByte()
+	if err != nil {
+		return false, err
+	}
+	if r.canonical && value != 0x00 && value != 0xFF {
+		return false, errors.New("BOOLEAN value must be 0x00 or 0xFF")
+	}
+	return value != 0x00, nil
+}
+
+// ReadInteger decodes an INTEGER on a number of octets derived from its constraint, raises an error if value does not satisfy the constraint
+func (r *Reader) ReadInteger(constraint IntegerConstraint) (int64, error) {
+	fixedBytes, isSigned := constraint.fixedSize()
+	nBytes := fixedBytes
+	if fixedBytes == 0 {
+		length, err := r.ReadLength()
+		if err != nil {
+			return 0, err
+		}
+		if length == 0 {
+			return 0, errors.New("zero length integer")
+		}
+		nBytes = length
+	}
+
+	var value int64
+	if isSigned {
+		signedValue, err := r.readSigned(nBytes)
+		if err != nil {
+			return 0, err
+		}
+		value = signedValue
+		if r.canonical && fixedBytes == 0 && nBytes != octets.SignedBytes(value) {
+			return 0, errors.New("non-minimal integer encoding")
+		}
+	} else {
+		unsignedValue, err := r.readUnsigned(nBytes)
+		if err != nil {
+			return 0, err
+		}
+		if r.canonical && fixedBytes == 0 && nBytes != octets.UnsignedBytes(unsignedValue) {
+			return 0, errors.New("non-minimal integer encoding")
+		}
+		value = int64(unsignedValue)
+		if value < 0 {
+			return 0, errors.New("INTEGER value overflows int64")
+		}
+	}
+
+	if !constraint.Extensible && !constraint.contains(value) {
+		return 0, errors.New("INTEGER value out of range")
+	}
+	return value, nil
+}
+
+// ReadEnumerated decodes an ENUMERATED value
+func (r *Reader) ReadEnumerated() (int, error) {
+	first, err := r.readByte()
+	if err != nil {
+		return 0, err
+	}
+	if first < 128 {
+		return int(first), nil
+	}
+	nBytes := int(first & 0x7F)
+	if nBytes == 0 {
+		return 0, errors.New("invalid ENUMERATED encoding")
+	}
+	value, err := r.readSigned(nBytes)
+	if err != nil {
+		return 0, err
+	}
+	if r.canonical && (value >= 0 && value <= 127 || nBytes != octets.SignedBytes(value)) {
+		return 0, errors.New("non-minimal ENUMERATED encoding")
+	}
+	return int(value), nil
+}
+
+// ReadOctetString decodes an OCTET STRING, fixed sizes are read without length determinant, raises an error if value does not satisfy the constraint
+func (r *Reader) ReadOctetString(size Size) ([]byte, error) {
+	length := size.Lb
+	if !size.isFixed() {
+		var err error
+		length, err = r.ReadLength()
+		if err != nil {
+			return nil, err
+		}
+		if !size.Extensible && !size.contains(length) {
+			return nil, errors.New("size out of range")
+		}
+	}
+	return r.readBytes(length)
+}
+
+// ReadRestrictedCharacterString decodes a string of a type with one octet per character (IA5String, VisibleString...) or a UTF8String
+func (r *Reader) ReadRestrictedCharacterString(size Size) (string, error) {
+	value, err := r.ReadOctetString(size)
+	return string(value), err
+}
+
+// ReadBitString decodes a BIT STRING, fixed sizes are read without length determinant nor unused bits octet
+// CANONICAL-OER only accepts zero unused bits, raises an error if value does not satisfy the constraint
+func (r *Reader) ReadBitString(size Size) (types.BitString, error) {
+	if size.isFixed() {
+		buffer, err := r.readBytes((size.Lb + 7) / 8)
+		if err != nil {
+			return types.BitString{}, err
+		}
+		err = r.checkUnusedBits(buffer, 8*len(buffer)-size.Lb)
+		return types.BitString{Bytes: buffer, Length: size.Lb}, err
+	}
+
+	buffer, err := r.ReadOctetString(Size{})
+	if err != nil {
+		return types.BitString{}, err
+	}
+	value, err := r.decodeBitString(buffer)
+	if err != nil {
+		return types.BitString{}, err
+	}
+	if !size.Extensible && !size.contains(value.Length) {
+		return types.BitString{}, errors.New("size out of range")
+	}
+	return value, nil
+}
+
+// decodeBitString decodes the unused bits octet and the bits of a BIT STRING
+func (r *Reader) decodeBitString(buffer []byte) (types.BitString, error) {
+	if len(buffer) == 0 {
+		return types.BitString{}, errors.New("missing unused bits octet")
+	}
+	unusedBits := int(buffer[0])
+	if unusedBits > 7 || len(buffer) == 1 && unusedBits != 0 {
+		return types.BitString{}, errors.New("invalid number of unused bits")
+	}
+	err := r.checkUnusedBits(buffer[1:], unusedBits)
+	return types.BitString{Bytes: buffer[1:], Length: 8*(len(buffer)-1) - unusedBits}, err
+}
+
+// checkUnusedBits raises an error if one of the unusedBits trailing bits of buffer is not zero, in CANONICAL-OER only
+func (r *Reader) checkUnusedBits(buffer []byte, unusedBits int) error {
+	if r.canonical && unusedBits > 0 && buffer[len(buffer)-1]&(1<<uint(unusedBits)-1) != 0 {
+		return errors.New("unused bits must be zero")
+	}
+	return nil
+}
+
+// ReadObjectIdentifier decodes an OBJECT IDENTIFIER
+func (r *Reader) ReadObjectIdentifier() (types.ObjectIdentifier, error) {
+	contents, err := r.ReadOpenType()
+	if err != nil {
+		return nil, err
+	}
+	return ber.NewReader(bytes.NewReader(contents)).ReadObjectIdentifier(len(contents))
+}
+
+// ReadRelativeOID decodes a RELATIVE-OID
+func (r *Reader) ReadRelativeOID() (types.RelativeOID, error) {
+	contents, err := r.ReadOpenType()
+	if err != nil {
+		return nil, err
+	}
+	return ber.NewReader(bytes.NewReader(contents)).ReadRelativeOID(len(contents))
+}
+
+// ReadReal decodes a REAL, CANONICAL-OER only accepts the CER/DER form
+func (r *Reader) ReadReal() (float64, error) {
+	contents, err := r.ReadOpenType()
+	if err != nil {
+		return 0, err
+	}
+	reader := ber.NewReader(bytes.NewReader(contents))
+	if r.canonical {
+		reader = ber.NewDERReader(bytes.NewReader(contents))
+	}
+	return reader.ReadReal(len(contents))
+}
+
+// ReadPreamble decodes the bitmap preceding the components of a SEQUENCE or SET, made of nBits bits
+// CANONICAL-OER only accepts zero padding bits
+func (r *Reader) ReadPreamble(nBits int) ([]bool, error) {
+	buffer, err := r.readBytes((nBits + 7) / 8)
+	if err != nil {
+		return nil, err
+	}
+	err = r.checkUnusedBits(buffer, 8*len(buffer)-nBits)
+	if err != nil {
+		return nil, err
+	}
+	return readBits(buffer, nBits), nil
+}
+
+// readBits returns the first nBits bits of buffer
+func readBits(buffer []byte, nBits int) []bool {
+	values := make([]bool, nBits)
+	for i := range values {
+		values[i] = buffer[i/8]&(0x80>>uint(i%8)) != 0
+	}
+	return values
+}
+
+// ReadExtensionAdditionsBitmap decodes the presence bitmap of the extension additions of a SEQUENCE or SET
+func (r *Reader) ReadExtensionAdditionsBitmap() ([]bool, error) {
+	buffer, err := r.ReadOctetString(Size{})
+	if err != nil {
+		return nil, err
+	}
+	value, err := r.decodeBitString(buffer)
+	if err != nil {
+		return nil, err
+	}
+	if value.Length == 0 {
+		return nil, errors.New("empty extension additions bitmap")
+	}
+	return readBits(value.Bytes, value.Length), nil
+}
+
+// ReadOpenType decodes an open type and returns the complete encoding of its value
+func (r *Reader) ReadOpenType() ([]byte, error) {
+	return r.ReadOctetString(Size{})
+}
+
+// ReadChoiceTag decodes the tag of the chosen alternative of a CHOICE, returns its class and number
+func (r *Reader) ReadChoiceTag() (int, uint64, error) {
+	first, err := r.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+	class := int(first >> 6)
+	if first&0x3F != 0x3F {
+		return class, uint64(first & 0x3F), nil
+	}
+	var number uint64
+	for i := 0; ; i++ {
+		aByte, err := r.readByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		if i == 0 && aByte == 0x80 {
+			return 0, 0, errors.New("non-minimal tag number")
+		}
+		if number>>57 != 0 {
+			return 0, 0, errors.New("tag number too large")
+		}
+		number = number<<7 | uint64(aByte&0x7F)
+		if aByte&0x80 == 0 {
+			break
+		}
+	}
+	if number < 63 {
+		return 0, 0, errors.New("non-minimal tag number")
+	}
+	return class, number, nil
+}
+
+// ReadQuantity decodes the number of components of a SEQUENCE OF or SET OF
+func (r *Reader) ReadQuantity() (int, error) {
+	nBytes, err := r.ReadLength()
+	if err != nil {
+		return 0, err
+	}
+	if nBytes == 0 {
+		return 0, errors.New("zero length quantity")
+	}
+	count, err := r.readUnsigned(nBytes)
+	if err != nil {
+		return 0, err
+	}
+	if r.canonical && nBytes != octets.UnsignedBytes(count) {
+		return 0, errors.New("non-minimal quantity")
+	}
+	if int(count) < 0 || uint64(int(count)) != count {
+		return 0, errors.New("quantity too large")
+	}
+	return int(count), nil
+}
+
+// ReadSetOfComponents decodes the count components of a SET OF by calling readComponent for each of them
+// the canonical reader raises an error if the components are not in ascending order of their encodings
+func (r *Reader) ReadSetOfComponents(count int, readComponent func(r *Reader, index int) error) error {
+	var previous []byte
+	for i := 0; i < count; i++ {
+		if !r.canonical {
+			err := readComponent(r, i)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		r.setOfComponents = append(r.setOfComponents, []byte{})
+		err := readComponent(r, i)
+		encoding := r.setOfComponents[len(r.setOfComponents)-1]
+		r.setOfComponents = r.setOfComponents[:len(r.setOfComponents)-1]
+		if err != nil {
+			return err
+		}
+		if previous != nil && octets.CompareSetOfEncodings(previous, encoding) > 0 {
+			return errors.New("SET OF components not in canonical order")
+		}
+		previous = encoding
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package oer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReadLength(t *testing.T) {
+	buffer := [...]byte{0x05, 0x81, 0xc8, 0x82, 0x01, 0x00, 0x82, 0x00, 0x05}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	for _, expected := range []int{5, 200, 256, 5} {
+		length, err := reader.ReadLength()
+		if err != nil || length != expected {
+			t.Fatal("Wrong")
+		}
+	}
+	if reader.GetOffset() != 9 {
+		t.Fatal("Should be 9")
+	}
+
+	reader = NewCanonicalReader(bytes.NewReader(buffer[6:]))
+	_, err := reader.ReadLength()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	// over DefaultMaxLength
+	buffer2 := [...]byte{0x85, 0x01, 0x00, 0x00, 0x00, 0x00}
+	reader = NewReader(bytes.NewReader(buffer2[0:]))
+	_, err = reader.ReadLength()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadInteger(t *testing.T) {
+	tests := []struct {
+		buffer     []byte
+		constraint IntegerConstraint
+		expected   int64
+	}{
+		{[]byte{0x05}, IntegerConstraint{Lb: 0, Ub: 255, HasLb: true, HasUb: true}, 5},
+		{[]byte{0x00, 0x05}, IntegerConstraint{Lb: 0, Ub: 65535, HasLb: true, HasUb: true}, 5},
+		{[]byte{0xff}, IntegerConstraint{Lb: -128, Ub: 127, HasLb: true, HasUb: true}, -1},
+		{[]byte{0xff, 0xff}, IntegerConstraint{Lb: -1000, Ub: 1000, HasLb: true, HasUb: true}, -1},
+		{[]byte{0x02, 0x01, 0x00}, IntegerConstraint{}, 256},
+		{[]byte{0x02, 0xff, 0x7f}, IntegerConstraint{}, -129},
+		{[]byte{0x01, 0x80}, IntegerConstraint{Lb: 0, HasLb: true}, 128},
+		{[]byte{0x02, 0x01, 0x2c}, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true}, 300},
+	}
+
+	for _, test := range tests {
+		reader := NewCanonicalReader(bytes.NewReader(test.buffer))
+		value, err := reader.ReadInteger(test.constraint)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if value != test.expected {
+			t.Fatal("Wrong:", value)
+		}
+	}
+
+	// out of range
+	buffer := [...]byte{0xc8}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+	_, err := reader.ReadInteger(IntegerConstraint{Lb: 0, Ub: 100, HasLb: true, HasUb: true})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadEnumerated(t *testing.T) {
+	buffer := [...]byte{0x05, 0x82, 0x00, 0xc8, 0x81, 0xff}
+	reader := NewCanonicalReader(bytes.NewReader(buffer[0:]))
+
+	for _, expected := range []int{5, 200, -1} {
+		value, err := reader.ReadEnumerated()
+		if err != nil || value != expected {
+			t.Fatal("Wrong")
+		}
+	}
+}
+
+func TestReadBitString(t *testing.T) {
+	buffer := [...]byte{0xa0, 0x02, 0x04, 0xa0, 0x01, 0x00}
+	reader := NewCanonicalReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadBitString(Size{Lb: 4, Ub: 4, HasUb: true})
+	if err != nil || value.Length != 4 || value.Bytes[0] != 0xa0 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadBitString(Size{})
+	if err != nil || value.Length != 4 || value.Bytes[0] != 0xa0 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadBitString(Size{})
+	if err != nil || value.Length != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadStrings(t *testing.T) {
+	buffer := [...]byte{0x01, 0x02, 0x03, 0x61, 0x62, 0x63, 0x03, 0x2a, 0x86, 0x48, 0x03, 0x80, 0x00, 0x01}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	octets, err := reader.ReadOctetString(Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil || false == bytes.Equal(octets, []byte{0x01, 0x02}) {
+		t.Fatal("Wrong")
+	}
+	text, err := reader.ReadRestrictedCharacterString(Size{})
+	if err != nil || text != "abc" {
+		t.Fatal("Wrong")
+	}
+	oid, err := reader.ReadObjectIdentifier()
+	if err != nil || len(oid) != 3 || oid[0] != 1 || oid[1] != 2 || oid[2] != 840 {
+		t.Fatal("Wrong")
+	}
+	real, err := reader.ReadReal()
+	if err != nil || real != 1 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadSequenceBitmaps(t *testing.T) {
+	buffer := [...]byte{0xa0, 0x02, 0x06, 0x40, 0x01, 0xff}
+	reader := NewCanonicalReader(bytes.NewReader(buffer[0:]))
+
+	preamble, err := reader.ReadPreamble(3)
+	if err != nil || len(preamble) != 3 || !preamble[0] || preamble[1] || !preamble[2] {
+		t.Fatal("Wrong")
+	}
+	bitmap, err := reader.ReadExtensionAdditionsBitmap()
+	if err != nil || len(bitmap) != 2 || bitmap[0] || !bitmap[1] {
+		t.Fatal("Wrong")
+	}
+	openType, err := reader.ReadOpenType()
+	if err != nil || false == bytes.Equal(openType, []byte{0xff}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadChoiceTag(t *testing.T) {
+	buffer := [...]byte{0x81, 0xbf, 0x64, 0xff, 0x81, 0x48}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	class, number, err := reader.ReadChoiceTag()
+	if err != nil || class != ContextClass || number != 1 {
+		t.Fatal("Wrong")
+	}
+	class, number, err = reader.ReadChoiceTag()
+	if err != nil || class != ContextClass || number != 100 {
+		t.Fatal("Wrong")
+	}
+	class, number, err = reader.ReadChoiceTag()
+	if err != nil || class != PrivateClass || number != 200 {
+		t.Fatal("Wrong")
+	}
+
+	// 5 must be encoded in the first octet
+	buffer2 := [...]byte{0xbf, 0x05}
+	reader = NewReader(bytes.NewReader(buffer2[0:]))
+	_, _, err = reader.ReadChoiceTag()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadSetOfComponents(t *testing.T) {
+	values := make([]string, 3)
+	readComponent := func(r *Reader, index int) error {
+		var err error
+		values[index], err = r.ReadRestrictedCharacterString(Size{})
+		return err
+	}
+
+	buffer := []byte{0x01, 0x03, 0x01, 0x61, 0x01, 0x62, 0x02, 0x61, 0x62}
+	reader := NewCanonicalReader(bytes.NewReader(buffer))
+	count, err := reader.ReadQuantity()
+	if err != nil || count != 3 {
+		t.Fatal("Wrong")
+	}
+	err = reader.ReadSetOfComponents(count, readComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if values[0] != "a" || values[1] != "b" || values[2] != "ab" {
+		t.Fatal("Wrong")
+	}
+
+	buffer = []byte{0x01, 0x62, 0x02, 0x61, 0x62, 0x01, 0x61}
+	reader = NewReader(bytes.NewReader(buffer))
+	err = reader.ReadSetOfComponents(3, readComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	reader = NewCanonicalReader(bytes.NewReader(buffer))
+	err = reader.ReadSetOfComponents(3, readComponent)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCanonicalReaderRejects(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		read   func(r *Reader) error
+	}{
+		// BOOLEAN other than 0x00 and 0xFF
+		{[]byte{0x01}, func(r *Reader) error {
+			_, err := r.ReadBoolean()
+			return err
+		}},
+		// non-minimal length determinant
+		{[]byte{0x81, 0x05}, func(r *Reader) error {
+			_, err := r.ReadLength()
+			return err
+		}},
+		// non-minimal INTEGER
+		{[]byte{0x02, 0x00, 0x05}, func(r *Reader) error {
+			_, err := r.ReadInteger(IntegerConstraint{})
+			return err
+		}},
+		// non-minimal unsigned INTEGER
+		{[]byte{0x02, 0x00, 0x80}, func(r *Reader) error {
+			_, err := r.ReadInteger(IntegerConstraint{Lb: 0, HasLb: true})
+			return err
+		}},
+		// ENUMERATED in long form
+		{[]byte{0x81, 0x05}, func(r *Reader) error {
+			_, err := r.ReadEnumerated()
+			return err
+		}},
+		// unused bits not zero
+		{[]byte{0x02, 0x04, 0xaf}, func(r *Reader) error {
+			_, err := r.ReadBitString(Size{})
+			return err
+		}},
+		// padding bits of the preamble not zero
+		{[]byte{0xa1}, func(r *Reader) error {
+			_, err := r.ReadPreamble(3)
+			return err
+		}},
+		// non-minimal quantity
+		{[]byte{0x02, 0x00, 0x03}, func(r *Reader) error {
+			_, err := r.ReadQuantity()
+			return err
+		}},
+	}
+
+	for i, test := range tests {
+		err := test.read(NewReader(bytes.NewReader(test.buffer)))
+		if err != nil {
+			t.Fatal("Wrong:", i, err)
+		}
+		err = test.read(NewCanonicalReader(bytes.NewReader(test.buffer)))
+		if err == nil {
+			t.Fatal("Wrong:", i)
+		}
+	}
+}
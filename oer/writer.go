@@ -0,0 +1,262 @@
+package oer
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+
+	"github.com/yafred/asn1-go/ber"
+	"github.com/yafred/asn1-go/internal/octets"
+	"github.com/yafred/asn1-go/types"
+)
+
+// Writer helps encode ASN.1 values in OER, octets are written forward
+type Writer struct {
+	// encoded data
+	dataBuffer []byte
+
+	// CANONICAL-OER
+	canonical bool
+}
+
+// NewWriter creates a writer for BASIC-OER
+func NewWriter() *Writer {
+	w := new(Writer)
+	return w
+}
+
+// NewCanonicalWriter creates a writer for CANONICAL-OER
+func NewCanonicalWriter() *Writer {
+	w := new(Writer)
+	w.canonical = true
+	return w
+}
+
+// IsCanonical returns true if the writer encodes in CANONICAL-OER
+func (w *Writer) IsCanonical() bool {
+	return w.canonical
+}
+
+// GetDataBuffer returns the encoded data
+func (w *Writer) GetDataBuffer() []byte {
+	return w.dataBuffer
+}
+
+// writeByte writes a single byte
+func (w *Writer) writeByte(value byte) {
+	w.dataBuffer = append(w.dataBuffer, value)
+}
+
+// writeBytes writes bytes
+func (w *Writer) writeBytes(value []byte) {
+	w.dataBuffer = append(w.dataBuffer, value...)
+}
+
+// writeUnsigned writes the nBytes least significant octets of value, most significant first
+func (w *Writer) writeUnsigned(value uint64, nBytes int) {
+	for i := nBytes - 1; i >= 0; i-- {
+		w.writeByte(byte(value >> uint(8*i)))
+	}
+}
+
+// WriteLength encodes a length determinant, in short form below 128 and in long form with the minimum number of octets otherwise
+func (w *Writer) WriteLength(length int) {
+	if length < 128 {
+		w.writeByte(byte(length))
+		return
+	}
+	nBytes := octets.UnsignedBytes(uint64(length))
+	w.writeByte(0x80 | byte(nBytes))
+	w.writeUnsigned(uint64(length), nBytes)
+}
+
+// WriteBoolean encodes a BOOLEAN
+func (w *Writer) WriteBoolean(value bool) {
+	if value {
+		w.writeByte(0xFF)
+	} else {
+		w.writeByte(0x00)
+	}
+}
+
+// WriteInteger encodes an INTEGER on a number of octets derived from its constraint, raises an error if value does not satisfy the constraint
+func (w *Writer) WriteInteger(value int64, constraint IntegerConstraint) error {
+	if !constraint.Extensible && !constraint.contains(value) {
+		return errors.New("INTEGER value out of range")
+	}
+	nBytes, isSigned := constraint.fixedSize()
+	switch {
+	case nBytes != 0:
+		w.writeUnsigned(uint64(value), nBytes)
+	case isSigned:
+		nBytes = octets.SignedBytes(value)
+		w.WriteLength(nBytes)
+		w.writeUnsigned(uint64(value), nBytes)
+	default:
+		nBytes = octets.UnsignedBytes(uint64(value))
+		w.WriteLength(nBytes)
+		w.writeUnsigned(uint64(value), nBytes)
+	}
+	return nil
+}
+
+// WriteEnumerated encodes an ENUMERATED value, in a single octet from 0 to 127
+func (w *Writer) WriteEnumerated(value int) {
+	if value >= 0 && value <= 127 {
+		w.writeByte(byte(value))
+		return
+	}
+	nBytes := octets.SignedBytes(int64(value))
+	w.writeByte(0x80 | byte(nBytes))
+	w.writeUnsigned(uint64(value), nBytes)
+}
+
+// WriteOctetString encodes an OCTET STRING, fixed sizes are written without length determinant, raises an error if value does not satisfy the constraint
+func (w *Writer) WriteOctetString(value []byte, size Size) error {
+	if !size.Extensible && !size.contains(len(value)) {
+		return errors.New("size out of range")
+	}
+	if !size.isFixed() {
+		w.WriteLength(len(value))
+	}
+	w.writeBytes(value)
+	return nil
+}
+
+// WriteRestrictedCharacterString encodes a string of a type with one octet per character (IA5String, VisibleString...) or a UTF8String
+// fixed sizes are written without length determinant, raises an error if value does not satisfy the constraint
+func (w *Writer) WriteRestrictedCharacterString(value string, size Size) error {
+	return w.WriteOctetString([]byte(value), size)
+}
+
+// WriteBitString encodes a BIT STRING, fixed sizes are written without length determinant nor unused bits octet
+// unused bits are always written as zero, raises an error if value does not satisfy the constraint
+func (w *Writer) WriteBitString(value types.BitString, size Size) error {
+	length := value.ValidLength()
+	if !size.Extensible && !size.contains(length) {
+		return errors.New("size out of range")
+	}
+	nBytes := (length + 7) / 8
+	if !size.isFixed() {
+		w.WriteLength(nBytes + 1)
+		w.writeByte(byte(8*nBytes - length))
+	}
+	w.writeBytes(value.Bytes[:nBytes])
+	if length%8 != 0 {
+		w.dataBuffer[len(w.dataBuffer)-1] &= 0xFF << uint(8-length%8)
+	}
+	return nil
+}
+
+// WriteObjectIdentifier encodes an OBJECT IDENTIFIER as a length determinant and its BER contents octets
+func (w *Writer) WriteObjectIdentifier(value types.ObjectIdentifier) {
+	contents := ber.NewWriter(10)
+	contents.WriteObjectIdentifier(value)
+	w.WriteOpenType(contents.GetDataBuffer())
+}
+
+// WriteRelativeOID encodes a RELATIVE-OID as a length determinant and its BER contents octets
+func (w *Writer) WriteRelativeOID(value types.RelativeOID) {
+	contents := ber.NewWriter(10)
+	contents.WriteRelativeOID(value)
+	w.WriteOpenType(contents.GetDataBuffer())
+}
+
+// WriteReal encodes a REAL as a length determinant and its contents octets, using the CER/DER form in CANONICAL-OER
+func (w *Writer) WriteReal(value float64) {
+	contents := ber.NewWriter(16)
+	if w.canonical {
+		contents = ber.NewDERWriter(16)
+	}
+	contents.WriteReal(value)
+	w.WriteOpenType(contents.GetDataBuffer())
+}
+
+// WritePreamble encodes the bitmap preceding the components of a SEQUENCE or SET:
+// the extension bit if the type is extensible, then one bit per OPTIONAL or DEFAULT component telling if it is present
+func (w *Writer) WritePreamble(values []bool) {
+	w.writeBits(values)
+}
+
+// writeBits writes bits, the last octet is padded with zero bits
+func (w *Writer) writeBits(values []bool) {
+	for i, bit := range values {
+		if i%8 == 0 {
+			w.writeByte(0x00)
+		}
+		if bit {
+			w.dataBuffer[len(w.dataBuffer)-1] |= 0x80 >> uint(i%8)
+		}
+	}
+}
+
+// WriteExtensionAdditionsBitmap encodes the presence bitmap of the extension additions of a SEQUENCE or SET
+// each present addition (or addition group) must then be written as an open type
+func (w *Writer) WriteExtensionAdditionsBitmap(present []bool) error {
+	if len(present) == 0 {
+		return errors.New("empty extension additions bitmap")
+	}
+	nBytes := (len(present) + 7) / 8
+	w.WriteLength(nBytes + 1)
+	w.writeByte(byte(8*nBytes - len(present)))
+	w.writeBits(present)
+	return nil
+}
+
+// WriteOpenType encodes the complete encoding of a value as an open type
+func (w *Writer) WriteOpenType(encoding []byte) {
+	w.WriteLength(len(encoding))
+	w.writeBytes(encoding)
+}
+
+// WriteChoiceTag encodes the tag of the chosen alternative of a CHOICE, its value must then be written (as an open type if it is an extension)
+func (w *Writer) WriteChoiceTag(class int, number uint64) {
+	if number < 63 {
+		w.writeByte(byte(class)<<6 | byte(number))
+		return
+	}
+	w.writeByte(byte(class)<<6 | 0x3F)
+	nBytes := (bits.Len64(number) + 6) / 7
+	for i := nBytes - 1; i > 0; i-- {
+		w.writeByte(0x80 | byte(number>>uint(7*i)))
+	}
+	w.writeByte(byte(number) & 0x7F)
+}
+
+// WriteQuantity encodes the number of components of a SEQUENCE OF or SET OF
+func (w *Writer) WriteQuantity(count int) {
+	nBytes := octets.UnsignedBytes(uint64(count))
+	w.WriteLength(nBytes)
+	w.writeUnsigned(uint64(count), nBytes)
+}
+
+// WriteSetOfComponents encodes the count components of a SET OF by calling writeComponent for each of them
+// the canonical writer encodes the components in ascending order of their encodings
+func (w *Writer) WriteSetOfComponents(count int, writeComponent func(w *Writer, index int) error) error {
+	if !w.canonical {
+		for i := 0; i < count; i++ {
+			err := writeComponent(w, i)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	encodings := make([][]byte, count)
+	for i := range encodings {
+		componentWriter := NewCanonicalWriter()
+		err := writeComponent(componentWriter, i)
+		if err != nil {
+			return err
+		}
+		encodings[i] = componentWriter.GetDataBuffer()
+	}
+	sort.SliceStable(encodings, func(i int, j int) bool {
+		return octets.CompareSetOfEncodings(encodings[i], encodings[j]) < 0
+	})
+	for _, encoding := range encodings {
+		w.writeBytes(encoding)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package oer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestWriteLength(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteLength(5)
+	writer.WriteLength(200)
+	writer.WriteLength(256)
+
+	expectedBuffer := [...]byte{0x05, 0x81, 0xc8, 0x82, 0x01, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteInteger(t *testing.T) {
+	tests := []struct {
+		value      int64
+		constraint IntegerConstraint
+		expected   []byte
+	}{
+		{5, IntegerConstraint{Lb: 0, Ub: 255, HasLb: true, HasUb: true}, []byte{0x05}},
+		{5, IntegerConstraint{Lb: 0, Ub: 65535, HasLb: true, HasUb: true}, []byte{0x00, 0x05}},
+		{1, IntegerConstraint{Lb: 0, Ub: 4294967295, HasLb: true, HasUb: true}, []byte{0x00, 0x00, 0x00, 0x01}},
+		{1, IntegerConstraint{Lb: 0, Ub: 4294967296, HasLb: true, HasUb: true}, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}},
+		{-1, IntegerConstraint{Lb: -128, Ub: 127, HasLb: true, HasUb: true}, []byte{0xff}},
+		{-1, IntegerConstraint{Lb: -1000, Ub: 1000, HasLb: true, HasUb: true}, []byte{0xff, 0xff}},
+		{256, IntegerConstraint{}, []byte{0x02, 0x01, 0x00}},
+		{-129, IntegerConstraint{}, []byte{0x02, 0xff, 0x7f}},
+		{128, IntegerConstraint{Lb: 0, HasLb: true}, []byte{0x01, 0x80}},
+		{-5, IntegerConstraint{Lb: -10, HasLb: true}, []byte{0x01, 0xfb}},
+		{300, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true}, []byte{0x02, 0x01, 0x2c}},
+	}
+
+	for _, test := range tests {
+		writer := NewWriter()
+		err := writer.WriteInteger(test.value, test.constraint)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if false == bytes.Equal(writer.GetDataBuffer(), test.expected) {
+			t.Fatal("Wrong:", test.value, writer.GetDataBuffer())
+		}
+	}
+
+	writer := NewWriter()
+	err := writer.WriteInteger(256, IntegerConstraint{Lb: 0, Ub: 255, HasLb: true, HasUb: true})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteEnumerated(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteEnumerated(5)
+	writer.WriteEnumerated(200)
+	writer.WriteEnumerated(-1)
+
+	expectedBuffer := [...]byte{0x05, 0x82, 0x00, 0xc8, 0x81, 0xff}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteBoolean(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteBoolean(true)
+	writer.WriteBoolean(false)
+
+	expectedBuffer := [...]byte{0xff, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteOctetString(t *testing.T) {
+	writer := NewWriter()
+
+	err := writer.WriteOctetString([]byte{0x01, 0x02}, Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteRestrictedCharacterString("abc", Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteOctetString([]byte{0x03}, Size{Lb: 2, Ub: 2, HasUb: true, Extensible: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x01, 0x02, 0x03, 0x61, 0x62, 0x63, 0x01, 0x03}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err = writer.WriteOctetString([]byte{0x03}, Size{Lb: 2, Ub: 2, HasUb: true})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteBitString(t *testing.T) {
+	writer := NewWriter()
+
+	value := types.BitString{Bytes: []byte{0xaf}, Length: 4}
+	err := writer.WriteBitString(value, Size{Lb: 4, Ub: 4, HasUb: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteBitString(value, Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteBitString(types.BitString{}, Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0xa0, 0x02, 0x04, 0xa0, 0x01, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteObjectIdentifier(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteObjectIdentifier(types.ObjectIdentifier{1, 2, 840})
+	writer.WriteRelativeOID(types.RelativeOID{840})
+
+	expectedBuffer := [...]byte{0x03, 0x2a, 0x86, 0x48, 0x02, 0x86, 0x48}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteReal(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteReal(1)
+	writer.WriteReal(0)
+
+	expectedBuffer := [...]byte{0x03, 0x80, 0x00, 0x01, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteSequenceBitmaps(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WritePreamble([]bool{true, false, true})
+	writer.WritePreamble(nil)
+	writer.WriteExtensionAdditionsBitmap([]bool{false, true})
+	writer.WriteOpenType([]byte{0xff})
+
+	expectedBuffer := [...]byte{0xa0, 0x02, 0x06, 0x40, 0x01, 0xff}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err := writer.WriteExtensionAdditionsBitmap(nil)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteChoiceTag(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteChoiceTag(ContextClass, 1)
+	writer.WriteChoiceTag(ContextClass, 100)
+	writer.WriteChoiceTag(PrivateClass, 200)
+
+	expectedBuffer := [...]byte{0x81, 0xbf, 0x64, 0xff, 0x81, 0x48}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteQuantity(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteQuantity(3)
+	writer.WriteQuantity(300)
+
+	expectedBuffer := [...]byte{0x01, 0x03, 0x02, 0x01, 0x2c}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteSetOfComponents(t *testing.T) {
+	values := []string{"b", "ab", "a"}
+	writeComponent := func(w *Writer, index int) error {
+		return w.WriteRestrictedCharacterString(values[index], Size{})
+	}
+
+	writer := NewWriter()
+	err := writer.WriteSetOfComponents(len(values), writeComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expectedBuffer := []byte{0x01, 0x62, 0x02, 0x61, 0x62, 0x01, 0x61}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer) {
+		t.Fatal("Wrong")
+	}
+
+	writer = NewCanonicalWriter()
+	err = writer.WriteSetOfComponents(len(values), writeComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expectedBuffer = []byte{0x01, 0x61, 0x01, 0x62, 0x02, 0x61, 0x62}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer) {
+		t.Fatal("Wrong")
+	}
+}
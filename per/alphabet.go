@@ -0,0 +1,126 @@
+package per
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// Alphabet is the effective permitted alphabet of a known-multiplier character string (X.691 30)
+type Alphabet struct {
+	// lowest and highest character values when the alphabet is a single range
+	first int64
+	last  int64
+
+	// characters in ascending order when the alphabet is not a single range
+	chars []rune
+}
+
+// NewAlphabet creates the alphabet made of the characters of chars (as in a PermittedAlphabet constraint)
+func NewAlphabet(chars string) Alphabet {
+	a := Alphabet{}
+	for _, c := range chars {
+		i := sort.Search(len(a.chars), func(i int) bool { return a.chars[i] >= c })
+		if i < len(a.chars) && a.chars[i] == c {
+			continue
+		}
+		a.chars = append(a.chars, 0)
+		copy(a.chars[i+1:], a.chars[i:])
+		a.chars[i] = c
+	}
+	return a
+}
+
+// NewAlphabetRange creates the alphabet made of all characters from first to last
+func NewAlphabetRange(first int64, last int64) Alphabet {
+	return Alphabet{first: first, last: last}
+}
+
+// IA5StringAlphabet is the alphabet of IA5String
+var IA5StringAlphabet = NewAlphabetRange(0, 127)
+
+// VisibleStringAlphabet is the alphabet of VisibleString (and ISO646String)
+var VisibleStringAlphabet = NewAlphabetRange(32, 126)
+
+// PrintableStringAlphabet is the alphabet of PrintableString
+var PrintableStringAlphabet = NewAlphabet(" '()+,-./0123456789:=?ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+
+// NumericStringAlphabet is the alphabet of NumericString
+var NumericStringAlphabet = NewAlphabet(" 0123456789")
+
+// BMPStringAlphabet is the alphabet of BMPString
+var BMPStringAlphabet = NewAlphabetRange(0, 0xFFFF)
+
+// UniversalStringAlphabet is the alphabet of UniversalString
+var UniversalStringAlphabet = NewAlphabetRange(0, 0xFFFFFFFF)
+
+// count returns the number of characters in the alphabet
+func (a Alphabet) count() uint64 {
+	if a.chars != nil {
+		return uint64(len(a.chars))
+	}
+	return uint64(a.last-a.first) + 1
+}
+
+// highest returns the highest character value in the alphabet
+func (a Alphabet) highest() int64 {
+	if a.chars != nil {
+		return int64(a.chars[len(a.chars)-1])
+	}
+	return a.last
+}
+
+// charBits returns the number of bits of a character (X.691 30.5.2)
+func (a Alphabet) charBits(aligned bool) int {
+	b := bits.Len64(a.count() - 1)
+	if aligned {
+		b2 := 1
+		for b2 < b {
+			b2 = b2 * 2
+		}
+		return b2
+	}
+	return b
+}
+
+// isIndexed returns true if characters are encoded as their index in the alphabet rather than their value (X.691 30.5.4)
+func (a Alphabet) isIndexed(nBits int) bool {
+	return nBits < 64 && uint64(a.highest()) > uint64(1)<<uint(nBits)-1
+}
+
+// encode returns the value encoding c, raises an error if c is not in the alphabet
+func (a Alphabet) encode(c rune, indexed bool) (uint64, error) {
+	if a.chars != nil {
+		i := sort.Search(len(a.chars), func(i int) bool { return a.chars[i] >= c })
+		if i == len(a.chars) || a.chars[i] != c {
+			return 0, errors.New("character not in permitted alphabet")
+		}
+		if indexed {
+			return uint64(i), nil
+		}
+		return uint64(c), nil
+	}
+	if int64(c) < a.first || int64(c) > a.last {
+		return 0, errors.New("character not in permitted alphabet")
+	}
+	if indexed {
+		return uint64(int64(c) - a.first), nil
+	}
+	return uint64(c), nil
+}
+
+// decode returns the character encoded by value, raises an error if value is not in the alphabet
+func (a Alphabet) decode(value uint64, indexed bool) (rune, error) {
+	if indexed {
+		if value >= a.count() {
+			return 0, errors.New("character index out of range")
+		}
+		if a.chars != nil {
+			return a.chars[value], nil
+		}
+		return rune(a.first + int64(value)), nil
+	}
+	c := rune(value)
+	_, err := a.encode(c, false)
+	return c, err
+}
@@ -0,0 +1,69 @@
+package per
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/yafred/asn1-go/internal/octets"
+)
+
+// WriteSetOfComponents encodes the count components of a SET OF by calling writeComponent for each of them (X.691 21)
+// the canonical writer encodes the components in ascending order of their encodings, so writeComponent may be called twice per component
+func (w *Writer) WriteSetOfComponents(count int, writeComponent func(w *Writer, index int) error) error {
+	order := make([]int, count)
+	for i := range order {
+		order[i] = i
+	}
+
+	if w.canonical {
+		encodings := make([][]byte, count)
+		for i := range encodings {
+			componentWriter := &Writer{aligned: w.aligned, canonical: true}
+			err := writeComponent(componentWriter, i)
+			if err != nil {
+				return err
+			}
+			encodings[i] = componentWriter.GetDataBuffer()
+		}
+		sort.SliceStable(order, func(i int, j int) bool {
+			return octets.CompareSetOfEncodings(encodings[order[i]], encodings[order[j]]) < 0
+		})
+	}
+
+	for _, i := range order {
+		err := writeComponent(w, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadSetOfComponents decodes the count components of a SET OF by calling readComponent for each of them (X.691 21)
+// the canonical reader raises an error if the components are not in ascending order of their encodings
+func (r *Reader) ReadSetOfComponents(count int, readComponent func(r *Reader, index int) error) error {
+	var previous []byte
+	for i := 0; i < count; i++ {
+		if !r.canonical {
+			err := readComponent(r, i)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		recorder := &Writer{aligned: r.aligned}
+		r.recorders = append(r.recorders, recorder)
+		err := readComponent(r, i)
+		r.recorders = r.recorders[:len(r.recorders)-1]
+		if err != nil {
+			return err
+		}
+		encoding := recorder.GetDataBuffer()
+		if previous != nil && octets.CompareSetOfEncodings(previous, encoding) > 0 {
+			return errors.New("SET OF components not in canonical order")
+		}
+		previous = encoding
+	}
+	return nil
+}
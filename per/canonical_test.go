@@ -0,0 +1,196 @@
+package per
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteSetOfComponents(t *testing.T) {
+	values := []int64{3, 1, 2}
+	writeComponent := func(w *Writer, index int) error {
+		return w.WriteConstrainedWholeNumber(values[index], 0, 7)
+	}
+
+	writer := NewWriter()
+	err := writer.WriteSetOfComponents(len(values), writeComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	// 011 001 010
+	expectedBuffer := [...]byte{0x65, 0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	writer = NewCanonicalWriter()
+	err = writer.WriteSetOfComponents(len(values), writeComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	// 001 010 011
+	expectedBuffer = [...]byte{0x29, 0x80}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteSetOfComponentsAligned(t *testing.T) {
+	values := [][]byte{[]byte("b"), []byte("a")}
+
+	writer := NewCanonicalAlignedWriter()
+	writer.WriteBit(true)
+	err := writer.WriteSetOfComponents(len(values), func(w *Writer, index int) error {
+		return w.WriteOctetString(values[index], Size{})
+	})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expectedBuffer := [...]byte{0x80, 0x01, 0x61, 0x01, 0x62}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadSetOfComponents(t *testing.T) {
+	values := make([]int64, 3)
+	readComponent := func(r *Reader, index int) error {
+		var err error
+		values[index], err = r.ReadConstrainedWholeNumber(0, 7)
+		return err
+	}
+
+	buffer := [...]byte{0x29, 0x80}
+	reader := NewCanonicalReader(bytes.NewReader(buffer[0:]))
+	err := reader.ReadSetOfComponents(3, readComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Fatal("Wrong")
+	}
+
+	buffer = [...]byte{0x65, 0x00}
+	reader = NewReader(bytes.NewReader(buffer[0:]))
+	err = reader.ReadSetOfComponents(3, readComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	reader = NewCanonicalReader(bytes.NewReader(buffer[0:]))
+	err = reader.ReadSetOfComponents(3, readComponent)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadSetOfComponentsAligned(t *testing.T) {
+	readComponent := func(r *Reader, index int) error {
+		_, err := r.ReadOctetString(Size{})
+		return err
+	}
+
+	buffer := [...]byte{0x80, 0x01, 0x61, 0x01, 0x62}
+	reader := NewCanonicalAlignedReader(bytes.NewReader(buffer[0:]))
+	reader.ReadBit()
+	err := reader.ReadSetOfComponents(2, readComponent)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	buffer = [...]byte{0x80, 0x01, 0x62, 0x01, 0x61}
+	reader = NewCanonicalAlignedReader(bytes.NewReader(buffer[0:]))
+	reader.ReadBit()
+	err = reader.ReadSetOfComponents(2, readComponent)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCanonicalReaderRejects(t *testing.T) {
+	tests := []struct {
+		buffer []byte
+		read   func(r *Reader) error
+	}{
+		// non-minimal length determinant
+		{[]byte{0x80, 0x03, 0x61, 0x62, 0x63}, func(r *Reader) error {
+			_, err := r.ReadOctetString(Size{})
+			return err
+		}},
+		// non-minimal unconstrained whole number
+		{[]byte{0x02, 0x00, 0x05}, func(r *Reader) error {
+			_, err := r.ReadUnconstrainedWholeNumber()
+			return err
+		}},
+		// non-minimal semi-constrained whole number
+		{[]byte{0x02, 0x00, 0x80}, func(r *Reader) error {
+			_, err := r.ReadSemiConstrainedWholeNumber(0)
+			return err
+		}},
+		// non-minimal normally small number
+		{[]byte{0x80, 0x82, 0x80}, func(r *Reader) error {
+			_, err := r.ReadNormallySmallNonNegativeWholeNumber()
+			return err
+		}},
+		// value of the root encoded as an extension
+		{[]byte{0x80, 0x81, 0x80}, func(r *Reader) error {
+			_, err := r.ReadInteger(IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true})
+			return err
+		}},
+		// size of the root encoded as an extension
+		{[]byte{0x80, 0x80, 0x80}, func(r *Reader) error {
+			_, err := r.ReadOctetString(Size{Lb: 1, Ub: 2, HasUb: true, Extensible: true})
+			return err
+		}},
+	}
+
+	for i, test := range tests {
+		err := test.read(NewReader(bytes.NewReader(test.buffer)))
+		if err != nil {
+			t.Fatal("Wrong:", i, err)
+		}
+		err = test.read(NewCanonicalReader(bytes.NewReader(test.buffer)))
+		if err == nil {
+			t.Fatal("Wrong:", i)
+		}
+	}
+}
+
+func TestCanonicalReaderRejectsPadding(t *testing.T) {
+	buffer := [...]byte{0xc0, 0x05}
+
+	reader := NewAlignedReader(bytes.NewReader(buffer[0:]))
+	reader.ReadBit()
+	value, err := reader.ReadConstrainedWholeNumber(0, 255)
+	if err != nil || value != 5 {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewCanonicalAlignedReader(bytes.NewReader(buffer[0:]))
+	reader.ReadBit()
+	_, err = reader.ReadConstrainedWholeNumber(0, 255)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestCanonicalReaderRejectsFragmentation(t *testing.T) {
+	// two fragments of 16K instead of one of 32K
+	buffer := []byte{0xc1}
+	buffer = append(buffer, make([]byte, 16384)...)
+	buffer = append(buffer, 0xc1)
+	buffer = append(buffer, make([]byte, 16384)...)
+	buffer = append(buffer, 0x00)
+
+	reader := NewReader(bytes.NewReader(buffer))
+	value, err := reader.ReadOctetString(Size{})
+	if err != nil || len(value) != 32768 {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewCanonicalReader(bytes.NewReader(buffer))
+	_, err = reader.ReadOctetString(Size{})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,49 @@
+// Package per implements the Packed Encoding Rules (X.691)
+package per
+
+import (
+	"math/bits"
+)
+
+// IntegerConstraint is the PER-visible constraint of an INTEGER, the zero value means unconstrained
+type IntegerConstraint struct {
+	Lb         int64
+	Ub         int64
+	HasLb      bool
+	HasUb      bool
+	Extensible bool
+}
+
+// Size is the PER-visible SIZE constraint of a BIT STRING or OCTET STRING, the zero value means SIZE(0..MAX)
+type Size struct {
+	Lb         int
+	Ub         int
+	HasUb      bool
+	Extensible bool
+}
+
+// number of items in a length determinant fragment (X.691 11.9.3.8)
+const fragmentSize = 16384
+
+// lengths up to 64K are encoded as constrained whole numbers (X.691 11.9.3.3)
+const maxConstrainedLength = 65536
+
+// contains returns true if value satisfies the root of the constraint
+func (c IntegerConstraint) contains(value int64) bool {
+	return (!c.HasLb || value >= c.Lb) && (!c.HasUb || value <= c.Ub)
+}
+
+// contains returns true if length satisfies the root of the constraint
+func (s Size) contains(length int) bool {
+	return length >= s.Lb && (!s.HasUb || length <= s.Ub)
+}
+
+// isFixed returns true if the root of the constraint is a single size
+func (s Size) isFixed() bool {
+	return s.HasUb && s.Lb == s.Ub
+}
+
+// rangeBits returns the number of bits of the bit-field encoding a constrained whole number in lb..ub (X.691 11.5.7.1)
+func rangeBits(lb int64, ub int64) int {
+	return bits.Len64(uint64(ub - lb))
+}
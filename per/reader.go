@@ -0,0 +1,541 @@
+package per
+
+import (
+	"errors"
+	"io"
+
+	"github.com/yafred/asn1-go/internal/octets"
+	"github.com/yafred/asn1-go/types"
+)
+
+// Reader helps decode ASN.1 values encoded in unaligned or aligned PER
+type Reader struct {
+	// stream to read from
+	in io.Reader
+
+	// byte being read
+	currentByte byte
+
+	// number of bits read
+	bitOffset int64
+
+	// ALIGNED variant
+	aligned bool
+
+	// CANONICAL-PER, non-canonical encodings are rejected
+	canonical bool
+
+	// writers recording the encodings of the SET OF components being read
+	recorders []*Writer
+}
+
+// NewReader creates a reader for the unaligned variant
+func NewReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.in = in
+	return r
+}
+
+// NewAlignedReader creates a reader for the aligned variant
+func NewAlignedReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.in = in
+	r.aligned = true
+	return r
+}
+
+// NewCanonicalReader creates a reader for the unaligned variant of CANONICAL-PER
+func NewCanonicalReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.in = in
+	r.canonical = true
+	return r
+}
+
+// NewCanonicalAlignedReader creates a reader for the aligned variant of CANONICAL-PER
+func NewCanonicalAlignedReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.in = in
+	r.aligned = true
+	r.canonical = true
+	return r
+}
+
+// IsCanonical returns true if the reader rejects encodings which are not CANONICAL-PER
+func (r *Reader) IsCanonical() bool {
+	return r.canonical
+}
+
+// IsAligned returns true if the reader decodes the aligned variant
+func (r *Reader) IsAligned() bool {
+	return r.aligned
+}
+
+// GetBitOffset returns the number of bits read
+func (r *Reader) GetBitOffset() int64 {
+	return r.bitOffset
+}
+
+// ReadBit reads a single bit, raises an error if end of stream is reached
+func (r *Reader) ReadBit() (bool, error) {
+	if r.bitOffset%8 == 0 {
+		buffer := make([]byte, 1)
+		_, err := io.ReadFull(r.in, buffer)
+		if err != nil {
+			return false, err
+		}
+		r.currentByte = buffer[0]
+	}
+	bit := (r.currentByte>>uint(7-r.bitOffset%8))&1 == 1
+	r.bitOffset++
+	for _, recorder := range r.recorders {
+		recorder.WriteBit(bit)
+	}
+	return bit, nil
+}
+
+// ReadBits reads nBits bits (at most 64), most significant first, raises an error if end of stream is reached
+func (r *Reader) ReadBits(nBits int) (uint64, error) {
+	if nBits > 64 {
+		return 0, errors.New("cannot read more than 64 bits at once")
+	}
+	var value uint64
+	for i := 0; i < nBits; i++ {
+		bit, err := r.ReadBit()
+		if err != nil {
+			return 0, err
+		}
+		value = value << 1
+		if bit {
+			value |= 1
+		}
+	}
+	return value, nil
+}
+
+// align skips the padding bits up to the next octet boundary, if the variant is aligned
+// raises an error if a padding bit is not zero in CANONICAL-PER
+func (r *Reader) align() error {
+	if !r.aligned {
+		return nil
+	}
+	if !r.canonical {
+		r.bitOffset = (r.bitOffset + 7) / 8 * 8
+		return nil
+	}
+
+	// recorded encodings are padded relative to their own start
+	recorders := r.recorders
+	r.recorders = nil
+	defer func() { r.recorders = recorders }()
+	for _, recorder := range recorders {
+		recorder.align()
+	}
+
+	for r.bitOffset%8 != 0 {
+		bit, err := r.ReadBit()
+		if err != nil {
+			return err
+		}
+		if bit {
+			return errors.New("non-zero padding bit")
+		}
+	}
+	return nil
+}
+
+// readBytes reads nBytes bytes, raises an error if end of stream is reached
+func (r *Reader) readBytes(nBytes int) ([]byte, error) {
+	buffer := make([]byte, nBytes)
+	if r.bitOffset%8 == 0 {
+		n, err := io.ReadFull(r.in, buffer)
+		r.bitOffset += 8 * int64(n)
+		if n > 0 {
+			r.currentByte = buffer[n-1]
+		}
+		for _, recorder := range r.recorders {
+			recorder.writeBytes(buffer[:n])
+		}
+		return buffer, err
+	}
+	for i := range buffer {
+		aByte, err := r.ReadBits(8)
+		if err != nil {
+			return nil, err
+		}
+		buffer[i] = byte(aByte)
+	}
+	return buffer, nil
+}
+
+// ReadBoolean decodes a BOOLEAN (X.691 12)
+func (r *Reader) ReadBoolean() (bool, error) {
+	return r.ReadBit()
+}
+
+// ReadExtensionBit decodes the bit telling if a value is outside the extension root (or if extension additions are present)
+func (r *Reader) ReadExtensionBit() (bool, error) {
+	return r.ReadBit()
+}
+
+// ReadConstrainedWholeNumber decodes a whole number in lb..ub (X.691 11.5), raises an error if value is out of range
+func (r *Reader) ReadConstrainedWholeNumber(lb int64, ub int64) (int64, error) {
+	maxOffset := uint64(ub - lb)
+	nBits := rangeBits(lb, ub)
+	isOctetAligned := r.aligned && maxOffset >= 255
+	switch {
+	case !isOctetAligned:
+	case maxOffset == 255:
+		nBits = 8
+	case maxOffset < maxConstrainedLength:
+		nBits = 16
+	default:
+		nBytes, err := r.ReadConstrainedWholeNumber(1, int64(octets.UnsignedBytes(maxOffset)))
+		if err != nil {
+			return 0, err
+		}
+		nBits = 8 * int(nBytes)
+	}
+	if isOctetAligned {
+		err := r.align()
+		if err != nil {
+			return 0, err
+		}
+	}
+	offset, err := r.ReadBits(nBits)
+	if err != nil {
+		return 0, err
+	}
+	if offset > maxOffset {
+		return 0, errors.New("constrained whole number out of range")
+	}
+	if r.canonical && r.aligned && maxOffset >= maxConstrainedLength && nBits != 8*octets.UnsignedBytes(offset) {
+		return 0, errors.New("non-minimal constrained whole number")
+	}
+	return lb + int64(offset), nil
+}
+
+// ReadNormallySmallNonNegativeWholeNumber decodes a whole number expected to be small (X.691 11.6)
+func (r *Reader) ReadNormallySmallNonNegativeWholeNumber() (uint64, error) {
+	isLarge, err := r.ReadBit()
+	if err != nil {
+		return 0, err
+	}
+	if !isLarge {
+		return r.ReadBits(6)
+	}
+	value, err := r.readNonNegativeBinaryInteger()
+	if err == nil && r.canonical && value <= 63 {
+		return 0, errors.New("non-minimal normally small number")
+	}
+	return value, err
+}
+
+// ReadSemiConstrainedWholeNumber decodes a whole number greater than or equal to lb (X.691 11.7)
+func (r *Reader) ReadSemiConstrainedWholeNumber(lb int64) (int64, error) {
+	offset, err := r.readNonNegativeBinaryInteger()
+	if err != nil {
+		return 0, err
+	}
+	value := lb + int64(offset)
+	if value < lb {
+		return 0, errors.New("semi-constrained whole number overflows int64")
+	}
+	return value, nil
+}
+
+// readNonNegativeBinaryInteger decodes a length determinant and the octets of a non-negative binary integer
+func (r *Reader) readNonNegativeBinaryInteger() (uint64, error) {
+	nBytes, err := r.readIntegerLength()
+	if err != nil {
+		return 0, err
+	}
+	value, err := r.ReadBits(8 * nBytes)
+	if err == nil && r.canonical && nBytes != octets.UnsignedBytes(value) {
+		return 0, errors.New("non-minimal integer encoding")
+	}
+	return value, err
+}
+
+// ReadUnconstrainedWholeNumber decodes a whole number without bounds (X.691 11.8)
+func (r *Reader) ReadUnconstrainedWholeNumber() (int64, error) {
+	nBytes, err := r.readIntegerLength()
+	if err != nil {
+		return 0, err
+	}
+	value, err := r.ReadBits(8 * nBytes)
+	if err != nil {
+		return 0, err
+	}
+	shift := uint(64 - 8*nBytes)
+	result := int64(value<<shift) >> shift // sign extension
+	if r.canonical && nBytes != octets.SignedBytes(result) {
+		return 0, errors.New("non-minimal integer encoding")
+	}
+	return result, nil
+}
+
+// readIntegerLength decodes the number of octets of an integer
+func (r *Reader) readIntegerLength() (int, error) {
+	nBytes, isFragment, err := r.readUnconstrainedLength()
+	if err != nil {
+		return 0, err
+	}
+	if isFragment || nBytes > 8 {
+		return 0, errors.New("integers over 8 bytes not supported")
+	}
+	if nBytes == 0 {
+		return 0, errors.New("zero length integer")
+	}
+	return nBytes, nil
+}
+
+// ReadInteger decodes an INTEGER according to its PER-visible constraint (X.691 13)
+func (r *Reader) ReadInteger(constraint IntegerConstraint) (int64, error) {
+	if constraint.Extensible {
+		isExtension, err := r.ReadExtensionBit()
+		if err != nil {
+			return 0, err
+		}
+		if isExtension {
+			value, err := r.ReadUnconstrainedWholeNumber()
+			if err == nil && r.canonical && constraint.contains(value) {
+				return 0, errors.New("INTEGER value of the root encoded as an extension")
+			}
+			return value, err
+		}
+	}
+
+	switch {
+	case constraint.HasLb && constraint.HasUb:
+		return r.ReadConstrainedWholeNumber(constraint.Lb, constraint.Ub)
+	case constraint.HasLb:
+		return r.ReadSemiConstrainedWholeNumber(constraint.Lb)
+	}
+	return r.ReadUnconstrainedWholeNumber()
+}
+
+// ReadEnumerated decodes the index of an ENUMERATED value (X.691 14)
+// with an extensible ENUMERATED, index >= rootCount designates the extension value (index - rootCount) in order of definition
+func (r *Reader) ReadEnumerated(rootCount int, extensible bool) (int, error) {
+	return r.readIndex(rootCount, extensible)
+}
+
+// ReadChoiceIndex decodes the index of the chosen alternative of a CHOICE (X.691 23)
+// with an extensible CHOICE, index >= rootCount designates the extension alternative (index - rootCount), whose value must then be read as an open type
+func (r *Reader) ReadChoiceIndex(rootCount int, extensible bool) (int, error) {
+	return r.readIndex(rootCount, extensible)
+}
+
+// readIndex decodes an index in the root (constrained whole number) or in the extensions (normally small number)
+func (r *Reader) readIndex(rootCount int, extensible bool) (int, error) {
+	if extensible {
+		isExtension, err := r.ReadExtensionBit()
+		if err != nil {
+			return 0, err
+		}
+		if isExtension {
+			index, err := r.ReadNormallySmallNonNegativeWholeNumber()
+			if err != nil {
+				return 0, err
+			}
+			if index > uint64(maxConstrainedLength) {
+				return 0, errors.New("extension index too large")
+			}
+			return rootCount + int(index), nil
+		}
+	}
+	index, err := r.ReadConstrainedWholeNumber(0, int64(rootCount)-1)
+	return int(index), err
+}
+
+// ReadExtensionAdditionsBitmap decodes the presence bitmap of the extension additions of a SEQUENCE or SET (X.691 19.8)
+func (r *Reader) ReadExtensionAdditionsBitmap() ([]bool, error) {
+	length, err := r.ReadNormallySmallNonNegativeWholeNumber()
+	if err != nil {
+		return nil, err
+	}
+	if length >= uint64(maxConstrainedLength) {
+		return nil, errors.New("extension additions bitmap too large")
+	}
+	present := make([]bool, length+1)
+	for i := range present {
+		present[i], err = r.ReadBit()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return present, nil
+}
+
+// ReadOpenType decodes an open type and returns the complete encoding of its value (X.691 11.2)
+func (r *Reader) ReadOpenType() ([]byte, error) {
+	result := []byte{}
+	err := r.readFragmented(func(nItems int) error {
+		buffer, err := r.readBytes(nItems)
+		result = append(result, buffer...)
+		return err
+	})
+	return result, err
+}
+
+// ReadOctetString decodes an OCTET STRING according to its SIZE constraint (X.691 17)
+func (r *Reader) ReadOctetString(size Size) ([]byte, error) {
+	result := []byte{}
+	err := r.readString(size, 8, func(nItems int) error {
+		buffer, err := r.readBytes(nItems)
+		result = append(result, buffer...)
+		return err
+	})
+	return result, err
+}
+
+// ReadBitString decodes a BIT STRING according to its SIZE constraint (X.691 16)
+func (r *Reader) ReadBitString(size Size) (types.BitString, error) {
+	result := types.BitString{Bytes: []byte{}}
+	err := r.readString(size, 1, func(nItems int) error {
+		for i := 0; i < nItems; i++ {
+			bit, err := r.ReadBit()
+			if err != nil {
+				return err
+			}
+			if result.Length%8 == 0 {
+				result.Bytes = append(result.Bytes, 0)
+			}
+			if bit {
+				result.Bytes[len(result.Bytes)-1] |= 0x80 >> uint(result.Length%8)
+			}
+			result.Length++
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ReadKnownMultiplierCharacterString decodes an IA5String, PrintableString, VisibleString, NumericString, BMPString or UniversalString
+// according to its effective permitted alphabet and its SIZE constraint (X.691 30.5)
+func (r *Reader) ReadKnownMultiplierCharacterString(alphabet Alphabet, size Size) (string, error) {
+	nBits := alphabet.charBits(r.aligned)
+	indexed := alphabet.isIndexed(nBits)
+	result := []rune{}
+	err := r.readString(size, nBits, func(nItems int) error {
+		for i := 0; i < nItems; i++ {
+			code, err := r.ReadBits(nBits)
+			if err != nil {
+				return err
+			}
+			c, err := alphabet.decode(code, indexed)
+			if err != nil {
+				return err
+			}
+			result = append(result, c)
+		}
+		return nil
+	})
+	return string(result), err
+}
+
+// readString decodes the length and the items of a string of itemBits-bit items, fixed sizes are read without length determinant
+func (r *Reader) readString(size Size, itemBits int, readItems func(nItems int) error) error {
+	if size.Extensible {
+		isExtension, err := r.ReadExtensionBit()
+		if err != nil {
+			return err
+		}
+		if isExtension {
+			nItems := 0
+			err := r.readFragmented(func(n int) error {
+				nItems += n
+				return readItems(n)
+			})
+			if err == nil && r.canonical && size.contains(nItems) {
+				return errors.New("size of the root encoded as an extension")
+			}
+			return err
+		}
+	}
+
+	switch {
+	case size.isFixed() && size.Ub < maxConstrainedLength:
+		if size.Ub*itemBits > 16 {
+			err := r.align()
+			if err != nil {
+				return err
+			}
+		}
+		return readItems(size.Lb)
+	case size.HasUb && size.Ub < maxConstrainedLength:
+		length, err := r.ReadConstrainedWholeNumber(int64(size.Lb), int64(size.Ub))
+		if err != nil {
+			return err
+		}
+		if length > 0 {
+			err = r.align()
+			if err != nil {
+				return err
+			}
+		}
+		return readItems(int(length))
+	}
+
+	nItems := 0
+	err := r.readFragmented(func(n int) error {
+		nItems += n
+		return readItems(n)
+	})
+	if err == nil && !size.contains(nItems) {
+		return errors.New("size out of range")
+	}
+	return err
+}
+
+// readUnconstrainedLength decodes a length determinant, returns true if it is the length of a fragment (X.691 11.9.3.6 to 11.9.3.8)
+func (r *Reader) readUnconstrainedLength() (int, bool, error) {
+	err := r.align()
+	if err != nil {
+		return 0, false, err
+	}
+	first, err := r.ReadBits(8)
+	if err != nil {
+		return 0, false, err
+	}
+	switch {
+	case first&0x80 == 0:
+		return int(first), false, nil
+	case first&0xC0 == 0x80:
+		second, err := r.ReadBits(8)
+		if err != nil {
+			return 0, false, err
+		}
+		length := int(first&0x3F)<<8 | int(second)
+		if r.canonical && length < 128 {
+			return 0, false, errors.New("non-minimal length determinant")
+		}
+		return length, false, nil
+	}
+	nFragments := int(first & 0x3F)
+	if nFragments < 1 || nFragments > 4 {
+		return 0, false, errors.New("invalid length determinant")
+	}
+	return nFragments * fragmentSize, true, nil
+}
+
+// readFragmented decodes unconstrained length determinants and the items, in fragments if needed (X.691 11.9.3.8)
+// in CANONICAL-PER, only the last fragment may be shorter than 64K items
+func (r *Reader) readFragmented(readItems func(nItems int) error) error {
+	mustBeLast := false
+	for {
+		length, isFragment, err := r.readUnconstrainedLength()
+		if err != nil {
+			return err
+		}
+		if isFragment && mustBeLast {
+			return errors.New("non-canonical fragmentation")
+		}
+		err = readItems(length)
+		if err != nil || !isFragment {
+			return err
+		}
+		mustBeLast = r.canonical && length < 4*fragmentSize
+	}
+}
@@ -0,0 +1,318 @@
+package per
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestReadBits(t *testing.T) {
+	buffer := [...]byte{0xdf, 0xf8}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	bit, err := reader.ReadBit()
+	if err != nil || bit != true {
+		t.Fatal("Wrong")
+	}
+	value, err := reader.ReadBits(3)
+	if err != nil || value != 5 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadBits(9)
+	if err != nil || value != 0x1ff {
+		t.Fatal("Wrong")
+	}
+	if reader.GetBitOffset() != 13 {
+		t.Fatal("Should be 13")
+	}
+	_, err = reader.ReadBits(8)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadConstrainedWholeNumber(t *testing.T) {
+	buffer := [...]byte{0xa4}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadConstrainedWholeNumber(0, 7)
+	if err != nil || value != 5 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadConstrainedWholeNumber(3, 3)
+	if err != nil || value != 3 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadConstrainedWholeNumber(-2, 2)
+	if err != nil || value != -1 {
+		t.Fatal("Wrong")
+	}
+
+	// 111 is out of range 0..4
+	buffer = [...]byte{0xe0}
+	reader = NewReader(bytes.NewReader(buffer[0:]))
+	_, err = reader.ReadConstrainedWholeNumber(0, 4)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadNormallySmallNonNegativeWholeNumber(t *testing.T) {
+	buffer := [...]byte{0x0b, 0x01, 0x40}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadNormallySmallNonNegativeWholeNumber()
+	if err != nil || value != 5 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadNormallySmallNonNegativeWholeNumber()
+	if err != nil || value != 64 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadInteger(t *testing.T) {
+	tests := []struct {
+		buffer     []byte
+		constraint IntegerConstraint
+		expected   int64
+	}{
+		{[]byte{0x02, 0x01, 0x00}, IntegerConstraint{}, 256},
+		{[]byte{0x02, 0xff, 0x7f}, IntegerConstraint{}, -129},
+		{[]byte{0x01, 0x80}, IntegerConstraint{Lb: 0, HasLb: true}, 128},
+		{[]byte{0x30}, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true}, 3},
+		{[]byte{0x80, 0x84, 0x00}, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true}, 8},
+	}
+
+	for _, test := range tests {
+		reader := NewReader(bytes.NewReader(test.buffer))
+		value, err := reader.ReadInteger(test.constraint)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if value != test.expected {
+			t.Fatal("Wrong:", value)
+		}
+	}
+
+	// length 9 is not supported
+	buffer := [...]byte{0x09, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+	_, err := reader.ReadInteger(IntegerConstraint{})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadEnumerated(t *testing.T) {
+	buffer := [...]byte{0x8c, 0x08}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadEnumerated(3, false)
+	if err != nil || value != 2 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadEnumerated(3, true)
+	if err != nil || value != 1 {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadEnumerated(3, true)
+	if err != nil || value != 4 {
+		t.Fatal("Wrong")
+	}
+
+	// 11 is out of range for 3 root values
+	buffer = [...]byte{0xc0, 0x00}
+	reader = NewReader(bytes.NewReader(buffer[0:]))
+	_, err = reader.ReadEnumerated(3, false)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadOctetString(t *testing.T) {
+	buffer := [...]byte{0x81, 0xb0, 0xb1, 0x31, 0x80}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	reader.ReadBit()
+	value, err := reader.ReadOctetString(Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(value, []byte("abc")) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadOctetStringConstrained(t *testing.T) {
+	buffer := [...]byte{0x01, 0x02, 0x00, 0xe0, 0x40, 0x80, 0xa0}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadOctetString(Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil || false == bytes.Equal(value, []byte{0x01, 0x02}) {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadOctetString(Size{Lb: 1, Ub: 4, HasUb: true})
+	if err != nil || false == bytes.Equal(value, []byte{0x03}) {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadOctetString(Size{Lb: 1, Ub: 1, HasUb: true, Extensible: true})
+	if err != nil || false == bytes.Equal(value, []byte{0x04, 0x05}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadOctetStringFragmented(t *testing.T) {
+	writer := NewWriter()
+	expected := make([]byte, 16384*5+200)
+	for i := range expected {
+		expected[i] = byte(i)
+	}
+	writer.WriteOctetString(expected, Size{})
+
+	reader := NewReader(bytes.NewReader(writer.GetDataBuffer()))
+	value, err := reader.ReadOctetString(Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if false == bytes.Equal(value, expected) {
+		t.Fatal("Wrong")
+	}
+
+	writer = NewWriter()
+	writer.WriteOctetString(expected[:16384], Size{})
+	reader = NewReader(bytes.NewReader(writer.GetDataBuffer()))
+	value, err = reader.ReadOctetString(Size{})
+	if err != nil || false == bytes.Equal(value, expected[:16384]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadBitString(t *testing.T) {
+	buffer := [...]byte{0xa0, 0x4a}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	expected := types.BitString{Bytes: []byte{0xa0}, Length: 4}
+
+	value, err := reader.ReadBitString(Size{Lb: 4, Ub: 4, HasUb: true})
+	if err != nil || value.Length != 4 || false == bytes.Equal(value.Bytes, expected.Bytes) {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadBitString(Size{})
+	if err != nil || value.Length != 4 || false == bytes.Equal(value.Bytes, expected.Bytes) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadExtensionAdditions(t *testing.T) {
+	buffer := [...]byte{0x81, 0x40, 0x60, 0x00}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	extended, err := reader.ReadExtensionBit()
+	if err != nil || extended != true {
+		t.Fatal("Wrong")
+	}
+	bitmap, err := reader.ReadExtensionAdditionsBitmap()
+	if err != nil || len(bitmap) != 2 || bitmap[0] != false || bitmap[1] != true {
+		t.Fatal("Wrong")
+	}
+	openType, err := reader.ReadOpenType()
+	if err != nil || false == bytes.Equal(openType, []byte{0x80}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadAlignedConstrainedWholeNumber(t *testing.T) {
+	tests := []struct {
+		buffer   []byte
+		lb       int64
+		ub       int64
+		expected int64
+	}{
+		{[]byte{0xd0}, 0, 7, 5},
+		{[]byte{0x80, 0x05}, 0, 255, 5},
+		{[]byte{0x80, 0x12, 0x34}, 0, 65535, 0x1234},
+		{[]byte{0x80, 0x00}, 0, 4294967295, 0},
+		{[]byte{0xa0, 0x01, 0x00}, 0, 4294967295, 256},
+	}
+
+	for _, test := range tests {
+		reader := NewAlignedReader(bytes.NewReader(test.buffer))
+		reader.ReadBit()
+		value, err := reader.ReadConstrainedWholeNumber(test.lb, test.ub)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if value != test.expected {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestReadAlignedOctetString(t *testing.T) {
+	buffer := [...]byte{0x80, 0x03, 0x61, 0x62, 0x63, 0x80, 0x81, 0x40, 0x01, 0x02, 0x03, 0x90, 0x01}
+	reader := NewAlignedReader(bytes.NewReader(buffer[0:]))
+
+	reader.ReadBit()
+	value, err := reader.ReadOctetString(Size{})
+	if err != nil || false == bytes.Equal(value, []byte("abc")) {
+		t.Fatal("Wrong")
+	}
+	reader.ReadBit()
+	value, err = reader.ReadOctetString(Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil || false == bytes.Equal(value, []byte{0x01, 0x02}) {
+		t.Fatal("Wrong")
+	}
+	reader.ReadBit()
+	value, err = reader.ReadOctetString(Size{Lb: 3, Ub: 3, HasUb: true})
+	if err != nil || false == bytes.Equal(value, []byte{0x01, 0x02, 0x03}) {
+		t.Fatal("Wrong")
+	}
+	reader.ReadBit()
+	value, err = reader.ReadOctetString(Size{Lb: 0, Ub: 7, HasUb: true})
+	if err != nil || false == bytes.Equal(value, []byte{0x01}) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadKnownMultiplierCharacterString(t *testing.T) {
+	buffer := [...]byte{0x02, 0xc3, 0x88, 0x0c, 0x8d, 0x00}
+	reader := NewReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadKnownMultiplierCharacterString(IA5StringAlphabet, Size{})
+	if err != nil || value != "ab" {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadKnownMultiplierCharacterString(NumericStringAlphabet, Size{})
+	if err != nil || value != "123" {
+		t.Fatal("Wrong")
+	}
+
+	// index 15 is not in NumericString alphabet
+	buffer = [...]byte{0x01, 0xf0, 0x00, 0x00, 0x00, 0x00}
+	reader = NewReader(bytes.NewReader(buffer[0:]))
+	_, err = reader.ReadKnownMultiplierCharacterString(NumericStringAlphabet, Size{})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadAlignedKnownMultiplierCharacterString(t *testing.T) {
+	buffer := [...]byte{0x02, 0x61, 0x62, 0xb0, 0xb1, 0x20}
+	reader := NewAlignedReader(bytes.NewReader(buffer[0:]))
+
+	value, err := reader.ReadKnownMultiplierCharacterString(IA5StringAlphabet, Size{})
+	if err != nil || value != "ab" {
+		t.Fatal("Wrong")
+	}
+	reader.ReadBit()
+	value, err = reader.ReadKnownMultiplierCharacterString(IA5StringAlphabet, Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil || value != "ab" {
+		t.Fatal("Wrong")
+	}
+	value, err = reader.ReadKnownMultiplierCharacterString(NewAlphabet("BA"), Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil || value != "AB" {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,349 @@
+package per
+
+import (
+	"errors"
+
+	"github.com/yafred/asn1-go/internal/octets"
+	"github.com/yafred/asn1-go/types"
+)
+
+// Writer helps encode ASN.1 values in unaligned or aligned PER, bits are written forward
+type Writer struct {
+	// encoded data, last byte may be partially written
+	dataBuffer []byte
+
+	// number of bits written
+	bitSize int
+
+	// ALIGNED variant
+	aligned bool
+
+	// CANONICAL-PER
+	canonical bool
+}
+
+// NewWriter creates a writer for the unaligned variant
+func NewWriter() *Writer {
+	w := new(Writer)
+	return w
+}
+
+// NewAlignedWriter creates a writer for the aligned variant
+func NewAlignedWriter() *Writer {
+	w := new(Writer)
+	w.aligned = true
+	return w
+}
+
+// NewCanonicalWriter creates a writer for the unaligned variant of CANONICAL-PER
+func NewCanonicalWriter() *Writer {
+	w := new(Writer)
+	w.canonical = true
+	return w
+}
+
+// NewCanonicalAlignedWriter creates a writer for the aligned variant of CANONICAL-PER
+func NewCanonicalAlignedWriter() *Writer {
+	w := new(Writer)
+	w.aligned = true
+	w.canonical = true
+	return w
+}
+
+// IsCanonical returns true if the writer encodes in CANONICAL-PER
+func (w *Writer) IsCanonical() bool {
+	return w.canonical
+}
+
+// IsAligned returns true if the writer encodes in the aligned variant
+func (w *Writer) IsAligned() bool {
+	return w.aligned
+}
+
+// GetDataBuffer returns the complete encoding: padded with zero bits to an octet boundary, a single zero octet if empty (X.691 11.1)
+func (w *Writer) GetDataBuffer() []byte {
+	if w.bitSize == 0 {
+		return []byte{0x00}
+	}
+	return w.dataBuffer
+}
+
+// GetBitSize returns the number of bits written
+func (w *Writer) GetBitSize() int {
+	return w.bitSize
+}
+
+// WriteBit writes a single bit
+func (w *Writer) WriteBit(value bool) {
+	if w.bitSize%8 == 0 {
+		w.dataBuffer = append(w.dataBuffer, 0)
+	}
+	if value {
+		w.dataBuffer[len(w.dataBuffer)-1] |= 0x80 >> uint(w.bitSize%8)
+	}
+	w.bitSize++
+}
+
+// WriteBits writes the nBits least significant bits of value, most significant first
+func (w *Writer) WriteBits(value uint64, nBits int) {
+	for i := nBits - 1; i >= 0; i-- {
+		w.WriteBit((value>>uint(i))&1 == 1)
+	}
+}
+
+// align writes zero bits up to the next octet boundary, if the variant is aligned
+func (w *Writer) align() {
+	if w.aligned {
+		w.bitSize = 8 * len(w.dataBuffer)
+	}
+}
+
+// writeBytes writes bytes
+func (w *Writer) writeBytes(value []byte) {
+	if w.bitSize%8 == 0 {
+		w.dataBuffer = append(w.dataBuffer, value...)
+		w.bitSize += 8 * len(value)
+		return
+	}
+	for _, aByte := range value {
+		w.WriteBits(uint64(aByte), 8)
+	}
+}
+
+// WriteBoolean encodes a BOOLEAN (X.691 12)
+func (w *Writer) WriteBoolean(value bool) {
+	w.WriteBit(value)
+}
+
+// WriteExtensionBit encodes the bit telling if a value is outside the extension root (or if extension additions are present)
+func (w *Writer) WriteExtensionBit(value bool) {
+	w.WriteBit(value)
+}
+
+// WriteConstrainedWholeNumber encodes a whole number in lb..ub (X.691 11.5), raises an error if value is out of range
+// in the aligned variant, ranges over 255 are octet-aligned and ranges over 64K are preceded by their number of octets
+func (w *Writer) WriteConstrainedWholeNumber(value int64, lb int64, ub int64) error {
+	if value < lb || value > ub {
+		return errors.New("constrained whole number out of range")
+	}
+	offset := uint64(value - lb)
+	maxOffset := uint64(ub - lb)
+	switch {
+	case !w.aligned || maxOffset < 255:
+		w.WriteBits(offset, rangeBits(lb, ub))
+	case maxOffset == 255:
+		w.align()
+		w.WriteBits(offset, 8)
+	case maxOffset < maxConstrainedLength:
+		w.align()
+		w.WriteBits(offset, 16)
+	default:
+		nBytes := octets.UnsignedBytes(offset)
+		w.WriteConstrainedWholeNumber(int64(nBytes), 1, int64(octets.UnsignedBytes(maxOffset)))
+		w.align()
+		w.WriteBits(offset, 8*nBytes)
+	}
+	return nil
+}
+
+// WriteNormallySmallNonNegativeWholeNumber encodes a whole number expected to be small (X.691 11.6)
+func (w *Writer) WriteNormallySmallNonNegativeWholeNumber(value uint64) {
+	if value <= 63 {
+		w.WriteBit(false)
+		w.WriteBits(value, 6)
+		return
+	}
+	w.WriteBit(true)
+	w.writeNonNegativeBinaryInteger(value)
+}
+
+// WriteSemiConstrainedWholeNumber encodes a whole number greater than or equal to lb (X.691 11.7), raises an error if value is out of range
+func (w *Writer) WriteSemiConstrainedWholeNumber(value int64, lb int64) error {
+	if value < lb {
+		return errors.New("semi-constrained whole number out of range")
+	}
+	w.writeNonNegativeBinaryInteger(uint64(value - lb))
+	return nil
+}
+
+// writeNonNegativeBinaryInteger encodes a length determinant and the minimum octets of a non-negative binary integer
+func (w *Writer) writeNonNegativeBinaryInteger(value uint64) {
+	nBytes := octets.UnsignedBytes(value)
+	w.writeUnconstrainedLength(nBytes)
+	w.WriteBits(value, 8*nBytes)
+}
+
+// WriteUnconstrainedWholeNumber encodes a whole number without bounds (X.691 11.8)
+func (w *Writer) WriteUnconstrainedWholeNumber(value int64) {
+	nBytes := octets.SignedBytes(value)
+	w.writeUnconstrainedLength(nBytes)
+	w.WriteBits(uint64(value), 8*nBytes)
+}
+
+// WriteInteger encodes an INTEGER according to its PER-visible constraint (X.691 13), raises an error if value does not satisfy the constraint
+func (w *Writer) WriteInteger(value int64, constraint IntegerConstraint) error {
+	if constraint.Extensible {
+		isExtension := !constraint.contains(value)
+		w.WriteExtensionBit(isExtension)
+		if isExtension {
+			w.WriteUnconstrainedWholeNumber(value)
+			return nil
+		}
+	}
+
+	switch {
+	case constraint.HasLb && constraint.HasUb:
+		return w.WriteConstrainedWholeNumber(value, constraint.Lb, constraint.Ub)
+	case constraint.HasLb:
+		return w.WriteSemiConstrainedWholeNumber(value, constraint.Lb)
+	case constraint.HasUb && value > constraint.Ub:
+		return errors.New("INTEGER value out of range")
+	}
+	w.WriteUnconstrainedWholeNumber(value)
+	return nil
+}
+
+// WriteEnumerated encodes the index of an ENUMERATED value (X.691 14)
+// with an extensible ENUMERATED, index >= rootCount designates the extension value (index - rootCount) in order of definition
+func (w *Writer) WriteEnumerated(index int, rootCount int, extensible bool) error {
+	return w.writeIndex(index, rootCount, extensible)
+}
+
+// WriteChoiceIndex encodes the index of the chosen alternative of a CHOICE (X.691 23)
+// with an extensible CHOICE, index >= rootCount designates the extension alternative (index - rootCount), whose value must then be written as an open type
+func (w *Writer) WriteChoiceIndex(index int, rootCount int, extensible bool) error {
+	return w.writeIndex(index, rootCount, extensible)
+}
+
+// writeIndex encodes an index in the root (constrained whole number) or in the extensions (normally small number)
+func (w *Writer) writeIndex(index int, rootCount int, extensible bool) error {
+	if index < 0 || !extensible && index >= rootCount {
+		return errors.New("index out of range")
+	}
+	if extensible {
+		isExtension := index >= rootCount
+		w.WriteExtensionBit(isExtension)
+		if isExtension {
+			w.WriteNormallySmallNonNegativeWholeNumber(uint64(index - rootCount))
+			return nil
+		}
+	}
+	return w.WriteConstrainedWholeNumber(int64(index), 0, int64(rootCount)-1)
+}
+
+// WriteExtensionAdditionsBitmap encodes the presence bitmap of the extension additions of a SEQUENCE or SET (X.691 19.8)
+// each present addition must then be written as an open type
+func (w *Writer) WriteExtensionAdditionsBitmap(present []bool) error {
+	if len(present) == 0 {
+		return errors.New("empty extension additions bitmap")
+	}
+	w.WriteNormallySmallNonNegativeWholeNumber(uint64(len(present) - 1))
+	for _, isPresent := range present {
+		w.WriteBit(isPresent)
+	}
+	return nil
+}
+
+// WriteOpenType encodes the complete encoding of a value as an open type (X.691 11.2)
+func (w *Writer) WriteOpenType(encoding []byte) {
+	w.writeFragmented(len(encoding), func(begin int, end int) {
+		w.writeBytes(encoding[begin:end])
+	})
+}
+
+// WriteOctetString encodes an OCTET STRING according to its SIZE constraint (X.691 17), raises an error if value does not satisfy the constraint
+func (w *Writer) WriteOctetString(value []byte, size Size) error {
+	return w.writeString(len(value), size, 8, func(begin int, end int) {
+		w.writeBytes(value[begin:end])
+	})
+}
+
+// WriteBitString encodes a BIT STRING according to its SIZE constraint (X.691 16), raises an error if value does not satisfy the constraint
+func (w *Writer) WriteBitString(value types.BitString, size Size) error {
+	length := value.ValidLength()
+	return w.writeString(length, size, 1, func(begin int, end int) {
+		for i := begin; i < end; i++ {
+			w.WriteBit(value.Get(i))
+		}
+	})
+}
+
+// WriteKnownMultiplierCharacterString encodes an IA5String, PrintableString, VisibleString, NumericString, BMPString or UniversalString
+// according to its effective permitted alphabet and its SIZE constraint (X.691 30.5), raises an error if value does not satisfy the constraints
+func (w *Writer) WriteKnownMultiplierCharacterString(value string, alphabet Alphabet, size Size) error {
+	nBits := alphabet.charBits(w.aligned)
+	indexed := alphabet.isIndexed(nBits)
+	chars := []rune(value)
+	codes := make([]uint64, len(chars))
+	for i, c := range chars {
+		code, err := alphabet.encode(c, indexed)
+		if err != nil {
+			return err
+		}
+		codes[i] = code
+	}
+	return w.writeString(len(codes), size, nBits, func(begin int, end int) {
+		for _, code := range codes[begin:end] {
+			w.WriteBits(code, nBits)
+		}
+	})
+}
+
+// writeString encodes the length and the items of a string of itemBits-bit items, fixed sizes are written without length determinant
+// in the aligned variant, the items are octet-aligned unless the size is fixed and they fit in 16 bits
+func (w *Writer) writeString(length int, size Size, itemBits int, writeItems func(begin int, end int)) error {
+	if size.Extensible {
+		isExtension := !size.contains(length)
+		w.WriteExtensionBit(isExtension)
+		if isExtension {
+			w.writeFragmented(length, writeItems)
+			return nil
+		}
+	} else if !size.contains(length) {
+		return errors.New("size out of range")
+	}
+
+	switch {
+	case size.isFixed() && size.Ub < maxConstrainedLength:
+		if size.Ub*itemBits > 16 {
+			w.align()
+		}
+		writeItems(0, length)
+	case size.HasUb && size.Ub < maxConstrainedLength:
+		w.WriteConstrainedWholeNumber(int64(length), int64(size.Lb), int64(size.Ub))
+		if length > 0 {
+			w.align()
+		}
+		writeItems(0, length)
+	default:
+		w.writeFragmented(length, writeItems)
+	}
+	return nil
+}
+
+// writeUnconstrainedLength encodes a length determinant below 16K (X.691 11.9.3.6 and 11.9.3.7), octet-aligned in the aligned variant
+func (w *Writer) writeUnconstrainedLength(length int) {
+	w.align()
+	if length < 128 {
+		w.WriteBits(uint64(length), 8)
+		return
+	}
+	w.WriteBits(0x8000|uint64(length), 16)
+}
+
+// writeFragmented encodes an unconstrained length determinant and the items, in fragments of 16K to 64K items if needed (X.691 11.9.3.8)
+func (w *Writer) writeFragmented(length int, writeItems func(begin int, end int)) {
+	begin := 0
+	for length-begin >= fragmentSize {
+		nFragments := (length - begin) / fragmentSize
+		if nFragments > 4 {
+			nFragments = 4
+		}
+		w.align()
+		w.WriteBits(0xC0|uint64(nFragments), 8)
+		writeItems(begin, begin+nFragments*fragmentSize)
+		begin += nFragments * fragmentSize
+	}
+	w.writeUnconstrainedLength(length - begin)
+	writeItems(begin, length)
+}
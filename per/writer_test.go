@@ -0,0 +1,353 @@
+package per
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func TestWriteBits(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteBit(true)
+	writer.WriteBits(0x05, 3)
+	writer.WriteBits(0x1ff, 9)
+
+	if writer.GetBitSize() != 13 {
+		t.Fatal("Should be 13")
+	}
+
+	expectedBuffer := [...]byte{0xdf, 0xf8}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	writer := NewWriter()
+
+	expectedBuffer := [...]byte{0x00}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteConstrainedWholeNumber(t *testing.T) {
+	writer := NewWriter()
+
+	err := writer.WriteConstrainedWholeNumber(5, 0, 7)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteConstrainedWholeNumber(3, 3, 3)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteConstrainedWholeNumber(-1, -2, 2)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if writer.GetBitSize() != 6 {
+		t.Fatal("Should be 6")
+	}
+	expectedBuffer := [...]byte{0xa4}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err = writer.WriteConstrainedWholeNumber(8, 0, 7)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteNormallySmallNonNegativeWholeNumber(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteNormallySmallNonNegativeWholeNumber(5)
+	writer.WriteNormallySmallNonNegativeWholeNumber(64)
+
+	// 0 000101 1 00000001 01000000
+	expectedBuffer := [...]byte{0x0b, 0x01, 0x40}
+	if writer.GetBitSize() != 24 {
+		t.Fatal("Should be 24")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteInteger(t *testing.T) {
+	tests := []struct {
+		value      int64
+		constraint IntegerConstraint
+		expected   []byte
+		bitSize    int
+	}{
+		{256, IntegerConstraint{}, []byte{0x02, 0x01, 0x00}, 24},
+		{-129, IntegerConstraint{}, []byte{0x02, 0xff, 0x7f}, 24},
+		{128, IntegerConstraint{Lb: 0, HasLb: true}, []byte{0x01, 0x80}, 16},
+		{3, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true}, []byte{0x30}, 4},
+		{8, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true, Extensible: true}, []byte{0x80, 0x84, 0x00}, 17},
+	}
+
+	for _, test := range tests {
+		writer := NewWriter()
+		err := writer.WriteInteger(test.value, test.constraint)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if writer.GetBitSize() != test.bitSize {
+			t.Fatal("Wrong:", test.value, writer.GetBitSize())
+		}
+		if false == bytes.Equal(writer.GetDataBuffer(), test.expected) {
+			t.Fatal("Wrong:", test.value, writer.GetDataBuffer())
+		}
+	}
+
+	writer := NewWriter()
+	err := writer.WriteInteger(8, IntegerConstraint{Lb: 0, Ub: 7, HasLb: true, HasUb: true})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteEnumerated(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteEnumerated(2, 3, false)
+	writer.WriteEnumerated(1, 3, true)
+	writer.WriteEnumerated(4, 3, true)
+
+	// 10 0 01 1 0 000001
+	expectedBuffer := [...]byte{0x8c, 0x08}
+	if writer.GetBitSize() != 13 {
+		t.Fatal("Should be 13")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err := writer.WriteEnumerated(3, 3, false)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteOctetString(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteBit(true)
+	err := writer.WriteOctetString([]byte("abc"), Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	// 1 00000011 01100001 01100010 01100011
+	expectedBuffer := [...]byte{0x81, 0xb0, 0xb1, 0x31, 0x80}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteOctetStringConstrained(t *testing.T) {
+	writer := NewWriter()
+
+	err := writer.WriteOctetString([]byte{0x01, 0x02}, Size{Lb: 2, Ub: 2, HasUb: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteOctetString([]byte{0x03}, Size{Lb: 1, Ub: 4, HasUb: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteOctetString([]byte{0x04, 0x05}, Size{Lb: 1, Ub: 1, HasUb: true, Extensible: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	// 00000001 00000010 | 00 00000011 | 1 00000010 00000100 00000101
+	expectedBuffer := [...]byte{0x01, 0x02, 0x00, 0xe0, 0x40, 0x80, 0xa0}
+	if writer.GetBitSize() != 16+10+25 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err = writer.WriteOctetString([]byte{0x01}, Size{Lb: 2, Ub: 2, HasUb: true})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteOctetStringFragmented(t *testing.T) {
+	writer := NewWriter()
+
+	err := writer.WriteOctetString(make([]byte, 16384*5+200), Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	encoded := writer.GetDataBuffer()
+	if len(encoded) != 1+16384*4+1+16384+2+200 {
+		t.Fatal("Wrong")
+	}
+	if encoded[0] != 0xc4 || encoded[1+16384*4] != 0xc1 || encoded[2+16384*5] != 0x80 || encoded[3+16384*5] != 0xc8 {
+		t.Fatal("Wrong")
+	}
+
+	writer = NewWriter()
+	writer.WriteOctetString(make([]byte, 16384), Size{})
+	encoded = writer.GetDataBuffer()
+	if len(encoded) != 1+16384+1 || encoded[0] != 0xc1 || encoded[16385] != 0x00 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteBitString(t *testing.T) {
+	writer := NewWriter()
+
+	value := types.BitString{Bytes: []byte{0xa0}, Length: 4}
+
+	err := writer.WriteBitString(value, Size{Lb: 4, Ub: 4, HasUb: true})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	err = writer.WriteBitString(value, Size{})
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	// 1010 00000100 1010
+	expectedBuffer := [...]byte{0xa0, 0x4a}
+	if writer.GetBitSize() != 16 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteExtensionAdditions(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteExtensionBit(true)
+	writer.WriteExtensionAdditionsBitmap([]bool{false, true})
+
+	inner := NewWriter()
+	inner.WriteBoolean(true)
+	writer.WriteOpenType(inner.GetDataBuffer())
+
+	// 1 0 000001 01 00000001 10000000
+	expectedBuffer := [...]byte{0x81, 0x40, 0x60, 0x00}
+	if writer.GetBitSize() != 26 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err := writer.WriteExtensionAdditionsBitmap(nil)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteAlignedConstrainedWholeNumber(t *testing.T) {
+	tests := []struct {
+		value    int64
+		lb       int64
+		ub       int64
+		expected []byte
+	}{
+		{5, 0, 7, []byte{0xd0}},
+		{5, 0, 255, []byte{0x80, 0x05}},
+		{0x1234, 0, 65535, []byte{0x80, 0x12, 0x34}},
+		{0, 0, 4294967295, []byte{0x80, 0x00}},
+		{256, 0, 4294967295, []byte{0xa0, 0x01, 0x00}},
+	}
+
+	for _, test := range tests {
+		writer := NewAlignedWriter()
+		writer.WriteBit(true)
+		err := writer.WriteConstrainedWholeNumber(test.value, test.lb, test.ub)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if false == bytes.Equal(writer.GetDataBuffer(), test.expected) {
+			t.Fatal("Wrong:", test.value, writer.GetDataBuffer())
+		}
+	}
+}
+
+func TestWriteAlignedInteger(t *testing.T) {
+	writer := NewAlignedWriter()
+
+	writer.WriteBit(true)
+	writer.WriteInteger(256, IntegerConstraint{})
+	writer.WriteBit(true)
+	writer.WriteInteger(128, IntegerConstraint{Lb: 0, HasLb: true})
+
+	expectedBuffer := [...]byte{0x80, 0x02, 0x01, 0x00, 0x80, 0x01, 0x80}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteAlignedOctetString(t *testing.T) {
+	writer := NewAlignedWriter()
+
+	writer.WriteBit(true)
+	writer.WriteOctetString([]byte("abc"), Size{})
+	writer.WriteBit(true)
+	writer.WriteOctetString([]byte{0x01, 0x02}, Size{Lb: 2, Ub: 2, HasUb: true})
+	writer.WriteBit(true)
+	writer.WriteOctetString([]byte{0x01, 0x02, 0x03}, Size{Lb: 3, Ub: 3, HasUb: true})
+	writer.WriteBit(true)
+	writer.WriteOctetString([]byte{0x01}, Size{Lb: 0, Ub: 7, HasUb: true})
+
+	// 1 0000000 00000011 'abc' | 1 00000001 00000010 | 1 000000 01 02 03 | 1 001 0000 01
+	expectedBuffer := [...]byte{0x80, 0x03, 0x61, 0x62, 0x63, 0x80, 0x81, 0x40, 0x01, 0x02, 0x03, 0x90, 0x01}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteKnownMultiplierCharacterString(t *testing.T) {
+	writer := NewWriter()
+
+	writer.WriteKnownMultiplierCharacterString("ab", IA5StringAlphabet, Size{})
+	writer.WriteKnownMultiplierCharacterString("123", NumericStringAlphabet, Size{})
+
+	// 00000010 1100001 1100010 | 00000011 0010 0011 0100
+	expectedBuffer := [...]byte{0x02, 0xc3, 0x88, 0x0c, 0x8d, 0x00}
+	if writer.GetBitSize() != 8+14+8+12 {
+		t.Fatal("Wrong")
+	}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+
+	err := writer.WriteKnownMultiplierCharacterString("12a", NumericStringAlphabet, Size{})
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestWriteAlignedKnownMultiplierCharacterString(t *testing.T) {
+	writer := NewAlignedWriter()
+
+	writer.WriteKnownMultiplierCharacterString("ab", IA5StringAlphabet, Size{})
+	writer.WriteBit(true)
+	writer.WriteKnownMultiplierCharacterString("ab", IA5StringAlphabet, Size{Lb: 2, Ub: 2, HasUb: true})
+	writer.WriteKnownMultiplierCharacterString("AB", NewAlphabet("BA"), Size{Lb: 2, Ub: 2, HasUb: true})
+
+	// 00000010 'a' 'b' | 1 01100001 01100010 0 1
+	expectedBuffer := [...]byte{0x02, 0x61, 0x62, 0xb0, 0xb1, 0x20}
+	if false == bytes.Equal(writer.GetDataBuffer(), expectedBuffer[0:]) {
+		t.Fatal("Wrong")
+	}
+}
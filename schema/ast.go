@@ -0,0 +1,527 @@
+// Package schema parses ASN.1 modules (X.680) into an abstract syntax tree
+// all the nodes of the tree have the position of their first token, syntax errors are *Error values giving the position of the offending token
+// parameterization and information object classes (X.681, X.683) are not supported
+package schema
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Position is a location in the text of a module
+type Position struct {
+	Line   int
+	Column int
+}
+
+// String returns the position as text, e.g. line 3, column 12
+func (p Position) String() string {
+	return "line " + strconv.Itoa(p.Line) + ", column " + strconv.Itoa(p.Column)
+}
+
+// Error is a syntax error located in the text of a module
+type Error struct {
+	Pos     Position
+	Message string
+}
+
+// Error returns the position followed by the message
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Message
+}
+
+// Node holds the position of a node of the tree
+type Node struct {
+	Pos Position
+}
+
+// Position returns the position of the first token of the node
+func (n *Node) Position() Position {
+	return n.Pos
+}
+
+// TagDefault is the tagging applied to the tags of a module which are neither EXPLICIT nor IMPLICIT
+type TagDefault int
+
+// tag defaults
+const (
+	ExplicitTags TagDefault = iota
+	ImplicitTags
+	AutomaticTags
+)
+
+// Module is a module definition
+type Module struct {
+	Node
+	Name string
+
+	// definitive identifier, nil if absent
+	Identifier *ObjectIdentifierValue
+
+	TagDefault           TagDefault
+	ExtensibilityImplied bool
+
+	// true if there is no EXPORTS clause or if it is EXPORTS ALL, Exports lists the exported symbols otherwise
+	ExportsAll bool
+	Exports    []*Symbol
+
+	Imports     []*Import
+	Assignments []Assignment
+}
+
+// Symbol is a reference exported or imported by a module
+type Symbol struct {
+	Node
+	Name string
+}
+
+// Import is a list of symbols imported from a module
+type Import struct {
+	Node
+	Symbols []*Symbol
+	Module  string
+
+	// *ObjectIdentifierValue or *ValueReference identifying the module, nil if absent
+	ModuleIdentifier Value
+}
+
+// Assignment is a *TypeAssignment, a *ValueAssignment or a *ValueSetAssignment
+type Assignment interface {
+	Position() Position
+	isAssignment()
+}
+
+// TypeAssignment is Name ::= Type
+type TypeAssignment struct {
+	Node
+	Name string
+	Type Type
+}
+
+// ValueAssignment is name Type ::= Value
+type ValueAssignment struct {
+	Node
+	Name  string
+	Type  Type
+	Value Value
+}
+
+// ValueSetAssignment is Name Type ::= { Set }
+type ValueSetAssignment struct {
+	Node
+	Name string
+	Type Type
+	Set  *Constraint
+}
+
+func (*TypeAssignment) isAssignment()     {}
+func (*ValueAssignment) isAssignment()    {}
+func (*ValueSetAssignment) isAssignment() {}
+
+// Type is a node describing a type
+type Type interface {
+	Position() Position
+	isType()
+}
+
+// BuiltinType is a built-in type without components, named numbers or named bits, e.g. BOOLEAN, OCTET STRING or UTF8String
+type BuiltinType struct {
+	Node
+	Name string
+}
+
+// IntegerType is INTEGER with its named numbers
+type IntegerType struct {
+	Node
+	NamedNumbers []*NamedNumber
+}
+
+// BitStringType is BIT STRING with its named bits
+type BitStringType struct {
+	Node
+	NamedBits []*NamedNumber
+}
+
+// NamedNumber is a named number, a named bit or an enumeration item
+type NamedNumber struct {
+	Node
+	Name string
+
+	// *IntegerValue or *ValueReference, nil for an enumeration item without number
+	Value Value
+}
+
+// EnumeratedType is ENUMERATED with its root items and, if it is extensible, its additional items
+type EnumeratedType struct {
+	Node
+	Items      []*NamedNumber
+	Extensible bool
+	Exception  Value
+	Additions  []*NamedNumber
+}
+
+// SequenceType is a SEQUENCE or a SET (Set is true)
+// components after the second extension marker are in TrailingComponents
+type SequenceType struct {
+	Node
+	Set                bool
+	Components         []*Component
+	Extensible         bool
+	Exception          Value
+	Additions          []*ExtensionAddition
+	TrailingComponents []*Component
+}
+
+// ChoiceType is a CHOICE with its root alternatives and, if it is extensible, its additional alternatives
+type ChoiceType struct {
+	Node
+	Alternatives []*Component
+	Extensible   bool
+	Exception    Value
+	Additions    []*ExtensionAddition
+}
+
+// Component is a component of a SEQUENCE or SET, or an alternative of a CHOICE
+// for COMPONENTS OF Type, ComponentsOf is true and Name is empty
+type Component struct {
+	Node
+	Name         string
+	Type         Type
+	Optional     bool
+	Default      Value
+	ComponentsOf bool
+}
+
+// ExtensionAddition is an additional component or a group of additional components in version brackets ([[ ]])
+type ExtensionAddition struct {
+	Node
+	Group bool
+
+	// version number of a group, nil if absent
+	Version    Value
+	Components []*Component
+}
+
+// SequenceOfType is a SEQUENCE OF or a SET OF (Set is true)
+type SequenceOfType struct {
+	Node
+	Set bool
+
+	// constraint written before OF, e.g. SEQUENCE SIZE (1..4) OF, nil if absent
+	Constraint *Constraint
+
+	// identifier of the elements, e.g. SEQUENCE OF item Type, empty if absent
+	ElementName string
+	Element     Type
+}
+
+// TaggingMode tells whether a tag is EXPLICIT, IMPLICIT or given by the tag default of the module
+type TaggingMode int
+
+// tagging modes
+const (
+	DefaultTagging TaggingMode = iota
+	ExplicitTagging
+	ImplicitTagging
+)
+
+// TaggedType is [Class Number] Mode Type
+type TaggedType struct {
+	Node
+	Class types.TagClass
+
+	// *IntegerValue or *ValueReference
+	Number Value
+	Mode   TaggingMode
+	Type   Type
+}
+
+// TypeReference is a reference to a type, Module is given for an external reference (Module.Type)
+type TypeReference struct {
+	Node
+	Module string
+	Name   string
+}
+
+// SelectionType is alternative < Type
+type SelectionType struct {
+	Node
+	Alternative string
+	Type        Type
+}
+
+// ConstrainedType is Type (Constraint)
+type ConstrainedType struct {
+	Node
+	Type       Type
+	Constraint *Constraint
+}
+
+func (*BuiltinType) isType()     {}
+func (*IntegerType) isType()     {}
+func (*BitStringType) isType()   {}
+func (*EnumeratedType) isType()  {}
+func (*SequenceType) isType()    {}
+func (*ChoiceType) isType()      {}
+func (*SequenceOfType) isType()  {}
+func (*TaggedType) isType()      {}
+func (*TypeReference) isType()   {}
+func (*SelectionType) isType()   {}
+func (*ConstrainedType) isType() {}
+
+// Value is a node describing a value
+// braces are parsed according to the type given in the assignment or in the SEQUENCE or SET type of the enclosing value, references to the types of the module being followed
+// otherwise they are an *ObjectIdentifierValue if they hold arcs, a *SequenceValue if they hold identifiers followed by values, a *SequenceOfValue otherwise
+type Value interface {
+	Position() Position
+	isValue()
+}
+
+// BooleanValue is TRUE or FALSE
+type BooleanValue struct {
+	Node
+	Value bool
+}
+
+// NullValue is NULL
+type NullValue struct {
+	Node
+}
+
+// IntegerValue is a number
+type IntegerValue struct {
+	Node
+	Value *big.Int
+}
+
+// RealValue is a realnumber or one of PLUS-INFINITY, MINUS-INFINITY and NOT-A-NUMBER
+type RealValue struct {
+	Node
+	Value float64
+}
+
+// StringValue is a cstring
+type StringValue struct {
+	Node
+	Value string
+}
+
+// BStringValue is a bstring, e.g. '0101'B
+type BStringValue struct {
+	Node
+	Bits string
+}
+
+// HStringValue is an hstring, e.g. 'CAFE'H
+type HStringValue struct {
+	Node
+	Digits string
+}
+
+// ValueReference is an identifier: a reference to a value or a named value (named number, enumeration item, ...), Module is given for an external reference (Module.value)
+type ValueReference struct {
+	Node
+	Module string
+	Name   string
+}
+
+// ObjectIdentifierValue is the value of an OBJECT IDENTIFIER or a RELATIVE-OID
+type ObjectIdentifierValue struct {
+	Node
+	Components []*OIDComponent
+}
+
+// OIDComponent is an arc: a name, a number or a name followed by a number in parentheses
+type OIDComponent struct {
+	Node
+
+	// name of the arc, empty if absent
+	Name string
+
+	// *IntegerValue or *ValueReference, nil if absent
+	Number Value
+}
+
+// SequenceValue is the value of a SEQUENCE or SET
+type SequenceValue struct {
+	Node
+	Components []*NamedValue
+}
+
+// NamedValue is a component of a SEQUENCE or SET value
+type NamedValue struct {
+	Node
+	Name  string
+	Value Value
+}
+
+// SequenceOfValue is the value of a SEQUENCE OF or SET OF, or a list of named bits
+type SequenceOfValue struct {
+	Node
+	Elements []Value
+}
+
+// ChoiceValue is alternative : Value
+type ChoiceValue struct {
+	Node
+	Alternative string
+	Value       Value
+}
+
+// MinValue is MIN, the lower bound of a value range
+type MinValue struct {
+	Node
+}
+
+// MaxValue is MAX, the upper bound of a value range
+type MaxValue struct {
+	Node
+}
+
+func (*BooleanValue) isValue()          {}
+func (*NullValue) isValue()             {}
+func (*IntegerValue) isValue()          {}
+func (*RealValue) isValue()             {}
+func (*StringValue) isValue()           {}
+func (*BStringValue) isValue()          {}
+func (*HStringValue) isValue()          {}
+func (*ValueReference) isValue()        {}
+func (*ObjectIdentifierValue) isValue() {}
+func (*SequenceValue) isValue()         {}
+func (*SequenceOfValue) isValue()       {}
+func (*ChoiceValue) isValue()           {}
+func (*MinValue) isValue()              {}
+func (*MaxValue) isValue()              {}
+
+// Constraint is a subtype constraint or a value set: its root element set and, if it is extensible, its additional element set
+type Constraint struct {
+	Node
+
+	// nil if there is no root element set, e.g. ( ..., 5 )
+	Root       ElementSet
+	Extensible bool
+
+	// nil if there is no additional element set
+	Additions ElementSet
+	Exception Value
+}
+
+// ElementSet is a node describing a set of values
+type ElementSet interface {
+	Position() Position
+	isElementSet()
+}
+
+// Union is Set | Set ... (or UNION)
+type Union struct {
+	Node
+	Elements []ElementSet
+}
+
+// Intersection is Set ^ Set ... (or INTERSECTION)
+type Intersection struct {
+	Node
+	Elements []ElementSet
+}
+
+// Exclusion is Set EXCEPT Excluded, or ALL EXCEPT Excluded (Set is nil)
+type Exclusion struct {
+	Node
+	Set      ElementSet
+	Excluded ElementSet
+}
+
+// SingleValue is a value
+type SingleValue struct {
+	Node
+	Value Value
+}
+
+// ValueRange is Lower..Upper, with < on the side of an excluded bound
+// bounds are *MinValue and *MaxValue for MIN and MAX
+type ValueRange struct {
+	Node
+	Lower         Value
+	LowerExcluded bool
+	Upper         Value
+	UpperExcluded bool
+}
+
+// ContainedSubtype is a type whose values are included, INCLUDES Type or Type
+type ContainedSubtype struct {
+	Node
+	Includes bool
+	Type     Type
+}
+
+// SizeConstraint is SIZE (Constraint)
+type SizeConstraint struct {
+	Node
+	Constraint *Constraint
+}
+
+// PermittedAlphabet is FROM (Constraint)
+type PermittedAlphabet struct {
+	Node
+	Constraint *Constraint
+}
+
+// PatternConstraint is PATTERN Value
+type PatternConstraint struct {
+	Node
+	Pattern Value
+}
+
+// ContentsConstraint is CONTAINING Type ENCODED BY Value, Type or EncodedBy being nil if absent
+type ContentsConstraint struct {
+	Node
+	Type      Type
+	EncodedBy Value
+}
+
+// InnerTypeConstraint is WITH COMPONENT (Constraint) or WITH COMPONENTS { ... }
+type InnerTypeConstraint struct {
+	Node
+
+	// constraint of WITH COMPONENT, nil for WITH COMPONENTS
+	Component *Constraint
+
+	// true if WITH COMPONENTS starts with ...
+	Partial    bool
+	Components []*NamedConstraint
+}
+
+// Presence is the presence constraint of a component in WITH COMPONENTS
+type Presence int
+
+// presence constraints
+const (
+	NoPresence Presence = iota
+	PresentPresence
+	AbsentPresence
+	OptionalPresence
+)
+
+// NamedConstraint is a component in WITH COMPONENTS: name (Constraint) PRESENT|ABSENT|OPTIONAL
+type NamedConstraint struct {
+	Node
+	Name string
+
+	// nil if absent
+	Constraint *Constraint
+	Presence   Presence
+}
+
+func (*Union) isElementSet()               {}
+func (*Intersection) isElementSet()        {}
+func (*Exclusion) isElementSet()           {}
+func (*SingleValue) isElementSet()         {}
+func (*ValueRange) isElementSet()          {}
+func (*ContainedSubtype) isElementSet()    {}
+func (*SizeConstraint) isElementSet()      {}
+func (*PermittedAlphabet) isElementSet()   {}
+func (*PatternConstraint) isElementSet()   {}
+func (*ContentsConstraint) isElementSet()  {}
+func (*InnerTypeConstraint) isElementSet() {}
@@ -0,0 +1,285 @@
+package schema
+
+// parseConstraint parses a constraint in parentheses
+func (p *parser) parseConstraint() (*Constraint, error) {
+	pos := p.current().pos
+	err := p.expectSymbol("(")
+	if err != nil {
+		return nil, err
+	}
+	return p.parseElementSetSpecs(pos, ")")
+}
+
+// parseElementSetSpecs parses the root element set, the extension marker, the additional element set and the exception of a constraint or value set, up to the closing symbol
+func (p *parser) parseElementSetSpecs(pos Position, closing string) (*Constraint, error) {
+	constraint := &Constraint{Node: Node{pos}}
+	var err error
+	if p.acceptSymbol("...") {
+		constraint.Extensible = true
+	} else {
+		constraint.Root, err = p.parseElementSetSpec()
+		if err != nil {
+			return nil, err
+		}
+		if isSymbol(p.current(), ",") && isSymbol(p.lookAhead(1), "...") {
+			p.index += 2
+			constraint.Extensible = true
+		}
+	}
+
+	if constraint.Extensible && p.acceptSymbol(",") {
+		constraint.Additions, err = p.parseElementSetSpec()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptSymbol("!") {
+		constraint.Exception, err = p.parseException()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !p.acceptSymbol(closing) {
+		if constraint.Extensible {
+			return nil, p.unexpected("\"" + closing + "\"")
+		}
+		return nil, p.unexpected("\",\" or \"" + closing + "\"")
+	}
+	return constraint, nil
+}
+
+// parseElementSetSpec parses unions of intersections of elements, or ALL EXCEPT elements
+func (p *parser) parseElementSetSpec() (ElementSet, error) {
+	t := p.current()
+	if !p.acceptWord("ALL") {
+		return p.parseUnions()
+	}
+	err := p.expectWord("EXCEPT")
+	if err != nil {
+		return nil, err
+	}
+	excluded, err := p.parseElements()
+	if err != nil {
+		return nil, err
+	}
+	return &Exclusion{Node: Node{t.pos}, Excluded: excluded}, nil
+}
+
+// parseUnions parses intersections separated by | or UNION
+func (p *parser) parseUnions() (ElementSet, error) {
+	first, err := p.parseIntersections()
+	if err != nil {
+		return nil, err
+	}
+	elements := []ElementSet{first}
+	for p.acceptSymbol("|") || p.acceptWord("UNION") {
+		element, err := p.parseIntersections()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	if len(elements) == 1 {
+		return first, nil
+	}
+	return &Union{Node: Node{first.Position()}, Elements: elements}, nil
+}
+
+// parseIntersections parses elements (possibly with exclusions) separated by ^ or INTERSECTION
+func (p *parser) parseIntersections() (ElementSet, error) {
+	first, err := p.parseIntersectionElements()
+	if err != nil {
+		return nil, err
+	}
+	elements := []ElementSet{first}
+	for p.acceptSymbol("^") || p.acceptWord("INTERSECTION") {
+		element, err := p.parseIntersectionElements()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	if len(elements) == 1 {
+		return first, nil
+	}
+	return &Intersection{Node: Node{first.Position()}, Elements: elements}, nil
+}
+
+// parseIntersectionElements parses elements, or elements EXCEPT elements
+func (p *parser) parseIntersectionElements() (ElementSet, error) {
+	elements, err := p.parseElements()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptWord("EXCEPT") {
+		return elements, nil
+	}
+	excluded, err := p.parseElements()
+	if err != nil {
+		return nil, err
+	}
+	return &Exclusion{Node: Node{elements.Position()}, Set: elements, Excluded: excluded}, nil
+}
+
+// parseElements parses an element set in parentheses or a subtype element
+func (p *parser) parseElements() (ElementSet, error) {
+	t := p.current()
+	var err error
+	switch {
+	case p.acceptSymbol("("):
+		set, err := p.parseElementSetSpec()
+		if err != nil {
+			return nil, err
+		}
+		return set, p.expectSymbol(")")
+	case p.acceptWord("SIZE"):
+		size := &SizeConstraint{Node: Node{t.pos}}
+		size.Constraint, err = p.parseConstraint()
+		return size, err
+	case p.acceptWord("FROM"):
+		alphabet := &PermittedAlphabet{Node: Node{t.pos}}
+		alphabet.Constraint, err = p.parseConstraint()
+		return alphabet, err
+	case p.acceptWord("PATTERN"):
+		pattern := &PatternConstraint{Node: Node{t.pos}}
+		pattern.Pattern, err = p.parseValue()
+		return pattern, err
+	case p.acceptWord("WITH"):
+		return p.parseInnerTypeConstraint(t.pos)
+	case isWord(t, "CONTAINING"), isWord(t, "ENCODED"):
+		return p.parseContentsConstraint()
+	case p.acceptWord("INCLUDES"):
+		subtype := &ContainedSubtype{Node: Node{t.pos}, Includes: true}
+		subtype.Type, err = p.parseType()
+		return subtype, err
+	case isWord(t, "CONSTRAINED"):
+		return nil, errorAt(t.pos, "user-defined constraints are not supported")
+	case isWord(t, "SETTINGS"):
+		return nil, errorAt(t.pos, "property settings are not supported")
+	case p.isTypeStart():
+		subtype := &ContainedSubtype{Node: Node{t.pos}}
+		subtype.Type, err = p.parseType()
+		return subtype, err
+	}
+	return p.parseValueOrRange()
+}
+
+// parseValueOrRange parses a single value or a value range (lower..upper, with < on the side of an excluded bound)
+func (p *parser) parseValueOrRange() (ElementSet, error) {
+	t := p.current()
+	lower, err := p.parseBound()
+	if err != nil {
+		return nil, err
+	}
+	lowerExcluded := isSymbol(p.current(), "<") && isSymbol(p.lookAhead(1), "..")
+	if lowerExcluded {
+		p.index++
+	}
+	if !p.acceptSymbol("..") {
+		switch lower.(type) {
+		case *MinValue, *MaxValue:
+			return nil, p.unexpected("\"..\"")
+		}
+		return &SingleValue{Node: Node{t.pos}, Value: lower}, nil
+	}
+
+	valueRange := &ValueRange{Node: Node{t.pos}, Lower: lower, LowerExcluded: lowerExcluded}
+	valueRange.UpperExcluded = p.acceptSymbol("<")
+	valueRange.Upper, err = p.parseBound()
+	if err != nil {
+		return nil, err
+	}
+	return valueRange, nil
+}
+
+// parseBound parses a bound of a value range: MIN, MAX or a value
+func (p *parser) parseBound() (Value, error) {
+	t := p.current()
+	if p.acceptWord("MIN") {
+		return &MinValue{Node: Node{t.pos}}, nil
+	}
+	if p.acceptWord("MAX") {
+		return &MaxValue{Node: Node{t.pos}}, nil
+	}
+	return p.parseValue()
+}
+
+// parseContentsConstraint parses CONTAINING Type, CONTAINING Type ENCODED BY Value or ENCODED BY Value
+func (p *parser) parseContentsConstraint() (ElementSet, error) {
+	contents := &ContentsConstraint{Node: Node{p.current().pos}}
+	var err error
+	if p.acceptWord("CONTAINING") {
+		contents.Type, err = p.parseType()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if p.acceptWord("ENCODED") {
+		err = p.expectWord("BY")
+		if err != nil {
+			return nil, err
+		}
+		contents.EncodedBy, err = p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return contents, nil
+}
+
+// parseInnerTypeConstraint parses the end of WITH COMPONENT (Constraint) or WITH COMPONENTS { ..., name (Constraint) PRESENT, ... }
+func (p *parser) parseInnerTypeConstraint(pos Position) (ElementSet, error) {
+	inner := &InnerTypeConstraint{Node: Node{pos}}
+	var err error
+	if p.acceptWord("COMPONENT") {
+		inner.Component, err = p.parseConstraint()
+		if err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+
+	err = p.expectWord("COMPONENTS")
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectSymbol("{")
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptSymbol("...") {
+		inner.Partial = true
+		err = p.expectSymbol(",")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	presences := map[string]Presence{"PRESENT": PresentPresence, "ABSENT": AbsentPresence, "OPTIONAL": OptionalPresence}
+	for {
+		name, err := p.expectIdentifier("a component identifier")
+		if err != nil {
+			return nil, err
+		}
+		named := &NamedConstraint{Node: Node{name.pos}, Name: name.text}
+		if isSymbol(p.current(), "(") {
+			named.Constraint, err = p.parseConstraint()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if presence, found := presences[p.current().text]; found && p.current().kind == wordToken {
+			named.Presence = presence
+			p.index++
+		}
+		inner.Components = append(inner.Components, named)
+
+		if p.acceptSymbol("}") {
+			return inner, nil
+		}
+		if !p.acceptSymbol(",") {
+			return nil, p.unexpected("\",\" or \"}\"")
+		}
+	}
+}
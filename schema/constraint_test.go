@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"testing"
+)
+
+// parseConstraintOf returns the constraint of the type of the first assignment of a module made of the text given between BEGIN and END
+func parseConstraintOf(t *testing.T, body string) *Constraint {
+	return parseTypeOf(t, body).(*ConstrainedType).Constraint
+}
+
+func TestParseValueConstraints(t *testing.T) {
+	constraint := parseConstraintOf(t, "T ::= INTEGER (0 | 2<..<10 | MIN..-1, ..., 20..MAX ! 5)")
+	union := constraint.Root.(*Union)
+	if len(union.Elements) != 3 || union.Pos != (Position{Line: 2, Column: 16}) {
+		t.Fatal("Wrong")
+	}
+	if union.Elements[0].(*SingleValue).Value.(*IntegerValue).Value.Int64() != 0 {
+		t.Fatal("Wrong")
+	}
+	valueRange := union.Elements[1].(*ValueRange)
+	if !valueRange.LowerExcluded || !valueRange.UpperExcluded || valueRange.Upper.(*IntegerValue).Value.Int64() != 10 {
+		t.Fatal("Wrong")
+	}
+	if _, isMin := union.Elements[2].(*ValueRange).Lower.(*MinValue); !isMin {
+		t.Fatal("Wrong")
+	}
+
+	if !constraint.Extensible || constraint.Exception.(*IntegerValue).Value.Int64() != 5 {
+		t.Fatal("Wrong")
+	}
+	additions := constraint.Additions.(*ValueRange)
+	if _, isMax := additions.Upper.(*MaxValue); !isMax || additions.LowerExcluded || additions.UpperExcluded {
+		t.Fatal("Wrong")
+	}
+
+	constraint = parseConstraintOf(t, "T ::= INTEGER (...)")
+	if constraint.Root != nil || !constraint.Extensible || constraint.Additions != nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseSetOperators(t *testing.T) {
+	constraint := parseConstraintOf(t, `T ::= PrintableString (FROM ("A".."Z" UNION "a".."z") ^ SIZE (1..64) INTERSECTION (ALL EXCEPT "x") EXCEPT Other)`)
+	intersection := constraint.Root.(*Intersection)
+	if len(intersection.Elements) != 3 {
+		t.Fatal("Wrong")
+	}
+	alphabet := intersection.Elements[0].(*PermittedAlphabet)
+	if len(alphabet.Constraint.Root.(*Union).Elements) != 2 {
+		t.Fatal("Wrong")
+	}
+	if _, isSize := intersection.Elements[1].(*SizeConstraint); !isSize {
+		t.Fatal("Wrong")
+	}
+	exclusion := intersection.Elements[2].(*Exclusion)
+	if exclusion.Set.(*Exclusion).Set != nil || exclusion.Excluded.(*ContainedSubtype).Type.(*TypeReference).Name != "Other" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseOtherConstraints(t *testing.T) {
+	constraint := parseConstraintOf(t, `T ::= OCTET STRING (CONTAINING Inner ENCODED BY { joint-iso-itu-t asn1(1) basic-encoding(1) })`)
+	contents := constraint.Root.(*ContentsConstraint)
+	if contents.Type.(*TypeReference).Name != "Inner" || len(contents.EncodedBy.(*ObjectIdentifierValue).Components) != 3 {
+		t.Fatal("Wrong")
+	}
+
+	constraint = parseConstraintOf(t, `T ::= Base (WITH COMPONENTS { ..., a (1..2) PRESENT, b ABSENT, c })`)
+	inner := constraint.Root.(*InnerTypeConstraint)
+	if !inner.Partial || inner.Component != nil || len(inner.Components) != 3 {
+		t.Fatal("Wrong")
+	}
+	if inner.Components[0].Presence != PresentPresence || inner.Components[0].Constraint == nil || inner.Components[1].Presence != AbsentPresence || inner.Components[2].Presence != NoPresence {
+		t.Fatal("Wrong")
+	}
+
+	constraint = parseConstraintOf(t, `T ::= Base (WITH COMPONENT (0..9))`)
+	if constraint.Root.(*InnerTypeConstraint).Component == nil {
+		t.Fatal("Wrong")
+	}
+
+	constraint = parseConstraintOf(t, `T ::= UTF8String (PATTERN "[a-z]+" | INCLUDES Other | BOOLEAN)`)
+	elements := constraint.Root.(*Union).Elements
+	if elements[0].(*PatternConstraint).Pattern.(*StringValue).Value != "[a-z]+" || !elements[1].(*ContainedSubtype).Includes || elements[2].(*ContainedSubtype).Includes {
+		t.Fatal("Wrong")
+	}
+
+	constrained := parseTypeOf(t, `T ::= INTEGER (0..9) (1 | 2)`).(*ConstrainedType)
+	if _, isConstrained := constrained.Type.(*ConstrainedType); !isConstrained {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	errors := map[string]string{
+		"T ::= INTEGER (1..)":                       "line 2, column 19: expecting a value, found \")\"",
+		"T ::= INTEGER (MIN)":                       "line 2, column 19: expecting \"..\", found \")\"",
+		"T ::= INTEGER (1 2)":                       "line 2, column 18: expecting \",\" or \")\", found 2",
+		"T ::= INTEGER (1, ..., 2, 3)":              "line 2, column 25: expecting \")\", found \",\"",
+		"T ::= INTEGER (CONSTRAINED BY {})":         "line 2, column 16: user-defined constraints are not supported",
+		"T ::= Base (WITH COMPONENTS { a PRESENT )": "line 2, column 41: expecting \",\" or \"}\", found \")\"",
+	}
+	for body, message := range errors {
+		if parseError(t, "M DEFINITIONS ::= BEGIN\n"+body+"\nEND") != message {
+			t.Fatal("Wrong:", parseError(t, "M DEFINITIONS ::= BEGIN\n"+body+"\nEND"))
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize("A ::= SEQUENCE { -- comment\n a [[ 1..<2 ]], /* nested /* */ */ b '01'B ... 'CAFE'H \"x\"\"y\" -1.5e3 }")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	expected := []string{"A", "::=", "SEQUENCE", "{", "a", "[[", "1", "..", "<", "2", "]]", ",", "b", "01", "...", "CAFE", "x\"y", "-", "1.5e3", "}", ""}
+	if len(tokens) != len(expected) {
+		t.Fatal("Wrong")
+	}
+	for i, token := range tokens {
+		if token.text != expected[i] {
+			t.Fatal("Wrong:", token.text)
+		}
+	}
+	if tokens[4].pos != (Position{Line: 2, Column: 2}) || tokens[13].kind != bstringToken || tokens[15].kind != hstringToken || tokens[20].kind != endToken {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestTokenizeWords(t *testing.T) {
+	tokens, err := tokenize("id-ce--comment--TIME-OF-DAY a-")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if len(tokens) != 5 || tokens[0].text != "id-ce" || tokens[1].text != "TIME-OF-DAY" || tokens[2].text != "a" || tokens[3].text != "-" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestTokenizeErrors(t *testing.T) {
+	invalids := map[string]string{
+		"A ::= #":          "line 1, column 7: unexpected character '#'",
+		"/* a":             "line 1, column 1: unterminated comment",
+		"\n  \"abc":        "line 2, column 3: unterminated cstring",
+		"'012'B":           "line 1, column 1: invalid bstring '012'B",
+		"'01'X":            "line 1, column 1: expecting B or H after '01'",
+		"007":              "line 1, column 1: number 007 has leading zeros",
+		"A ::= INTEGER 5a": "line 1, column 15: unexpected character 'a' after number",
+	}
+	for text, message := range invalids {
+		_, err := tokenize(text)
+		if err == nil || err.Error() != message {
+			t.Fatal("Wrong:", err)
+		}
+		if _, isError := err.(*Error); !isError {
+			t.Fatal("Wrong")
+		}
+	}
+}
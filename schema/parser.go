@@ -0,0 +1,375 @@
+package schema
+
+// Parse returns the modules defined in text, errors are *Error values giving the position of the offending token
+func Parse(text string) ([]*Module, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	modules := []*Module{}
+	for {
+		// the module is parsed again once its types are known, to parse values in braces according to their type
+		start := p.index
+		module, err := p.parseModule()
+		if err != nil {
+			return nil, err
+		}
+		p.index = start
+		p.types = map[string]Type{}
+		for _, assignment := range module.Assignments {
+			if assignment, ok := assignment.(*TypeAssignment); ok {
+				p.types[assignment.Name] = assignment.Type
+			}
+		}
+		module, err = p.parseModule()
+		p.types = nil
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, module)
+		if p.current().kind == endToken {
+			return modules, nil
+		}
+	}
+}
+
+// reservedWords are the reserved words of X.680 (12.38), which cannot be references or identifiers
+var reservedWords = map[string]bool{
+	"ABSENT": true, "ABSTRACT-SYNTAX": true, "ALL": true, "APPLICATION": true, "AUTOMATIC": true, "BEGIN": true,
+	"BIT": true, "BMPString": true, "BOOLEAN": true, "BY": true, "CHARACTER": true, "CHOICE": true, "CLASS": true,
+	"COMPONENT": true, "COMPONENTS": true, "CONSTRAINED": true, "CONTAINING": true, "DATE": true, "DATE-TIME": true,
+	"DEFAULT": true, "DEFINITIONS": true, "DURATION": true, "EMBEDDED": true, "ENCODED": true, "ENCODING-CONTROL": true,
+	"END": true, "ENUMERATED": true, "EXCEPT": true, "EXPLICIT": true, "EXPORTS": true, "EXTENSIBILITY": true,
+	"EXTERNAL": true, "FALSE": true, "FROM": true, "GeneralizedTime": true, "GeneralString": true, "GraphicString": true,
+	"IA5String": true, "IDENTIFIER": true, "IMPLICIT": true, "IMPLIED": true, "IMPORTS": true, "INCLUDES": true,
+	"INSTANCE": true, "INSTRUCTIONS": true, "INTEGER": true, "INTERSECTION": true, "ISO646String": true, "MAX": true,
+	"MIN": true, "MINUS-INFINITY": true, "NOT-A-NUMBER": true, "NULL": true, "NumericString": true, "OBJECT": true,
+	"ObjectDescriptor": true, "OCTET": true, "OF": true, "OID-IRI": true, "OPTIONAL": true, "PATTERN": true, "PDV": true,
+	"PLUS-INFINITY": true, "PRESENT": true, "PrintableString": true, "PRIVATE": true, "REAL": true, "RELATIVE-OID": true,
+	"RELATIVE-OID-IRI": true, "SEQUENCE": true, "SET": true, "SETTINGS": true, "SIZE": true, "STRING": true,
+	"SYNTAX": true, "T61String": true, "TAGS": true, "TeletexString": true, "TIME": true, "TIME-OF-DAY": true,
+	"TRUE": true, "TYPE-IDENTIFIER": true, "UNION": true, "UNIQUE": true, "UNIVERSAL": true, "UniversalString": true,
+	"UTCTime": true, "UTF8String": true, "VideotexString": true, "VisibleString": true, "WITH": true,
+}
+
+// parser builds the tree of modules from tokens
+type parser struct {
+	// tokens of the text, the last one being an endToken
+	tokens []token
+
+	// index of the current token
+	index int
+
+	// types assigned in the module being parsed, nil until the module has been parsed once
+	types map[string]Type
+}
+
+// current returns the current token
+func (p *parser) current() token {
+	return p.tokens[p.index]
+}
+
+// lookAhead returns the token following the current one by n
+func (p *parser) lookAhead(n int) token {
+	if p.index+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.index+n]
+}
+
+// isSymbol returns true if t is the symbol given
+func isSymbol(t token, symbol string) bool {
+	return t.kind == symbolToken && t.text == symbol
+}
+
+// isWord returns true if t is the word given
+func isWord(t token, word string) bool {
+	return t.kind == wordToken && t.text == word
+}
+
+// isReference returns true if t is a type or module reference: a word starting with an upper case letter which is not a reserved word
+func isReference(t token) bool {
+	return t.kind == wordToken && t.text[0] >= 'A' && t.text[0] <= 'Z' && !reservedWords[t.text]
+}
+
+// isIdentifier returns true if t is an identifier or a value reference: a word starting with a lower case letter
+func isIdentifier(t token) bool {
+	return t.kind == wordToken && t.text[0] >= 'a' && t.text[0] <= 'z'
+}
+
+// errorAt returns an error located at a position
+func errorAt(pos Position, message string) error {
+	return &Error{Pos: pos, Message: message}
+}
+
+// unexpected returns an error located at the current token
+func (p *parser) unexpected(expected string) error {
+	t := p.current()
+	return errorAt(t.pos, "expecting "+expected+", found "+t.describe())
+}
+
+// expectSymbol consumes the symbol given, raises an error if it is not the current token
+func (p *parser) expectSymbol(symbol string) error {
+	if !isSymbol(p.current(), symbol) {
+		return p.unexpected("\"" + symbol + "\"")
+	}
+	p.index++
+	return nil
+}
+
+// expectWord consumes the reserved word given, raises an error if it is not the current token
+func (p *parser) expectWord(word string) error {
+	if !isWord(p.current(), word) {
+		return p.unexpected(word)
+	}
+	p.index++
+	return nil
+}
+
+// acceptSymbol consumes the symbol given and returns true if it is the current token
+func (p *parser) acceptSymbol(symbol string) bool {
+	if isSymbol(p.current(), symbol) {
+		p.index++
+		return true
+	}
+	return false
+}
+
+// acceptWord consumes the reserved word given and returns true if it is the current token
+func (p *parser) acceptWord(word string) bool {
+	if isWord(p.current(), word) {
+		p.index++
+		return true
+	}
+	return false
+}
+
+// expectReference consumes a type or module reference and returns it
+func (p *parser) expectReference(expected string) (token, error) {
+	t := p.current()
+	if !isReference(t) {
+		return t, p.unexpected(expected)
+	}
+	p.index++
+	return t, nil
+}
+
+// expectIdentifier consumes an identifier and returns it
+func (p *parser) expectIdentifier(expected string) (token, error) {
+	t := p.current()
+	if !isIdentifier(t) {
+		return t, p.unexpected(expected)
+	}
+	p.index++
+	return t, nil
+}
+
+// parseModule parses a module definition
+func (p *parser) parseModule() (*Module, error) {
+	name, err := p.expectReference("a module reference")
+	if err != nil {
+		return nil, err
+	}
+	module := &Module{Node: Node{name.pos}, Name: name.text, ExportsAll: true}
+
+	if isSymbol(p.current(), "{") {
+		module.Identifier, err = p.parseObjectIdentifierValue()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = p.expectWord("DEFINITIONS")
+	if err != nil {
+		return nil, err
+	}
+	err = p.parseModuleDefaults(module)
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectSymbol("::=")
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectWord("BEGIN")
+	if err != nil {
+		return nil, err
+	}
+
+	if isWord(p.current(), "EXPORTS") {
+		err = p.parseExports(module)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if isWord(p.current(), "IMPORTS") {
+		module.Imports, err = p.parseImports()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	defined := map[string]Position{}
+	for !p.acceptWord("END") {
+		t := p.current()
+		assignment, err := p.parseAssignment()
+		if err != nil {
+			return nil, err
+		}
+		if pos, found := defined[t.text]; found {
+			return nil, errorAt(t.pos, t.text+" already defined at "+pos.String())
+		}
+		defined[t.text] = t.pos
+		module.Assignments = append(module.Assignments, assignment)
+	}
+	return module, nil
+}
+
+// parseModuleDefaults parses the tag default and the extension default of a module
+func (p *parser) parseModuleDefaults(module *Module) error {
+	tagDefaults := map[string]TagDefault{"EXPLICIT": ExplicitTags, "IMPLICIT": ImplicitTags, "AUTOMATIC": AutomaticTags}
+	if tagDefault, found := tagDefaults[p.current().text]; found && p.current().kind == wordToken {
+		p.index++
+		err := p.expectWord("TAGS")
+		if err != nil {
+			return err
+		}
+		module.TagDefault = tagDefault
+	}
+	if p.acceptWord("EXTENSIBILITY") {
+		err := p.expectWord("IMPLIED")
+		if err != nil {
+			return err
+		}
+		module.ExtensibilityImplied = true
+	}
+	return nil
+}
+
+// parseExports parses EXPORTS ALL; or EXPORTS followed by a (possibly empty) list of symbols
+func (p *parser) parseExports(module *Module) error {
+	p.index++
+	module.ExportsAll = false
+	if p.acceptWord("ALL") {
+		module.ExportsAll = true
+		return p.expectSymbol(";")
+	}
+	if p.acceptSymbol(";") {
+		return nil
+	}
+	for {
+		symbol, err := p.parseSymbol()
+		if err != nil {
+			return err
+		}
+		module.Exports = append(module.Exports, symbol)
+		if p.acceptSymbol(";") {
+			return nil
+		}
+		if !p.acceptSymbol(",") {
+			return p.unexpected("\",\" or \";\"")
+		}
+	}
+}
+
+// parseImports parses IMPORTS followed by lists of symbols imported from modules
+func (p *parser) parseImports() ([]*Import, error) {
+	p.index++
+	imports := []*Import{}
+	for !p.acceptSymbol(";") {
+		imported := &Import{Node: Node{p.current().pos}}
+		for {
+			symbol, err := p.parseSymbol()
+			if err != nil {
+				return nil, err
+			}
+			imported.Symbols = append(imported.Symbols, symbol)
+			if p.acceptWord("FROM") {
+				break
+			}
+			if !p.acceptSymbol(",") {
+				return nil, p.unexpected("\",\" or FROM")
+			}
+		}
+
+		name, err := p.expectReference("a module reference")
+		if err != nil {
+			return nil, err
+		}
+		imported.Module = name.text
+
+		// a value reference identifies the module unless it starts the next list of symbols
+		switch {
+		case isSymbol(p.current(), "{"):
+			imported.ModuleIdentifier, err = p.parseObjectIdentifierValue()
+			if err != nil {
+				return nil, err
+			}
+		case isIdentifier(p.current()) && !isSymbol(p.lookAhead(1), ",") && !isWord(p.lookAhead(1), "FROM"):
+			t := p.current()
+			p.index++
+			imported.ModuleIdentifier = &ValueReference{Node: Node{t.pos}, Name: t.text}
+		}
+		imports = append(imports, imported)
+	}
+	return imports, nil
+}
+
+// parseSymbol parses a type or value reference in EXPORTS or IMPORTS
+func (p *parser) parseSymbol() (*Symbol, error) {
+	t := p.current()
+	if !isReference(t) && !isIdentifier(t) {
+		return nil, p.unexpected("a reference")
+	}
+	p.index++
+	return &Symbol{Node: Node{t.pos}, Name: t.text}, nil
+}
+
+// parseAssignment parses a type, value or value set assignment
+func (p *parser) parseAssignment() (Assignment, error) {
+	name := p.current()
+	if !isReference(name) && !isIdentifier(name) {
+		return nil, p.unexpected("an assignment or END")
+	}
+	p.index++
+	if isSymbol(p.current(), "{") {
+		return nil, errorAt(name.pos, "parameterized assignments are not supported")
+	}
+
+	if isReference(name) && p.acceptSymbol("::=") {
+		t, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return &TypeAssignment{Node: Node{name.pos}, Name: name.text, Type: t}, nil
+	}
+
+	t, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectSymbol("::=")
+	if err != nil {
+		return nil, err
+	}
+
+	if isIdentifier(name) {
+		value, err := p.parseTypedValue(t)
+		if err != nil {
+			return nil, err
+		}
+		return &ValueAssignment{Node: Node{name.pos}, Name: name.text, Type: t, Value: value}, nil
+	}
+
+	pos := p.current().pos
+	err = p.expectSymbol("{")
+	if err != nil {
+		return nil, err
+	}
+	set, err := p.parseElementSetSpecs(pos, "}")
+	if err != nil {
+		return nil, err
+	}
+	return &ValueSetAssignment{Node: Node{name.pos}, Name: name.text, Type: t, Set: set}, nil
+}
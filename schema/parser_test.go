@@ -0,0 +1,149 @@
+package schema
+
+import (
+	"testing"
+)
+
+// parseBody parses a module made of the text given between BEGIN and END
+func parseBody(t *testing.T, body string) *Module {
+	modules, err := Parse("M DEFINITIONS ::= BEGIN\n" + body + "\nEND")
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	return modules[0]
+}
+
+// parseError returns the message of the error raised when parsing text
+func parseError(t *testing.T, text string) string {
+	_, err := Parse(text)
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+	if _, isError := err.(*Error); !isError {
+		t.Fatal("Wrong")
+	}
+	return err.Error()
+}
+
+func TestParseModuleHeader(t *testing.T) {
+	text := `
+Module-A { iso(1) standard 8571 application-context(1) } DEFINITIONS
+AUTOMATIC TAGS EXTENSIBILITY IMPLIED ::= BEGIN
+END
+Module-B DEFINITIONS IMPLICIT TAGS ::= BEGIN END`
+	modules, err := Parse(text)
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+	if len(modules) != 2 {
+		t.Fatal("Wrong")
+	}
+
+	a := modules[0]
+	if a.Name != "Module-A" || a.Pos != (Position{Line: 2, Column: 1}) || a.TagDefault != AutomaticTags || !a.ExtensibilityImplied || !a.ExportsAll {
+		t.Fatal("Wrong")
+	}
+	arcs := a.Identifier.Components
+	if len(arcs) != 4 || arcs[0].Name != "iso" || arcs[0].Number.(*IntegerValue).Value.Int64() != 1 {
+		t.Fatal("Wrong")
+	}
+	if arcs[1].Name != "standard" || arcs[1].Number != nil || arcs[2].Name != "" || arcs[2].Number.(*IntegerValue).Value.Int64() != 8571 {
+		t.Fatal("Wrong")
+	}
+
+	b := modules[1]
+	if b.Name != "Module-B" || b.Identifier != nil || b.TagDefault != ImplicitTags || b.ExtensibilityImplied || len(b.Assignments) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseExportsImports(t *testing.T) {
+	module := parseBody(t, `EXPORTS A, b;
+IMPORTS
+  T1, v1 FROM M1 { 1 2 3 }
+  T2 FROM M2 m2-id
+  T3, T4 FROM M3
+  v5 FROM M5;`)
+
+	if module.ExportsAll || len(module.Exports) != 2 || module.Exports[1].Name != "b" || module.Exports[1].Pos != (Position{Line: 2, Column: 12}) {
+		t.Fatal("Wrong")
+	}
+
+	imports := module.Imports
+	if len(imports) != 4 {
+		t.Fatal("Wrong")
+	}
+	if imports[0].Module != "M1" || len(imports[0].Symbols) != 2 || imports[0].Symbols[1].Name != "v1" || imports[0].Pos != (Position{Line: 4, Column: 3}) {
+		t.Fatal("Wrong")
+	}
+	if oid, isOID := imports[0].ModuleIdentifier.(*ObjectIdentifierValue); !isOID || len(oid.Components) != 3 {
+		t.Fatal("Wrong")
+	}
+	if reference, isReference := imports[1].ModuleIdentifier.(*ValueReference); !isReference || reference.Name != "m2-id" {
+		t.Fatal("Wrong")
+	}
+	if imports[2].Module != "M3" || imports[2].ModuleIdentifier != nil || len(imports[2].Symbols) != 2 {
+		t.Fatal("Wrong")
+	}
+	if imports[3].Module != "M5" || imports[3].Symbols[0].Name != "v5" || imports[3].ModuleIdentifier != nil {
+		t.Fatal("Wrong")
+	}
+
+	module = parseBody(t, "EXPORTS ALL;")
+	if !module.ExportsAll {
+		t.Fatal("Wrong")
+	}
+	module = parseBody(t, "EXPORTS ;")
+	if module.ExportsAll || len(module.Exports) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseAssignments(t *testing.T) {
+	module := parseBody(t, `T ::= INTEGER
+v T ::= 5
+S T ::= { 1 | 2, ... }`)
+	if len(module.Assignments) != 3 {
+		t.Fatal("Wrong")
+	}
+
+	typeAssignment, isType := module.Assignments[0].(*TypeAssignment)
+	if !isType || typeAssignment.Name != "T" || typeAssignment.Pos != (Position{Line: 2, Column: 1}) {
+		t.Fatal("Wrong")
+	}
+	if _, isInteger := typeAssignment.Type.(*IntegerType); !isInteger {
+		t.Fatal("Wrong")
+	}
+
+	valueAssignment, isValue := module.Assignments[1].(*ValueAssignment)
+	if !isValue || valueAssignment.Name != "v" || valueAssignment.Type.(*TypeReference).Name != "T" || valueAssignment.Value.(*IntegerValue).Value.Int64() != 5 {
+		t.Fatal("Wrong")
+	}
+
+	setAssignment, isSet := module.Assignments[2].(*ValueSetAssignment)
+	if !isSet || setAssignment.Name != "S" || !setAssignment.Set.Extensible || len(setAssignment.Set.Root.(*Union).Elements) != 2 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	errors := map[string]string{
+		"":                                      "line 1, column 1: expecting a module reference, found end of text",
+		"m DEFINITIONS ::= BEGIN END":           "line 1, column 1: expecting a module reference, found m",
+		"M ::= BEGIN END":                       "line 1, column 3: expecting DEFINITIONS, found \"::=\"",
+		"M DEFINITIONS AUTOMATIC ::= BEGIN END": "line 1, column 25: expecting TAGS, found \"::=\"",
+		"M DEFINITIONS ::= BEGIN":               "line 1, column 24: expecting an assignment or END, found end of text",
+		"M DEFINITIONS ::= BEGIN\nA ::= INTEGER\nA ::= REAL END": "line 3, column 1: A already defined at line 2, column 1",
+		"M DEFINITIONS ::= BEGIN IMPORTS A B FROM N; END":        "line 1, column 35: expecting \",\" or FROM, found B",
+		"M DEFINITIONS ::= BEGIN EXPORTS A END":                  "line 1, column 35: expecting \",\" or \";\", found END",
+		"M { 1 x-y(z) 2. } DEFINITIONS ::= BEGIN END":            "line 1, column 15: expecting an arc or \"}\", found \".\"",
+		"M DEFINITIONS ::= BEGIN A { T } ::= SEQUENCE OF T END":  "line 1, column 25: parameterized assignments are not supported",
+		"M DEFINITIONS ::= BEGIN x INTEGER ::= END":              "line 1, column 39: expecting a value, found END",
+		"M DEFINITIONS ::= BEGIN S INTEGER ::= 5 END":            "line 1, column 39: expecting \"{\", found 5",
+	}
+	for text, message := range errors {
+		if parseError(t, text) != message {
+			t.Fatal("Wrong:", parseError(t, text))
+		}
+	}
+}
@@ -0,0 +1,530 @@
+package schema
+
+import (
+	"github.com/yafred/asn1-go/types"
+)
+
+// builtinTypes are the built-in types written as a single reserved word
+var builtinTypes = map[string]bool{
+	"BOOLEAN": true, "NULL": true, "REAL": true, "RELATIVE-OID": true, "EXTERNAL": true,
+	"UTCTime": true, "GeneralizedTime": true, "ObjectDescriptor": true,
+	"TIME": true, "DATE": true, "TIME-OF-DAY": true, "DATE-TIME": true, "DURATION": true,
+	"OID-IRI": true, "RELATIVE-OID-IRI": true,
+	"BMPString": true, "GeneralString": true, "GraphicString": true, "IA5String": true, "ISO646String": true,
+	"NumericString": true, "PrintableString": true, "TeletexString": true, "T61String": true,
+	"UniversalString": true, "UTF8String": true, "VideotexString": true, "VisibleString": true,
+}
+
+// builtinTypePairs are the built-in types written as 2 reserved words
+var builtinTypePairs = map[string]string{
+	"OCTET":     "STRING",
+	"OBJECT":    "IDENTIFIER",
+	"CHARACTER": "STRING",
+	"EMBEDDED":  "PDV",
+}
+
+// unsupportedTypes are the reserved words starting types of X.681 which are not supported
+var unsupportedTypes = map[string]bool{
+	"CLASS": true, "TYPE-IDENTIFIER": true, "ABSTRACT-SYNTAX": true, "INSTANCE": true,
+}
+
+// isTypeStart returns true if the current token starts a type rather than a value
+func (p *parser) isTypeStart() bool {
+	t := p.current()
+	switch {
+	case isSymbol(t, "["):
+		return true
+	case t.kind != wordToken:
+		return false
+	case isReference(t):
+		// Module.value is a value
+		return !isSymbol(p.lookAhead(1), ".") || !isIdentifier(p.lookAhead(2))
+	case isIdentifier(t):
+		return isSymbol(p.lookAhead(1), "<")
+	}
+	_, isPair := builtinTypePairs[t.text]
+	return builtinTypes[t.text] && t.text != "NULL" || isPair || t.text == "INTEGER" || t.text == "BIT" || t.text == "ENUMERATED" ||
+		t.text == "SEQUENCE" || t.text == "SET" || t.text == "CHOICE"
+}
+
+// parseType parses a type followed by its constraints
+func (p *parser) parseType() (Type, error) {
+	t, err := p.parseUnconstrainedType()
+	if err != nil {
+		return nil, err
+	}
+	for isSymbol(p.current(), "(") {
+		constraint, err := p.parseConstraint()
+		if err != nil {
+			return nil, err
+		}
+		t = &ConstrainedType{Node: Node{t.Position()}, Type: t, Constraint: constraint}
+	}
+	return t, nil
+}
+
+// parseUnconstrainedType parses a type without the constraints following it
+func (p *parser) parseUnconstrainedType() (Type, error) {
+	t := p.current()
+	if isSymbol(t, "[") {
+		return p.parseTaggedType()
+	}
+	if t.kind != wordToken {
+		return nil, p.unexpected("a type")
+	}
+
+	if builtinTypes[t.text] {
+		p.index++
+		return &BuiltinType{Node: Node{t.pos}, Name: t.text}, nil
+	}
+	if second, found := builtinTypePairs[t.text]; found {
+		p.index++
+		err := p.expectWord(second)
+		if err != nil {
+			return nil, err
+		}
+		return &BuiltinType{Node: Node{t.pos}, Name: t.text + " " + second}, nil
+	}
+	if unsupportedTypes[t.text] {
+		return nil, errorAt(t.pos, "information object classes are not supported")
+	}
+
+	switch t.text {
+	case "INTEGER":
+		p.index++
+		integer := &IntegerType{Node: Node{t.pos}}
+		if isSymbol(p.current(), "{") {
+			var err error
+			integer.NamedNumbers, err = p.parseNamedNumbers(true)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return integer, nil
+	case "BIT":
+		p.index++
+		err := p.expectWord("STRING")
+		if err != nil {
+			return nil, err
+		}
+		bitString := &BitStringType{Node: Node{t.pos}}
+		if isSymbol(p.current(), "{") {
+			bitString.NamedBits, err = p.parseNamedNumbers(false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return bitString, nil
+	case "ENUMERATED":
+		return p.parseEnumeratedType()
+	case "SEQUENCE", "SET":
+		return p.parseSequenceType()
+	case "CHOICE":
+		return p.parseChoiceType()
+	}
+
+	if isReference(t) {
+		p.index++
+		reference := &TypeReference{Node: Node{t.pos}, Name: t.text}
+		if isSymbol(p.current(), ".") && isReference(p.lookAhead(1)) {
+			reference.Module = t.text
+			reference.Name = p.lookAhead(1).text
+			p.index += 2
+		}
+		if isSymbol(p.current(), "{") {
+			return nil, errorAt(p.current().pos, "parameterized types are not supported")
+		}
+		return reference, nil
+	}
+	if isIdentifier(t) && isSymbol(p.lookAhead(1), "<") {
+		p.index += 2
+		selected, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return &SelectionType{Node: Node{t.pos}, Alternative: t.text, Type: selected}, nil
+	}
+	return nil, p.unexpected("a type")
+}
+
+// parseTaggedType parses [Class Number] IMPLICIT|EXPLICIT Type
+func (p *parser) parseTaggedType() (Type, error) {
+	tagged := &TaggedType{Node: Node{p.current().pos}, Class: types.ContextClass}
+	p.index++
+
+	classes := map[string]types.TagClass{"UNIVERSAL": types.UniversalClass, "APPLICATION": types.ApplicationClass, "PRIVATE": types.PrivateClass}
+	if class, found := classes[p.current().text]; found && p.current().kind == wordToken {
+		tagged.Class = class
+		p.index++
+	}
+
+	t := p.current()
+	switch {
+	case t.kind == numberToken:
+		number, err := parseNumber(t, false)
+		if err != nil {
+			return nil, err
+		}
+		if _, isInteger := number.(*IntegerValue); !isInteger {
+			return nil, errorAt(t.pos, "tag number must be an integer")
+		}
+		tagged.Number = number
+		p.index++
+	case isIdentifier(t):
+		tagged.Number = &ValueReference{Node: Node{t.pos}, Name: t.text}
+		p.index++
+	default:
+		return nil, p.unexpected("a tag number")
+	}
+
+	err := p.expectSymbol("]")
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptWord("IMPLICIT") {
+		tagged.Mode = ImplicitTagging
+	} else if p.acceptWord("EXPLICIT") {
+		tagged.Mode = ExplicitTagging
+	}
+
+	tagged.Type, err = p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return tagged, nil
+}
+
+// parseNamedNumbers parses the named numbers of an INTEGER ({ name(-1), ... }) or the named bits of a BIT STRING (numbers must not be negative)
+func (p *parser) parseNamedNumbers(signed bool) ([]*NamedNumber, error) {
+	p.index++
+	names := map[string]bool{}
+	namedNumbers := []*NamedNumber{}
+	for {
+		name, err := p.expectIdentifier("an identifier")
+		if err != nil {
+			return nil, err
+		}
+		if names[name.text] {
+			return nil, errorAt(name.pos, "duplicate name "+name.text)
+		}
+		names[name.text] = true
+
+		err = p.expectSymbol("(")
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.parseNumberOrReference(signed)
+		if err != nil {
+			return nil, err
+		}
+		err = p.expectSymbol(")")
+		if err != nil {
+			return nil, err
+		}
+		namedNumbers = append(namedNumbers, &NamedNumber{Node: Node{name.pos}, Name: name.text, Value: value})
+
+		if p.acceptSymbol("}") {
+			return namedNumbers, nil
+		}
+		if !p.acceptSymbol(",") {
+			return nil, p.unexpected("\",\" or \"}\"")
+		}
+	}
+}
+
+// parseEnumeratedType parses ENUMERATED { items, ..., additional items }
+func (p *parser) parseEnumeratedType() (Type, error) {
+	enumerated := &EnumeratedType{Node: Node{p.current().pos}}
+	p.index++
+	err := p.expectSymbol("{")
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for {
+		t := p.current()
+		if isSymbol(t, "...") {
+			if enumerated.Extensible {
+				return nil, errorAt(t.pos, "ENUMERATED cannot have more than one extension marker")
+			}
+			p.index++
+			enumerated.Extensible = true
+			if p.acceptSymbol("!") {
+				enumerated.Exception, err = p.parseException()
+				if err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			item, err := p.parseEnumerationItem()
+			if err != nil {
+				return nil, err
+			}
+			if names[item.Name] {
+				return nil, errorAt(item.Pos, "duplicate name "+item.Name)
+			}
+			names[item.Name] = true
+			if enumerated.Extensible {
+				enumerated.Additions = append(enumerated.Additions, item)
+			} else {
+				enumerated.Items = append(enumerated.Items, item)
+			}
+		}
+
+		if p.acceptSymbol("}") {
+			break
+		}
+		if !p.acceptSymbol(",") {
+			return nil, p.unexpected("\",\" or \"}\"")
+		}
+	}
+	if len(enumerated.Items) == 0 {
+		return nil, errorAt(enumerated.Pos, "ENUMERATED must have at least one root item")
+	}
+	return enumerated, nil
+}
+
+// parseEnumerationItem parses an item of an ENUMERATED: name or name(number)
+func (p *parser) parseEnumerationItem() (*NamedNumber, error) {
+	name, err := p.expectIdentifier("an enumeration item or ...")
+	if err != nil {
+		return nil, err
+	}
+	item := &NamedNumber{Node: Node{name.pos}, Name: name.text}
+	if p.acceptSymbol("(") {
+		item.Value, err = p.parseNumberOrReference(true)
+		if err != nil {
+			return nil, err
+		}
+		err = p.expectSymbol(")")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
+}
+
+// parseSequenceType parses SEQUENCE { ... }, SET { ... }, SEQUENCE OF Type or SET OF Type (with a constraint before OF)
+func (p *parser) parseSequenceType() (Type, error) {
+	t := p.current()
+	set := t.text == "SET"
+	p.index++
+
+	if isSymbol(p.current(), "{") {
+		sequence := &SequenceType{Node: Node{t.pos}, Set: set}
+		var err error
+		sequence.Components, sequence.Additions, sequence.TrailingComponents, err = p.parseComponents(&sequence.Extensible, &sequence.Exception, false)
+		if err != nil {
+			return nil, err
+		}
+		return sequence, nil
+	}
+
+	sequenceOf := &SequenceOfType{Node: Node{t.pos}, Set: set}
+	switch {
+	case isWord(p.current(), "SIZE"):
+		pos := p.current().pos
+		p.index++
+		size, err := p.parseConstraint()
+		if err != nil {
+			return nil, err
+		}
+		sequenceOf.Constraint = &Constraint{Node: Node{pos}, Root: &SizeConstraint{Node: Node{pos}, Constraint: size}}
+	case isSymbol(p.current(), "("):
+		var err error
+		sequenceOf.Constraint, err = p.parseConstraint()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := p.expectWord("OF")
+	if err != nil {
+		return nil, err
+	}
+	if isIdentifier(p.current()) && !isSymbol(p.lookAhead(1), "<") {
+		sequenceOf.ElementName = p.current().text
+		p.index++
+	}
+	sequenceOf.Element, err = p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return sequenceOf, nil
+}
+
+// parseChoiceType parses CHOICE { ... }
+func (p *parser) parseChoiceType() (Type, error) {
+	choice := &ChoiceType{Node: Node{p.current().pos}}
+	p.index++
+	if !isSymbol(p.current(), "{") {
+		return nil, p.unexpected("\"{\"")
+	}
+	var err error
+	choice.Alternatives, choice.Additions, _, err = p.parseComponents(&choice.Extensible, &choice.Exception, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(choice.Alternatives) == 0 {
+		return nil, errorAt(choice.Pos, "CHOICE must have at least one root alternative")
+	}
+	return choice, nil
+}
+
+// parseComponents parses the components of a SEQUENCE or SET or the alternatives of a CHOICE in braces
+// returns the root components, the extension additions and the components after the second extension marker (not allowed in a CHOICE)
+func (p *parser) parseComponents(extensible *bool, exception *Value, choice bool) ([]*Component, []*ExtensionAddition, []*Component, error) {
+	p.index++
+	root := []*Component{}
+	var additions []*ExtensionAddition
+	var trailing []*Component
+	if p.acceptSymbol("}") {
+		return root, additions, trailing, nil
+	}
+
+	names := map[string]bool{}
+	checkName := func(component *Component) error {
+		if component.ComponentsOf {
+			return nil
+		}
+		if names[component.Name] {
+			return errorAt(component.Pos, "duplicate name "+component.Name)
+		}
+		names[component.Name] = true
+		return nil
+	}
+
+	// 0: root components, 1: extension additions, 2: after the second extension marker
+	part := 0
+	for {
+		t := p.current()
+		switch {
+		case isSymbol(t, "..."):
+			if part == 2 {
+				return nil, nil, nil, errorAt(t.pos, "more than 2 extension markers")
+			}
+			p.index++
+			if part == 0 {
+				*extensible = true
+				if p.acceptSymbol("!") {
+					var err error
+					*exception, err = p.parseException()
+					if err != nil {
+						return nil, nil, nil, err
+					}
+				}
+			}
+			part++
+		case isSymbol(t, "[["):
+			if part != 1 {
+				return nil, nil, nil, errorAt(t.pos, "version brackets are only allowed in extension additions")
+			}
+			addition, err := p.parseExtensionGroup(choice)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			for _, component := range addition.Components {
+				err = checkName(component)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+			}
+			additions = append(additions, addition)
+		default:
+			if choice && part == 2 {
+				return nil, nil, nil, errorAt(t.pos, "CHOICE cannot have alternatives after the second extension marker")
+			}
+			component, err := p.parseComponent(choice)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			err = checkName(component)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			switch part {
+			case 0:
+				root = append(root, component)
+			case 1:
+				additions = append(additions, &ExtensionAddition{Node: Node{component.Pos}, Components: []*Component{component}})
+			case 2:
+				trailing = append(trailing, component)
+			}
+		}
+
+		if p.acceptSymbol("}") {
+			return root, additions, trailing, nil
+		}
+		if !p.acceptSymbol(",") {
+			return nil, nil, nil, p.unexpected("\",\" or \"}\"")
+		}
+	}
+}
+
+// parseExtensionGroup parses [[ version: components ]]
+func (p *parser) parseExtensionGroup(choice bool) (*ExtensionAddition, error) {
+	group := &ExtensionAddition{Node: Node{p.current().pos}, Group: true}
+	p.index++
+	if p.current().kind == numberToken && isSymbol(p.lookAhead(1), ":") {
+		var err error
+		group.Version, err = parseNumber(p.current(), false)
+		if err != nil {
+			return nil, err
+		}
+		p.index += 2
+	}
+	for {
+		component, err := p.parseComponent(choice)
+		if err != nil {
+			return nil, err
+		}
+		group.Components = append(group.Components, component)
+		if p.acceptSymbol("]]") {
+			return group, nil
+		}
+		if !p.acceptSymbol(",") {
+			return nil, p.unexpected("\",\" or \"]]\"")
+		}
+	}
+}
+
+// parseComponent parses name Type OPTIONAL|DEFAULT Value or COMPONENTS OF Type (only name Type for an alternative of a CHOICE)
+func (p *parser) parseComponent(choice bool) (*Component, error) {
+	t := p.current()
+	if !choice && isWord(t, "COMPONENTS") && isWord(p.lookAhead(1), "OF") {
+		p.index += 2
+		included, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		return &Component{Node: Node{t.pos}, Type: included, ComponentsOf: true}, nil
+	}
+
+	expected := "a component identifier"
+	if choice {
+		expected = "an alternative identifier"
+	}
+	name, err := p.expectIdentifier(expected)
+	if err != nil {
+		return nil, err
+	}
+	component := &Component{Node: Node{name.pos}, Name: name.text}
+	component.Type, err = p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	if choice {
+		return component, nil
+	}
+
+	if p.acceptWord("OPTIONAL") {
+		component.Optional = true
+	} else if p.acceptWord("DEFAULT") {
+		component.Default, err = p.parseTypedValue(component.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return component, nil
+}
@@ -0,0 +1,163 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// parseTypeOf returns the type of the first assignment of a module made of the text given between BEGIN and END
+func parseTypeOf(t *testing.T, body string) Type {
+	module := parseBody(t, body)
+	return module.Assignments[0].(*TypeAssignment).Type
+}
+
+func TestParseBuiltinTypes(t *testing.T) {
+	names := []string{"BOOLEAN", "NULL", "REAL", "OCTET STRING", "OBJECT IDENTIFIER", "RELATIVE-OID", "CHARACTER STRING", "EMBEDDED PDV", "UTF8String", "GeneralizedTime", "TIME-OF-DAY"}
+	for _, name := range names {
+		builtin, isBuiltin := parseTypeOf(t, "T ::= "+name).(*BuiltinType)
+		if !isBuiltin || builtin.Name != name {
+			t.Fatal("Wrong:", name)
+		}
+	}
+}
+
+func TestParseNamedNumbers(t *testing.T) {
+	integer := parseTypeOf(t, "T ::= INTEGER { low(-1), high(ub) }").(*IntegerType)
+	if len(integer.NamedNumbers) != 2 || integer.NamedNumbers[0].Value.(*IntegerValue).Value.Int64() != -1 || integer.NamedNumbers[1].Value.(*ValueReference).Name != "ub" {
+		t.Fatal("Wrong")
+	}
+
+	bitString := parseTypeOf(t, "T ::= BIT STRING { a(0), b(7) }").(*BitStringType)
+	if len(bitString.NamedBits) != 2 || bitString.NamedBits[1].Name != "b" || bitString.NamedBits[1].Pos != (Position{Line: 2, Column: 26}) {
+		t.Fatal("Wrong")
+	}
+
+	enumerated := parseTypeOf(t, "T ::= ENUMERATED { red, green(5), ... ! 1, blue }").(*EnumeratedType)
+	if len(enumerated.Items) != 2 || enumerated.Items[0].Value != nil || enumerated.Items[1].Value.(*IntegerValue).Value.Int64() != 5 {
+		t.Fatal("Wrong")
+	}
+	if !enumerated.Extensible || enumerated.Exception.(*IntegerValue).Value.Int64() != 1 || len(enumerated.Additions) != 1 || enumerated.Additions[0].Name != "blue" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseSequenceType(t *testing.T) {
+	sequence := parseTypeOf(t, `T ::= SEQUENCE {
+  a INTEGER,
+  b BOOLEAN OPTIONAL,
+  c [0] Version DEFAULT v1,
+  COMPONENTS OF Base,
+  ...,
+  d NULL,
+  [[ 2: e REAL, f IA5String ]],
+  ...,
+  g OCTET STRING
+}`).(*SequenceType)
+
+	if sequence.Set || !sequence.Extensible || sequence.Exception != nil || len(sequence.Components) != 4 {
+		t.Fatal("Wrong")
+	}
+	components := sequence.Components
+	if components[0].Name != "a" || components[0].Pos != (Position{Line: 3, Column: 3}) || components[0].Optional {
+		t.Fatal("Wrong")
+	}
+	if !components[1].Optional || components[2].Default.(*ValueReference).Name != "v1" {
+		t.Fatal("Wrong")
+	}
+	if !components[3].ComponentsOf || components[3].Name != "" || components[3].Type.(*TypeReference).Name != "Base" {
+		t.Fatal("Wrong")
+	}
+
+	additions := sequence.Additions
+	if len(additions) != 2 || additions[0].Group || additions[0].Components[0].Name != "d" {
+		t.Fatal("Wrong")
+	}
+	if !additions[1].Group || additions[1].Version.(*IntegerValue).Value.Int64() != 2 || len(additions[1].Components) != 2 || additions[1].Pos != (Position{Line: 9, Column: 3}) {
+		t.Fatal("Wrong")
+	}
+	if len(sequence.TrailingComponents) != 1 || sequence.TrailingComponents[0].Name != "g" {
+		t.Fatal("Wrong")
+	}
+
+	set := parseTypeOf(t, "T ::= SET { }").(*SequenceType)
+	if !set.Set || len(set.Components) != 0 || set.Extensible {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseChoiceType(t *testing.T) {
+	choice := parseTypeOf(t, "T ::= CHOICE { a INTEGER, ..., [[ b BOOLEAN ]], ... }").(*ChoiceType)
+	if len(choice.Alternatives) != 1 || !choice.Extensible || len(choice.Additions) != 1 || choice.Additions[0].Version != nil {
+		t.Fatal("Wrong")
+	}
+
+	selection := parseTypeOf(t, "T ::= a < Choice").(*SelectionType)
+	if selection.Alternative != "a" || selection.Type.(*TypeReference).Name != "Choice" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseSequenceOfType(t *testing.T) {
+	sequenceOf := parseTypeOf(t, "T ::= SEQUENCE SIZE (1..MAX) OF item Item").(*SequenceOfType)
+	if sequenceOf.Set || sequenceOf.ElementName != "item" || sequenceOf.Element.(*TypeReference).Name != "Item" {
+		t.Fatal("Wrong")
+	}
+	if _, isSize := sequenceOf.Constraint.Root.(*SizeConstraint); !isSize {
+		t.Fatal("Wrong")
+	}
+
+	setOf := parseTypeOf(t, "T ::= SET (SIZE (2)) OF INTEGER (0..9)").(*SequenceOfType)
+	if !setOf.Set || setOf.ElementName != "" || setOf.Constraint == nil {
+		t.Fatal("Wrong")
+	}
+	if _, isConstrained := setOf.Element.(*ConstrainedType); !isConstrained {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseTaggedType(t *testing.T) {
+	tagged := parseTypeOf(t, "T ::= [APPLICATION 5] IMPLICIT SEQUENCE OF [1] EXPLICIT M.Type").(*TaggedType)
+	if tagged.Class != types.ApplicationClass || tagged.Number.(*IntegerValue).Value.Int64() != 5 || tagged.Mode != ImplicitTagging {
+		t.Fatal("Wrong")
+	}
+	inner := tagged.Type.(*SequenceOfType).Element.(*TaggedType)
+	if inner.Class != types.ContextClass || inner.Mode != ExplicitTagging {
+		t.Fatal("Wrong")
+	}
+	reference := inner.Type.(*TypeReference)
+	if reference.Module != "M" || reference.Name != "Type" {
+		t.Fatal("Wrong")
+	}
+
+	tagged = parseTypeOf(t, "T ::= [PRIVATE tag-number] BOOLEAN").(*TaggedType)
+	if tagged.Class != types.PrivateClass || tagged.Number.(*ValueReference).Name != "tag-number" || tagged.Mode != DefaultTagging {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseTypeErrors(t *testing.T) {
+	errors := map[string]string{
+		"T ::= SEQUENCE { a INTEGER b BOOLEAN }":    "line 2, column 28: expecting \",\" or \"}\", found b",
+		"T ::= SEQUENCE { A INTEGER }":              "line 2, column 18: expecting a component identifier, found A",
+		"T ::= SEQUENCE { a INTEGER, a BOOLEAN }":   "line 2, column 29: duplicate name a",
+		"T ::= SEQUENCE { [[ a INTEGER ]] }":        "line 2, column 18: version brackets are only allowed in extension additions",
+		"T ::= SEQUENCE { ..., ..., ... }":          "line 2, column 28: more than 2 extension markers",
+		"T ::= CHOICE { a INTEGER OPTIONAL }":       "line 2, column 26: expecting \",\" or \"}\", found OPTIONAL",
+		"T ::= CHOICE { }":                          "line 2, column 7: CHOICE must have at least one root alternative",
+		"T ::= CHOICE { a NULL, ..., ..., b NULL }": "line 2, column 34: CHOICE cannot have alternatives after the second extension marker",
+		"T ::= ENUMERATED { a, ..., b, ... }":       "line 2, column 31: ENUMERATED cannot have more than one extension marker",
+		"T ::= ENUMERATED { a, a }":                 "line 2, column 23: duplicate name a",
+		"T ::= BIT STRING { a(-1) }":                "line 2, column 22: expecting a number or a value reference, found \"-\"",
+		"T ::= [1.5] INTEGER":                       "line 2, column 8: tag number must be an integer",
+		"T ::= CLASS { &id INTEGER }":               "line 2, column 7: information object classes are not supported",
+		"T ::= SEQUENCE OF Param { INTEGER }":       "line 2, column 25: parameterized types are not supported",
+		"T ::= SEQUENCE SIZE (1) INTEGER":           "line 2, column 25: expecting OF, found INTEGER",
+		"T ::= 5":                                   "line 2, column 7: expecting a type, found 5",
+	}
+	for body, message := range errors {
+		if parseError(t, "M DEFINITIONS ::= BEGIN\n"+body+"\nEND") != message {
+			t.Fatal("Wrong:", parseError(t, "M DEFINITIONS ::= BEGIN\n"+body+"\nEND"))
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package schema
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// parseValue parses any value, the value of a value assignment or of a DEFAULT for instance
+func (p *parser) parseValue() (Value, error) {
+	t := p.current()
+	switch t.kind {
+	case numberToken:
+		p.index++
+		return parseNumber(t, false)
+	case cstringToken:
+		p.index++
+		return &StringValue{Node: Node{t.pos}, Value: t.text}, nil
+	case bstringToken:
+		p.index++
+		return &BStringValue{Node: Node{t.pos}, Bits: t.text}, nil
+	case hstringToken:
+		p.index++
+		return &HStringValue{Node: Node{t.pos}, Digits: t.text}, nil
+	case wordToken:
+		return p.parseWordValue()
+	}
+	switch {
+	case isSymbol(t, "-"):
+		p.index++
+		number := p.current()
+		if number.kind != numberToken {
+			return nil, p.unexpected("a number")
+		}
+		p.index++
+		value, err := parseNumber(number, true)
+		if err != nil {
+			return nil, err
+		}
+		switch value := value.(type) {
+		case *IntegerValue:
+			value.Pos = t.pos
+		case *RealValue:
+			value.Pos = t.pos
+		}
+		return value, nil
+	case isSymbol(t, "{"):
+		return p.parseBraces()
+	}
+	return nil, p.unexpected("a value")
+}
+
+// parseTypedValue parses a value of type t (nil if not known), the type decides how a value in braces is parsed
+func (p *parser) parseTypedValue(t Type) (Value, error) {
+	if !isSymbol(p.current(), "{") {
+		return p.parseValue()
+	}
+	pos := p.current().pos
+	switch t := p.underlyingType(t).(type) {
+	case *SequenceType:
+		p.index++
+		return p.parseSequenceValue(pos, t)
+	case *SequenceOfType:
+		p.index++
+		return p.parseSequenceOfValue(pos, t.Element)
+	case *BitStringType:
+		p.index++
+		return p.parseSequenceOfValue(pos, nil)
+	case *BuiltinType:
+		switch t.Name {
+		case "OBJECT IDENTIFIER", "RELATIVE-OID":
+			return p.parseObjectIdentifierValue()
+		case "REAL", "EXTERNAL", "EMBEDDED PDV", "CHARACTER STRING":
+			p.index++
+			return p.parseSequenceValue(pos, nil)
+		}
+	}
+	return p.parseBraces()
+}
+
+// underlyingType returns t without its tags and constraints, references to the types of the module are replaced by the types they refer to
+func (p *parser) underlyingType(t Type) Type {
+	// a limit on the number of references followed guards against circular definitions
+	for references := 0; references <= len(p.types); {
+		switch u := t.(type) {
+		case *TaggedType:
+			t = u.Type
+		case *ConstrainedType:
+			t = u.Type
+		case *TypeReference:
+			if u.Module != "" || p.types[u.Name] == nil {
+				return t
+			}
+			t = p.types[u.Name]
+			references++
+		default:
+			return t
+		}
+	}
+	return nil
+}
+
+// componentType returns the type of the component of a SEQUENCE or SET type (nil if the type or the component is not known)
+func componentType(sequence *SequenceType, name string) Type {
+	if sequence == nil {
+		return nil
+	}
+	components := append([]*Component{}, sequence.Components...)
+	for _, addition := range sequence.Additions {
+		components = append(components, addition.Components...)
+	}
+	components = append(components, sequence.TrailingComponents...)
+	for _, component := range components {
+		if component.Name == name {
+			return component.Type
+		}
+	}
+	return nil
+}
+
+// parseNumber returns the value of a number (*IntegerValue) or of a realnumber (*RealValue)
+func parseNumber(t token, negative bool) (Value, error) {
+	text := t.text
+	if negative {
+		text = "-" + text
+	}
+	if strings.ContainsAny(text, ".eE") {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsInf(value, 0) {
+			return nil, errorAt(t.pos, "invalid realnumber "+text)
+		}
+		return &RealValue{Node: Node{t.pos}, Value: value}, nil
+	}
+	if text == "-0" {
+		return nil, errorAt(t.pos, "-0 is not a valid number")
+	}
+	value, _ := new(big.Int).SetString(text, 10)
+	return &IntegerValue{Node: Node{t.pos}, Value: value}, nil
+}
+
+// parseWordValue parses a keyword value, a value reference or a CHOICE value (alternative : value)
+func (p *parser) parseWordValue() (Value, error) {
+	t := p.current()
+	switch t.text {
+	case "TRUE", "FALSE":
+		p.index++
+		return &BooleanValue{Node: Node{t.pos}, Value: t.text == "TRUE"}, nil
+	case "NULL":
+		p.index++
+		return &NullValue{Node: Node{t.pos}}, nil
+	case "PLUS-INFINITY":
+		p.index++
+		return &RealValue{Node: Node{t.pos}, Value: math.Inf(1)}, nil
+	case "MINUS-INFINITY":
+		p.index++
+		return &RealValue{Node: Node{t.pos}, Value: math.Inf(-1)}, nil
+	case "NOT-A-NUMBER":
+		p.index++
+		return &RealValue{Node: Node{t.pos}, Value: math.NaN()}, nil
+	}
+
+	if isReference(t) && isSymbol(p.lookAhead(1), ".") && isIdentifier(p.lookAhead(2)) {
+		name := p.lookAhead(2)
+		p.index += 3
+		return &ValueReference{Node: Node{t.pos}, Module: t.text, Name: name.text}, nil
+	}
+	if !isIdentifier(t) {
+		return nil, p.unexpected("a value")
+	}
+	p.index++
+	if !p.acceptSymbol(":") {
+		return &ValueReference{Node: Node{t.pos}, Name: t.text}, nil
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return &ChoiceValue{Node: Node{t.pos}, Alternative: t.text, Value: value}, nil
+}
+
+// parseNumberOrReference parses a number (negative if signed is true) or a value reference
+func (p *parser) parseNumberOrReference(signed bool) (Value, error) {
+	t := p.current()
+	if isIdentifier(t) {
+		p.index++
+		return &ValueReference{Node: Node{t.pos}, Name: t.text}, nil
+	}
+	negative := signed && isSymbol(t, "-")
+	if negative {
+		p.index++
+	}
+	number := p.current()
+	if number.kind != numberToken || strings.ContainsAny(number.text, ".eE") {
+		if negative {
+			return nil, p.unexpected("a number")
+		}
+		return nil, p.unexpected("a number or a value reference")
+	}
+	p.index++
+	value, err := parseNumber(number, negative)
+	if err != nil {
+		return nil, err
+	}
+	value.(*IntegerValue).Pos = t.pos
+	return value, nil
+}
+
+// parseException parses the exception identification following !: a number or a value reference
+func (p *parser) parseException() (Value, error) {
+	if p.isTypeStart() {
+		return nil, errorAt(p.current().pos, "exception identification with a type is not supported")
+	}
+	return p.parseNumberOrReference(true)
+}
+
+// parseObjectIdentifierValue parses arcs in braces, raises an error if the braces hold something else
+func (p *parser) parseObjectIdentifierValue() (*ObjectIdentifierValue, error) {
+	value, failure := p.parseArcs()
+	if value == nil {
+		p.index = failure
+		return nil, p.unexpected("an arc or \"}\"")
+	}
+	return value, nil
+}
+
+// parseArcs parses the arcs of an OBJECT IDENTIFIER or RELATIVE-OID value in braces: numbers, identifiers, identifiers followed by a number or a value reference in parentheses and external value references
+// if the braces do not only hold arcs, returns nil and the index of the first token which is not an arc (without consuming anything)
+func (p *parser) parseArcs() (*ObjectIdentifierValue, int) {
+	start := p.index
+	value := &ObjectIdentifierValue{Node: Node{p.current().pos}}
+	p.index++
+	for !isSymbol(p.current(), "}") {
+		t := p.current()
+		arc := &OIDComponent{Node: Node{t.pos}}
+		switch {
+		case t.kind == numberToken && !strings.ContainsAny(t.text, ".eE"):
+			arc.Number, _ = parseNumber(t, false)
+			p.index++
+		case isIdentifier(t) && isSymbol(p.lookAhead(1), "(") && isSymbol(p.lookAhead(3), ")"):
+			arc.Name = t.text
+			number := p.lookAhead(2)
+			switch {
+			case number.kind == numberToken && !strings.ContainsAny(number.text, ".eE"):
+				arc.Number, _ = parseNumber(number, false)
+			case isIdentifier(number):
+				arc.Number = &ValueReference{Node: Node{number.pos}, Name: number.text}
+			default:
+				failure := p.index + 2
+				p.index = start
+				return nil, failure
+			}
+			p.index += 4
+		case isIdentifier(t) && !isSymbol(p.lookAhead(1), ":"):
+			arc.Name = t.text
+			p.index++
+		case isReference(t) && isSymbol(p.lookAhead(1), ".") && isIdentifier(p.lookAhead(2)):
+			arc.Number = &ValueReference{Node: Node{t.pos}, Module: t.text, Name: p.lookAhead(2).text}
+			p.index += 3
+		default:
+			failure := p.index
+			p.index = start
+			return nil, failure
+		}
+		value.Components = append(value.Components, arc)
+	}
+	p.index++
+	return value, 0
+}
+
+// parseBraces parses a value in braces: arcs, a SEQUENCE or SET value or a SEQUENCE OF or SET OF value
+// braces without commas holding at least 2 arcs (or an identifier followed by a number in parentheses) are arcs, e.g. { iso member-body(2) 840 }
+func (p *parser) parseBraces() (Value, error) {
+	pos := p.current().pos
+	start := p.index
+	if isSymbol(p.lookAhead(1), "}") {
+		p.index += 2
+		return &SequenceOfValue{Node: Node{pos}, Elements: []Value{}}, nil
+	}
+
+	arcs, _ := p.parseArcs()
+	if arcs != nil && (len(arcs.Components) >= 2 || len(arcs.Components) == 1 && arcs.Components[0].Name != "" && arcs.Components[0].Number != nil) {
+		return arcs, nil
+	}
+	p.index = start + 1
+
+	first, second := p.current(), p.lookAhead(1)
+	if isIdentifier(first) && !isSymbol(second, ",") && !isSymbol(second, "}") && !isSymbol(second, ":") {
+		return p.parseSequenceValue(pos, nil)
+	}
+	return p.parseSequenceOfValue(pos, nil)
+}
+
+// parseSequenceValue parses the components of a value of the SEQUENCE or SET type sequence (nil if not known) up to the closing brace
+func (p *parser) parseSequenceValue(pos Position, sequence *SequenceType) (Value, error) {
+	value := &SequenceValue{Node: Node{pos}, Components: []*NamedValue{}}
+	if p.acceptSymbol("}") {
+		return value, nil
+	}
+	for {
+		name, err := p.expectIdentifier("a component identifier")
+		if err != nil {
+			return nil, err
+		}
+		component, err := p.parseTypedValue(componentType(sequence, name.text))
+		if err != nil {
+			return nil, err
+		}
+		value.Components = append(value.Components, &NamedValue{Node: Node{name.pos}, Name: name.text, Value: component})
+		if p.acceptSymbol("}") {
+			return value, nil
+		}
+		if !isSymbol(p.current(), ",") {
+			return nil, p.unexpected("\",\" or \"}\"")
+		}
+		p.index++
+	}
+}
+
+// parseSequenceOfValue parses the elements of a SEQUENCE OF or SET OF value whose elements are of type elementType (nil if not known) up to the closing brace
+func (p *parser) parseSequenceOfValue(pos Position, elementType Type) (Value, error) {
+	value := &SequenceOfValue{Node: Node{pos}, Elements: []Value{}}
+	if p.acceptSymbol("}") {
+		return value, nil
+	}
+	for {
+		element, err := p.parseTypedValue(elementType)
+		if err != nil {
+			return nil, err
+		}
+		value.Elements = append(value.Elements, element)
+		if p.acceptSymbol("}") {
+			return value, nil
+		}
+		if !isSymbol(p.current(), ",") {
+			return nil, p.unexpected("\",\" or \"}\"")
+		}
+		p.index++
+	}
+}
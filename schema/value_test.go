@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"math"
+	"testing"
+)
+
+// parseValueOf returns the value of the first assignment of a module made of the text given between BEGIN and END
+func parseValueOf(t *testing.T, body string) Value {
+	module := parseBody(t, body)
+	return module.Assignments[0].(*ValueAssignment).Value
+}
+
+func TestParseSimpleValues(t *testing.T) {
+	if !parseValueOf(t, "v BOOLEAN ::= TRUE").(*BooleanValue).Value {
+		t.Fatal("Wrong")
+	}
+	if _, isNull := parseValueOf(t, "v NULL ::= NULL").(*NullValue); !isNull {
+		t.Fatal("Wrong")
+	}
+	integer := parseValueOf(t, "v INTEGER ::= -123456789012345678901234567890").(*IntegerValue)
+	if integer.Value.String() != "-123456789012345678901234567890" || integer.Pos != (Position{Line: 2, Column: 15}) {
+		t.Fatal("Wrong")
+	}
+	if parseValueOf(t, "v REAL ::= 1.5e2").(*RealValue).Value != 150 {
+		t.Fatal("Wrong")
+	}
+	if !math.IsInf(parseValueOf(t, "v REAL ::= MINUS-INFINITY").(*RealValue).Value, -1) {
+		t.Fatal("Wrong")
+	}
+	if parseValueOf(t, "v UTF8String ::= \"a\"\"b\"").(*StringValue).Value != "a\"b" {
+		t.Fatal("Wrong")
+	}
+	if parseValueOf(t, "v BIT STRING ::= '1010'B").(*BStringValue).Bits != "1010" {
+		t.Fatal("Wrong")
+	}
+	if parseValueOf(t, "v OCTET STRING ::= 'CAFE'H").(*HStringValue).Digits != "CAFE" {
+		t.Fatal("Wrong")
+	}
+	reference := parseValueOf(t, "v INTEGER ::= Other.max-value").(*ValueReference)
+	if reference.Module != "Other" || reference.Name != "max-value" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseBracedValues(t *testing.T) {
+	oid := parseValueOf(t, "v OBJECT IDENTIFIER ::= { id-pkix 1 x(y) }").(*ObjectIdentifierValue)
+	if len(oid.Components) != 3 || oid.Components[0].Name != "id-pkix" || oid.Components[0].Number != nil {
+		t.Fatal("Wrong")
+	}
+	if oid.Components[2].Name != "x" || oid.Components[2].Number.(*ValueReference).Name != "y" {
+		t.Fatal("Wrong")
+	}
+
+	sequence := parseValueOf(t, "v T ::= { a TRUE, b { 1, 2 }, c choice : NULL }").(*SequenceValue)
+	if len(sequence.Components) != 3 || sequence.Components[0].Name != "a" || sequence.Components[1].Pos != (Position{Line: 2, Column: 19}) {
+		t.Fatal("Wrong")
+	}
+	if len(sequence.Components[1].Value.(*SequenceOfValue).Elements) != 2 {
+		t.Fatal("Wrong")
+	}
+	choice := sequence.Components[2].Value.(*ChoiceValue)
+	if choice.Alternative != "choice" {
+		t.Fatal("Wrong")
+	}
+	if _, isNull := choice.Value.(*NullValue); !isNull {
+		t.Fatal("Wrong")
+	}
+
+	bits := parseValueOf(t, "v T ::= { a, b }").(*SequenceOfValue)
+	if len(bits.Elements) != 2 || bits.Elements[1].(*ValueReference).Name != "b" {
+		t.Fatal("Wrong")
+	}
+	single := parseValueOf(t, "v T ::= { 5 }").(*SequenceOfValue)
+	if len(single.Elements) != 1 {
+		t.Fatal("Wrong")
+	}
+	empty := parseValueOf(t, "v T ::= {}").(*SequenceOfValue)
+	if len(empty.Elements) != 0 {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseValueErrors(t *testing.T) {
+	errors := map[string]string{
+		"v INTEGER ::= -a":     "line 2, column 16: expecting a number, found a",
+		"v INTEGER ::= -0":     "line 2, column 16: -0 is not a valid number",
+		"v T ::= { a 1, b }":   "line 2, column 18: expecting a value, found \"}\"",
+		"v T ::= { 1, 2 3 }":   "line 2, column 16: expecting \",\" or \"}\", found 3",
+		"v T ::= MIN":          "line 2, column 9: expecting a value, found MIN",
+		"v T ::= { a 1, B 2 }": "line 2, column 16: expecting a component identifier, found B",
+		"v REAL ::= 1e999999":  "line 2, column 12: invalid realnumber 1e999999",
+	}
+	for body, message := range errors {
+		if parseError(t, "M DEFINITIONS ::= BEGIN\n"+body+"\nEND") != message {
+			t.Fatal("Wrong:", parseError(t, "M DEFINITIONS ::= BEGIN\n"+body+"\nEND"))
+		}
+	}
+}
+
+func TestParseTypedBracedValues(t *testing.T) {
+	module := parseBody(t, `v S ::= { a 1 }
+w SEQUENCE { version INTEGER } ::= { version 1 }
+o OBJECT IDENTIFIER ::= { a }
+e S ::= {}
+S ::= [0] SEQUENCE { a INTEGER, b T DEFAULT { version 1 }, ..., c SEQUENCE (SIZE (1)) OF T OPTIONAL }
+T ::= SET { version INTEGER }
+n S ::= { a 1, c { { version 2 } } }
+x U ::= { a 1 }`)
+
+	for i := 0; i < 2; i++ {
+		sequence, ok := module.Assignments[i].(*ValueAssignment).Value.(*SequenceValue)
+		if !ok || len(sequence.Components) != 1 || sequence.Components[0].Value.(*IntegerValue).Value.Int64() != 1 {
+			t.Fatal("Wrong:", i)
+		}
+	}
+	oid := module.Assignments[2].(*ValueAssignment).Value.(*ObjectIdentifierValue)
+	if len(oid.Components) != 1 || oid.Components[0].Name != "a" {
+		t.Fatal("Wrong")
+	}
+	if len(module.Assignments[3].(*ValueAssignment).Value.(*SequenceValue).Components) != 0 {
+		t.Fatal("Wrong")
+	}
+
+	components := module.Assignments[4].(*TypeAssignment).Type.(*TaggedType).Type.(*SequenceType).Components
+	if _, ok := components[1].Default.(*SequenceValue); !ok {
+		t.Fatal("Wrong")
+	}
+
+	nested := module.Assignments[6].(*ValueAssignment).Value.(*SequenceValue).Components[1].Value.(*SequenceOfValue)
+	if _, ok := nested.Elements[0].(*SequenceValue); !ok {
+		t.Fatal("Wrong")
+	}
+
+	// type not defined in the module
+	if _, ok := module.Assignments[7].(*ValueAssignment).Value.(*ObjectIdentifierValue); !ok {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseTypedValueCircular(t *testing.T) {
+	module := parseBody(t, "A ::= B\nB ::= A\nv A ::= { a 1 }")
+	if _, ok := module.Assignments[2].(*ValueAssignment).Value.(*ObjectIdentifierValue); !ok {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,493 @@
+package types
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time is the Go implementation of ASN.1 TIME, the value is an ISO 8601 string whose form is given by TimeProperties.
+type Time string
+
+// Date is the Go implementation of ASN.1 DATE (e.g. "2023-12-31").
+type Date string
+
+// TimeOfDay is the Go implementation of ASN.1 TIME-OF-DAY (e.g. "23:59:59").
+type TimeOfDay string
+
+// DateTime is the Go implementation of ASN.1 DATE-TIME (e.g. "2023-12-31T23:59:59").
+type DateTime string
+
+// Duration is the Go implementation of ASN.1 DURATION (e.g. "P1Y2M3DT4H5M6S" or "P2W").
+type Duration string
+
+// TimeProperties holds the property settings of a TIME type (X.680 38.4), an empty field means any value of the property
+type TimeProperties struct {
+	Basic        string // Date, Time, Date-Time, Interval or Rec-Interval
+	Date         string // C, Y, YM, YMD, YD, YW or YWD
+	Year         string // Basic, Proleptic, Negative or Ln (n > 4)
+	Time         string // H, HM, HMS, HFn, HMFn or HMSFn
+	LocalOrUTC   string // L, Z or LD
+	IntervalType string // SE, D, SD or DE
+	SEPoint      string // Date, Time or Date-Time
+	Recurrence   string // Unlimited or Rn
+	Midnight     string // Start or End
+}
+
+// property settings of the useful time types (X.680 38.4.1)
+var (
+	DateProperties      = TimeProperties{Basic: "Date", Date: "YMD", Year: "Basic"}
+	TimeOfDayProperties = TimeProperties{Basic: "Time", Time: "HMS", LocalOrUTC: "L"}
+	DateTimeProperties  = TimeProperties{Basic: "Date-Time", Date: "YMD", Year: "Basic", Time: "HMS", LocalOrUTC: "L"}
+	DurationProperties  = TimeProperties{Basic: "Interval", IntervalType: "D"}
+)
+
+var (
+	largeYearSetting  = regexp.MustCompile(`^L([5-9]|[1-9][0-9]+)$`)
+	timeSetting       = regexp.MustCompile(`^(H|HM|HMS)(F([1-9][0-9]*))?$`)
+	recurrenceSetting = regexp.MustCompile(`^R[1-9][0-9]*$`)
+
+	dateValue = regexp.MustCompile(`^([+-]?[0-9]+)(?:-([0-9]{2})(?:-([0-9]{2}))?|-([0-9]{3})|-W([0-9]{2})(?:-([0-9]))?)?$`)
+	timeValue = regexp.MustCompile(`^([0-9]{2})(?::([0-9]{2})(?::([0-9]{2}))?)?(?:[.,]([0-9]+))?$`)
+	zoneValue = regexp.MustCompile(`^(.*?)(Z|[+-][0-9]{2}(?::[0-9]{2})?)?$`)
+)
+
+// ParseTimeProperties parses property settings as they appear in a SETTINGS constraint (e.g. "Basic=Date Date=YMD Year=Basic")
+func ParseTimeProperties(settings string) (TimeProperties, error) {
+	var properties TimeProperties
+
+	for _, setting := range strings.Fields(settings) {
+		nameValue := strings.SplitN(setting, "=", 2)
+		if len(nameValue) != 2 {
+			return properties, errors.New("invalid property setting: " + setting)
+		}
+		name, value := nameValue[0], nameValue[1]
+
+		var field *string
+		var valid bool
+		switch name {
+		case "Basic":
+			field = &properties.Basic
+			valid = isOneOf(value, "Date", "Time", "Date-Time", "Interval", "Rec-Interval")
+		case "Date":
+			field = &properties.Date
+			valid = isOneOf(value, "C", "Y", "YM", "YMD", "YD", "YW", "YWD")
+		case "Year":
+			field = &properties.Year
+			valid = isOneOf(value, "Basic", "Proleptic", "Negative") || largeYearSetting.MatchString(value)
+		case "Time":
+			field = &properties.Time
+			valid = timeSetting.MatchString(value)
+		case "Local-or-UTC":
+			field = &properties.LocalOrUTC
+			valid = isOneOf(value, "L", "Z", "LD")
+		case "Interval-type":
+			field = &properties.IntervalType
+			valid = isOneOf(value, "SE", "D", "SD", "DE")
+		case "SE-point":
+			field = &properties.SEPoint
+			valid = isOneOf(value, "Date", "Time", "Date-Time")
+		case "Recurrence":
+			field = &properties.Recurrence
+			valid = value == "Unlimited" || recurrenceSetting.MatchString(value)
+		case "Midnight":
+			field = &properties.Midnight
+			valid = isOneOf(value, "Start", "End")
+		default:
+			return properties, errors.New("unknown property: " + name)
+		}
+
+		if !valid {
+			return properties, errors.New("invalid value for property " + name + ": " + value)
+		}
+		if *field != "" {
+			return properties, errors.New("property set more than once: " + name)
+		}
+		*field = value
+	}
+
+	return properties, nil
+}
+
+// NewDate creates a DATE from the date of t in its own location
+func NewDate(t time.Time) Date {
+	return Date(t.Format("2006-01-02"))
+}
+
+// NewTimeOfDay creates a TIME-OF-DAY from the clock of t in its own location
+func NewTimeOfDay(t time.Time) TimeOfDay {
+	return TimeOfDay(t.Format("15:04:05"))
+}
+
+// NewDateTime creates a DATE-TIME from the date and clock of t in its own location
+func NewDateTime(t time.Time) DateTime {
+	return DateTime(t.Format("2006-01-02T15:04:05"))
+}
+
+// Validate raises an error if the value does not match the property settings
+func (v Time) Validate(properties TimeProperties) error {
+	if !matchTime(string(v), properties) {
+		return errors.New("invalid TIME value: " + string(v))
+	}
+	return nil
+}
+
+// Validate raises an error if the value is not a valid DATE
+func (v Date) Validate() error {
+	if !matchTime(string(v), DateProperties) {
+		return errors.New("invalid DATE value: " + string(v))
+	}
+	return nil
+}
+
+// Validate raises an error if the value is not a valid TIME-OF-DAY
+func (v TimeOfDay) Validate() error {
+	if !matchTime(string(v), TimeOfDayProperties) {
+		return errors.New("invalid TIME-OF-DAY value: " + string(v))
+	}
+	return nil
+}
+
+// Validate raises an error if the value is not a valid DATE-TIME
+func (v DateTime) Validate() error {
+	if !matchTime(string(v), DateTimeProperties) {
+		return errors.New("invalid DATE-TIME value: " + string(v))
+	}
+	return nil
+}
+
+// Validate raises an error if the value is not a valid DURATION
+func (v Duration) Validate() error {
+	if !matchTime(string(v), DurationProperties) {
+		return errors.New("invalid DURATION value: " + string(v))
+	}
+	return nil
+}
+
+// matchTime returns true if s is a TIME value matching the property settings
+func matchTime(s string, p TimeProperties) bool {
+	switch p.Basic {
+	case "Date":
+		return matchDate(s, p)
+	case "Time":
+		return matchTimeOfDay(s, p)
+	case "Date-Time":
+		return matchDateTime(s, p)
+	case "Interval":
+		return matchInterval(s, p)
+	case "Rec-Interval":
+		return matchRecurringInterval(s, p)
+	}
+	return matchDate(s, p) || matchTimeOfDay(s, p) || matchDateTime(s, p) || matchInterval(s, p) || matchRecurringInterval(s, p)
+}
+
+// matchDateTime returns true if s is a date and a time separated by T
+func matchDateTime(s string, p TimeProperties) bool {
+	i := strings.IndexByte(s, 'T')
+	return i > 0 && matchDate(s[:i], p) && matchTimeOfDay(s[i+1:], p)
+}
+
+// matchDate returns true if s is a date matching the Date and Year settings
+func matchDate(s string, p TimeProperties) bool {
+	m := dateValue.FindStringSubmatch(s)
+	if m == nil {
+		return false
+	}
+
+	var forms []string
+	switch {
+	case m[3] != "":
+		forms = []string{"YMD"}
+	case m[2] != "":
+		forms = []string{"YM"}
+	case m[4] != "":
+		forms = []string{"YD"}
+	case m[6] != "":
+		forms = []string{"YWD"}
+	case m[5] != "":
+		forms = []string{"YW"}
+	default:
+		forms = []string{"Y", "C"}
+	}
+
+	for _, form := range forms {
+		if p.Date != "" && p.Date != form {
+			continue
+		}
+		year, ok := matchYear(m[1], p.Year, form == "C")
+		if ok && matchDateFields(year, form, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchYear returns the value of a year (or century) matching the Year setting
+func matchYear(s string, setting string, isCentury bool) (int, bool) {
+	nDigits := 4
+	minimum := 1582 // Gregorian calendar
+	if isCentury {
+		nDigits = 2
+		minimum = 15
+	}
+
+	sign := ""
+	digits := s
+	if s[0] == '+' || s[0] == '-' {
+		sign = s[:1]
+		digits = s[1:]
+	}
+	value, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false
+	}
+	if sign == "-" {
+		value = -value
+	}
+
+	var ok bool
+	switch {
+	case setting == "Basic":
+		ok = sign == "" && len(digits) == nDigits && value >= minimum
+	case setting == "Proleptic":
+		ok = sign == "" && len(digits) == nDigits
+	case setting == "Negative":
+		ok = sign != "+" && len(digits) == nDigits
+	case setting == "":
+		ok = sign == "" && len(digits) == nDigits || sign == "-" && len(digits) == nDigits || sign != "" && len(digits) > nDigits
+	default: // Ln
+		n, _ := strconv.Atoi(setting[1:])
+		ok = sign != "" && len(digits) == n-4+nDigits
+	}
+	return value, ok
+}
+
+// matchDateFields checks the month, day and week fields of a date
+func matchDateFields(year int, form string, m []string) bool {
+	switch form {
+	case "YM":
+		month, _ := strconv.Atoi(m[2])
+		return month >= 1 && month <= 12
+	case "YMD":
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		return month >= 1 && month <= 12 && day >= 1 && day <= daysIn(year, time.Month(month))
+	case "YD":
+		day, _ := strconv.Atoi(m[4])
+		return day >= 1 && day <= time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	case "YW", "YWD":
+		week, _ := strconv.Atoi(m[5])
+		_, nWeeks := time.Date(year, 12, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+		if week < 1 || week > nWeeks {
+			return false
+		}
+		if form == "YWD" {
+			day, _ := strconv.Atoi(m[6])
+			return day >= 1 && day <= 7
+		}
+	}
+	return true
+}
+
+// matchTimeOfDay returns true if s is a time of day matching the Time, Local-or-UTC and Midnight settings
+func matchTimeOfDay(s string, p TimeProperties) bool {
+	z := zoneValue.FindStringSubmatch(s)
+	clock, zone := z[1], z[2]
+
+	switch {
+	case zone == "" && p.LocalOrUTC != "" && p.LocalOrUTC != "L":
+		return false
+	case zone == "Z" && p.LocalOrUTC != "" && p.LocalOrUTC != "Z":
+		return false
+	case len(zone) > 1:
+		if p.LocalOrUTC != "" && p.LocalOrUTC != "LD" {
+			return false
+		}
+		hours, _ := strconv.Atoi(zone[1:3])
+		minutes := 0
+		if len(zone) > 3 {
+			minutes, _ = strconv.Atoi(zone[4:])
+		}
+		if hours > 15 || minutes > 59 {
+			return false
+		}
+	}
+
+	m := timeValue.FindStringSubmatch(clock)
+	if m == nil {
+		return false
+	}
+
+	form := "H"
+	switch {
+	case m[3] != "":
+		form = "HMS"
+	case m[2] != "":
+		form = "HM"
+	}
+	if m[4] != "" {
+		form += "F" + strconv.Itoa(len(m[4]))
+	}
+	if p.Time != "" && form != p.Time {
+		return false
+	}
+
+	hour, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi("0" + m[2])
+	second, _ := strconv.Atoi("0" + m[3])
+	if hour == 24 && p.Midnight != "Start" {
+		// midnight at the end of the day
+		return minute == 0 && second == 0 && strings.Trim(m[4], "0") == ""
+	}
+	return hour <= 23 && minute <= 59 && second <= 59
+}
+
+// matchInterval returns true if s is an interval matching the Interval-type and SE-point settings
+func matchInterval(s string, p TimeProperties) bool {
+	parts := strings.Split(s, "/")
+
+	if len(parts) == 1 {
+		return (p.IntervalType == "" || p.IntervalType == "D") && matchDuration(s)
+	}
+	if len(parts) != 2 {
+		return false
+	}
+
+	switch p.IntervalType {
+	case "SE":
+		return matchPoint(parts[0], p) && matchPoint(parts[1], p)
+	case "SD":
+		return matchPoint(parts[0], p) && matchDuration(parts[1])
+	case "DE":
+		return matchDuration(parts[0]) && matchPoint(parts[1], p)
+	case "":
+		return (matchPoint(parts[0], p) || matchDuration(parts[0])) &&
+			(matchPoint(parts[1], p) || matchDuration(parts[1])) &&
+			!(matchDuration(parts[0]) && matchDuration(parts[1]))
+	}
+	return false
+}
+
+// matchRecurringInterval returns true if s is a recurring interval (Rn/interval) matching the Recurrence setting
+func matchRecurringInterval(s string, p TimeProperties) bool {
+	i := strings.IndexByte(s, '/')
+	if i < 1 || s[0] != 'R' {
+		return false
+	}
+
+	recurrence := s[1:i]
+	for _, c := range recurrence {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	switch p.Recurrence {
+	case "":
+	case "Unlimited":
+		if recurrence != "" {
+			return false
+		}
+	default: // Rn, n digits
+		nDigits, _ := strconv.Atoi(p.Recurrence[1:])
+		if len(recurrence) != nDigits {
+			return false
+		}
+	}
+
+	return matchInterval(s[i+1:], p)
+}
+
+// matchPoint returns true if s is a start or end point of an interval matching the SE-point setting
+func matchPoint(s string, p TimeProperties) bool {
+	switch p.SEPoint {
+	case "Date":
+		return matchDate(s, p)
+	case "Time":
+		return matchTimeOfDay(s, p)
+	case "Date-Time":
+		return matchDateTime(s, p)
+	}
+	return matchDate(s, p) || matchTimeOfDay(s, p) || matchDateTime(s, p)
+}
+
+// matchDuration returns true if s is a duration (PnW or PnYnMnDTnHnMnS, only the last component may have a fraction)
+func matchDuration(s string) bool {
+	if len(s) < 3 || s[0] != 'P' {
+		return false
+	}
+	s = s[1:]
+
+	units := "YMD"
+	isTime := false
+	nComponents := 0
+	nTimeComponents := 0
+	hasFraction := false
+
+	for s != "" {
+		if s[0] == 'T' {
+			if isTime {
+				return false
+			}
+			isTime = true
+			units = "HMS"
+			s = s[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 || hasFraction {
+			return false
+		}
+		if i < len(s) && (s[i] == '.' || s[i] == ',') {
+			i++
+			j := i
+			for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+				i++
+			}
+			if i == j {
+				return false
+			}
+			hasFraction = true
+		}
+		if i == len(s) {
+			return false
+		}
+
+		if s[i] == 'W' && !isTime {
+			// weeks cannot be combined with other components
+			return nComponents == 0 && i+1 == len(s)
+		}
+		position := strings.IndexByte(units, s[i])
+		if position < 0 {
+			return false
+		}
+		units = units[position+1:]
+		nComponents++
+		if isTime {
+			nTimeComponents++
+		}
+		s = s[i+1:]
+	}
+
+	return nComponents > 0 && (!isTime || nTimeComponents > 0)
+}
+
+// daysIn returns the number of days of a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// isOneOf returns true if value is one of the candidates
+func isOneOf(value string, candidates ...string) bool {
+	for _, candidate := range candidates {
+		if value == candidate {
+			return true
+		}
+	}
+	return false
+}
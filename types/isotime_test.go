@@ -0,0 +1,150 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeProperties(t *testing.T) {
+	properties, err := ParseTimeProperties("Basic=Date-Time Date=YMD Year=L5 Time=HMSF3 Local-or-UTC=LD")
+
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expected := TimeProperties{Basic: "Date-Time", Date: "YMD", Year: "L5", Time: "HMSF3", LocalOrUTC: "LD"}
+	if properties != expected {
+		t.Fatal("Wrong:", properties)
+	}
+
+	invalid := []string{"Basic", "Basic=Day", "Year=L4", "Time=HF0", "Basic=Date Basic=Time", "Color=Red"}
+
+	for _, settings := range invalid {
+		_, err = ParseTimeProperties(settings)
+		if err == nil {
+			t.Fatal("Wrong:", settings)
+		}
+	}
+}
+
+func TestDate(t *testing.T) {
+	valid := []Date{"2023-12-31", "2024-02-29", "1582-01-01"}
+	invalid := []Date{"2023-02-29", "2023-13-01", "23-12-31", "2023-12", "1581-12-31", "2023-12-31Z", "+2023-12-31"}
+
+	for _, value := range valid {
+		if value.Validate() != nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+	for _, value := range invalid {
+		if value.Validate() == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+
+	if NewDate(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)) != "2023-01-02" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	valid := []TimeOfDay{"00:00:00", "23:59:59", "24:00:00"}
+	invalid := []TimeOfDay{"23:59", "24:00:01", "23:60:00", "23:59:59Z", "23:59:59.5", "235959"}
+
+	for _, value := range valid {
+		if value.Validate() != nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+	for _, value := range invalid {
+		if value.Validate() == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+
+	if NewTimeOfDay(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)) != "03:04:05" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	valid := []DateTime{"2023-12-31T23:59:59"}
+	invalid := []DateTime{"2023-12-31 23:59:59", "2023-12-31T23:59", "2023-12-31", "T23:59:59"}
+
+	for _, value := range valid {
+		if value.Validate() != nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+	for _, value := range invalid {
+		if value.Validate() == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+
+	if NewDateTime(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)) != "2023-01-02T03:04:05" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestDuration(t *testing.T) {
+	valid := []Duration{"P1Y", "P2W", "P1Y2M3DT4H5M6S", "PT0,5S", "P1DT12H", "PT36H", "P0.5Y"}
+	invalid := []Duration{"P", "PT", "P1S", "P1M1Y", "P1W2D", "P1.5DT1H", "P1DT", "1Y", "P1Y2"}
+
+	for _, value := range valid {
+		if value.Validate() != nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+	for _, value := range invalid {
+		if value.Validate() == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestTimeSettings(t *testing.T) {
+	tests := []struct {
+		settings string
+		value    Time
+		valid    bool
+	}{
+		{"Basic=Date Date=C Year=Basic", "20", true},
+		{"Basic=Date Date=Y Year=Proleptic", "0100", true},
+		{"Basic=Date Date=Y Year=Basic", "0100", false},
+		{"Basic=Date Date=Y Year=Negative", "-0100", true},
+		{"Basic=Date Date=YMD Year=L5", "+12023-12-31", true},
+		{"Basic=Date Date=YMD Year=L5", "2023-12-31", false},
+		{"Basic=Date Date=YD Year=Basic", "2024-366", true},
+		{"Basic=Date Date=YD Year=Basic", "2023-366", false},
+		{"Basic=Date Date=YWD Year=Basic", "2023-W52-7", true},
+		{"Basic=Date Date=YWD Year=Basic", "2023-W53-1", false},
+		{"Basic=Time Time=HM Local-or-UTC=Z", "12:30Z", true},
+		{"Basic=Time Time=HM Local-or-UTC=Z", "12:30", false},
+		{"Basic=Time Time=HMSF2 Local-or-UTC=LD", "12:30:00.25+01:00", true},
+		{"Basic=Time Time=HMSF2 Local-or-UTC=LD", "12:30:00.25", false},
+		{"Basic=Time Time=HF1 Local-or-UTC=L", "12,5", true},
+		{"Basic=Time Time=HMS Local-or-UTC=L Midnight=Start", "24:00:00", false},
+		{"Basic=Interval Interval-type=SE SE-point=Date Date=YMD Year=Basic", "2023-01-01/2023-12-31", true},
+		{"Basic=Interval Interval-type=SD SE-point=Date Date=YMD Year=Basic", "2023-01-01/P1D", true},
+		{"Basic=Interval Interval-type=DE SE-point=Date Date=YMD Year=Basic", "2023-01-01/P1D", false},
+		{"Basic=Rec-Interval Recurrence=Unlimited Interval-type=D", "R/P1D", true},
+		{"Basic=Rec-Interval Recurrence=R2 Interval-type=D", "R12/P1D", true},
+		{"Basic=Rec-Interval Recurrence=R2 Interval-type=D", "R1/P1D", false},
+		{"", "2023-12-31T23:59:59Z", true},
+		{"", "P1D/2023-12-31", true},
+		{"", "P1D/P2D", false},
+		{"", "not a time", false},
+	}
+
+	for _, test := range tests {
+		properties, err := ParseTimeProperties(test.settings)
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		err = test.value.Validate(properties)
+		if (err == nil) != test.valid {
+			t.Fatal("Wrong:", test.settings, test.value)
+		}
+	}
+}
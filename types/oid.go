@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// String returns the dotted notation of the OBJECT IDENTIFIER, e.g. 1.2.840.113549
+func (v ObjectIdentifier) String() string {
+	return formatArcs(v)
+}
+
+// String returns the dotted notation of the RELATIVE-OID, e.g. 8571.3
+func (v RelativeOID) String() string {
+	return formatArcs(v)
+}
+
+// ParseObjectIdentifier decodes an OBJECT IDENTIFIER in dotted notation
+// raises an error if there are less than 2 arcs or if the first arcs are out of range (X.660)
+func ParseObjectIdentifier(text string) (ObjectIdentifier, error) {
+	arcs, err := parseArcs(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(arcs) < 2 || arcs[0] > 2 || arcs[0] < 2 && arcs[1] > 39 {
+		return nil, errors.New("invalid OBJECT IDENTIFIER " + strconv.Quote(text))
+	}
+	return ObjectIdentifier(arcs), nil
+}
+
+// ParseRelativeOID decodes a RELATIVE-OID in dotted notation
+func ParseRelativeOID(text string) (RelativeOID, error) {
+	arcs, err := parseArcs(text)
+	if err != nil {
+		return nil, err
+	}
+	return RelativeOID(arcs), nil
+}
+
+// formatArcs returns the dotted notation of arcs
+func formatArcs(arcs []int64) string {
+	parts := make([]string, len(arcs))
+	for i, arc := range arcs {
+		parts[i] = strconv.FormatInt(arc, 10)
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseArcs returns the arcs of a dotted notation, raises an error if an arc is not a decimal number without sign nor leading zero
+func parseArcs(text string) ([]int64, error) {
+	parts := strings.Split(text, ".")
+	arcs := make([]int64, len(parts))
+	for i, part := range parts {
+		if part == "" || part[0] == '+' || part[0] == '-' || len(part) > 1 && part[0] == '0' {
+			return nil, errors.New("invalid arc in " + strconv.Quote(text))
+		}
+		arc, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid arc in " + strconv.Quote(text))
+		}
+		arcs[i] = arc
+	}
+	return arcs, nil
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestObjectIdentifierString(t *testing.T) {
+	if (ObjectIdentifier{1, 2, 840, 113549}).String() != "1.2.840.113549" {
+		t.Fatal("Wrong")
+	}
+	if (RelativeOID{8571, 3}).String() != "8571.3" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestParseObjectIdentifier(t *testing.T) {
+	value, err := ParseObjectIdentifier("2.999.9223372036854775807")
+	if err != nil || value.String() != "2.999.9223372036854775807" {
+		t.Fatal("Wrong:", err)
+	}
+
+	relative, err := ParseRelativeOID("0")
+	if err != nil || len(relative) != 1 || relative[0] != 0 {
+		t.Fatal("Wrong:", err)
+	}
+
+	invalids := []string{"", "1", "3.1", "1.40", "1..2", "1.02", "1.+2", "1.-2", "1.2.", "1.9223372036854775808", "1.a"}
+	for _, invalid := range invalids {
+		_, err := ParseObjectIdentifier(invalid)
+		if err == nil {
+			t.Fatal("Wrong:", invalid)
+		}
+	}
+
+	_, err = ParseRelativeOID("1.02")
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,71 @@
+package types
+
+import "strconv"
+
+// TagClass is the class of an ASN.1 tag
+type TagClass int
+
+// classes of tags, in canonical order (X.680 8.6)
+const (
+	UniversalClass   TagClass = 0
+	ApplicationClass TagClass = 1
+	ContextClass     TagClass = 2
+	PrivateClass     TagClass = 3
+)
+
+// String returns the name of the class: UNIVERSAL, APPLICATION, CONTEXT or PRIVATE
+func (c TagClass) String() string {
+	switch c {
+	case UniversalClass:
+		return "UNIVERSAL"
+	case ApplicationClass:
+		return "APPLICATION"
+	case ContextClass:
+		return "CONTEXT"
+	case PrivateClass:
+		return "PRIVATE"
+	}
+	return "CLASS " + strconv.Itoa(int(c))
+}
+
+// Tag is an ASN.1 tag with the form (primitive or constructed) of the encoding it identifies
+type Tag struct {
+	Class       TagClass
+	Constructed bool
+	Number      uint64
+}
+
+// String returns the tag in ASN.1 notation, e.g. [APPLICATION 5] or [0] for a context-specific tag (the form is not shown)
+func (t Tag) String() string {
+	number := strconv.FormatUint(t.Number, 10)
+	if t.Class == ContextClass {
+		return "[" + number + "]"
+	}
+	return "[" + t.Class.String() + " " + number + "]"
+}
+
+// Equal returns true if both tags have the same class, form and number
+func (t Tag) Equal(other Tag) bool {
+	return t == other
+}
+
+// Matches returns true if both tags have the same class and number, whatever their form
+// (a string type may be encoded primitive or constructed)
+func (t Tag) Matches(other Tag) bool {
+	return t.Class == other.Class && t.Number == other.Number
+}
+
+// Compare returns -1, 0 or 1 whether t comes before, with or after other in canonical order: by class (UNIVERSAL first), then by number
+func (t Tag) Compare(other Tag) int {
+	switch {
+	case t.Class < other.Class:
+		return -1
+	case t.Class > other.Class:
+		return 1
+	case t.Number < other.Number:
+		return -1
+	case t.Number > other.Number:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,32 @@
+package types
+
+import "testing"
+
+func TestTagString(t *testing.T) {
+	if (Tag{Class: ApplicationClass, Number: 5}).String() != "[APPLICATION 5]" {
+		t.Fatal("Wrong")
+	}
+	if (Tag{Class: UniversalClass, Constructed: true, Number: 16}).String() != "[UNIVERSAL 16]" {
+		t.Fatal("Wrong")
+	}
+	if (Tag{Class: ContextClass, Number: 0}).String() != "[0]" {
+		t.Fatal("Wrong")
+	}
+	if (Tag{Class: PrivateClass, Number: 1000}).String() != "[PRIVATE 1000]" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestTagCompare(t *testing.T) {
+	primitive := Tag{Class: ContextClass, Number: 4}
+	constructed := Tag{Class: ContextClass, Constructed: true, Number: 4}
+	if primitive.Equal(constructed) || !primitive.Matches(constructed) || primitive.Compare(constructed) != 0 {
+		t.Fatal("Wrong")
+	}
+	if (Tag{Class: UniversalClass, Number: 30}).Compare(Tag{Class: ApplicationClass, Number: 1}) != -1 {
+		t.Fatal("Wrong")
+	}
+	if (Tag{Class: PrivateClass, Number: 2}).Compare(Tag{Class: PrivateClass, Number: 1}) != 1 {
+		t.Fatal("Wrong")
+	}
+}
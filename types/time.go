@@ -0,0 +1,179 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+// UTCTime is the Go implementation of ASN.1 UTCTime, the value is kept in its string form (e.g. "9912312359+0100").
+type UTCTime string
+
+// GeneralizedTime is the Go implementation of ASN.1 GeneralizedTime, the value is kept in its string form (e.g. "20231231235959.5Z").
+type GeneralizedTime string
+
+// NewUTCTime creates a UTCTime in DER form (YYMMDDHHMMSSZ), t must be between years 1950 and 2049
+func NewUTCTime(t time.Time) UTCTime {
+	return UTCTime(t.UTC().Format("060102150405Z"))
+}
+
+// NewGeneralizedTime creates a GeneralizedTime in DER form (YYYYMMDDHHMMSS[.f]Z, no trailing zeros in fraction)
+func NewGeneralizedTime(t time.Time) GeneralizedTime {
+	return GeneralizedTime(t.UTC().Format("20060102150405.999999999Z"))
+}
+
+// Time converts a UTCTime to a time.Time, raises an error if the value is not a valid UTCTime
+// two digits years from 50 to 99 are in the 20th century, years from 00 to 49 are in the 21st century
+func (v UTCTime) Time() (time.Time, error) {
+	s := string(v)
+
+	year, ok := parseDigits(s, 0, 2)
+	if !ok {
+		return time.Time{}, errors.New("invalid UTCTime year")
+	}
+	if year < 50 {
+		year += 2000
+	} else {
+		year += 1900
+	}
+
+	fields, rest, ok := parseTimeFields(s[2:], 4, 5)
+	if !ok {
+		return time.Time{}, errors.New("invalid UTCTime value")
+	}
+	if rest == "" {
+		return time.Time{}, errors.New("UTCTime must have a time zone")
+	}
+
+	location, ok := parseTimeZone(rest)
+	if !ok || len(rest) == 3 {
+		return time.Time{}, errors.New("invalid UTCTime time zone")
+	}
+
+	return makeTime(year, fields, 0, location)
+}
+
+// IsDER returns true if the UTCTime is valid and in the form required by DER (X.690 11.8)
+func (v UTCTime) IsDER() bool {
+	t, err := v.Time()
+	return err == nil && v == NewUTCTime(t)
+}
+
+// Time converts a GeneralizedTime to a time.Time, raises an error if the value is not a valid GeneralizedTime
+// local times (no time zone) are interpreted in time.Local
+func (v GeneralizedTime) Time() (time.Time, error) {
+	s := string(v)
+
+	year, ok := parseDigits(s, 0, 4)
+	if !ok {
+		return time.Time{}, errors.New("invalid GeneralizedTime year")
+	}
+
+	fields, rest, ok := parseTimeFields(s[4:], 3, 5)
+	if !ok {
+		return time.Time{}, errors.New("invalid GeneralizedTime value")
+	}
+
+	// fraction applies to the last time field (hour, minute or second)
+	var fraction time.Duration
+	if len(rest) > 0 && (rest[0] == '.' || rest[0] == ',') {
+		unit := [...]time.Duration{time.Hour, time.Minute, time.Second}[len(fields)-3]
+		nDigits := 0
+		for nDigits+1 < len(rest) && rest[nDigits+1] >= '0' && rest[nDigits+1] <= '9' {
+			nDigits++
+		}
+		if nDigits == 0 {
+			return time.Time{}, errors.New("invalid GeneralizedTime fraction")
+		}
+		numerator, _ := new(big.Int).SetString(rest[1:nDigits+1], 10)
+		denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(nDigits)), nil)
+		numerator.Mul(numerator, big.NewInt(int64(unit)))
+		fraction = time.Duration(numerator.Div(numerator, denominator).Int64())
+		rest = rest[nDigits+1:]
+	}
+
+	location := time.Local
+	if rest != "" {
+		location, ok = parseTimeZone(rest)
+		if !ok {
+			return time.Time{}, errors.New("invalid GeneralizedTime time zone")
+		}
+	}
+
+	return makeTime(year, fields, fraction, location)
+}
+
+// IsDER returns true if the GeneralizedTime is valid and in the form required by DER (X.690 11.7)
+func (v GeneralizedTime) IsDER() bool {
+	t, err := v.Time()
+	return err == nil && v == NewGeneralizedTime(t)
+}
+
+// parseTimeFields parses 2 digits fields (month, day, hour, ...), between minFields and maxFields must be present
+func parseTimeFields(s string, minFields int, maxFields int) ([]int, string, bool) {
+	var fields []int
+	for len(fields) < maxFields {
+		value, ok := parseDigits(s, 0, 2)
+		if !ok {
+			break
+		}
+		fields = append(fields, value)
+		s = s[2:]
+	}
+	return fields, s, len(fields) >= minFields
+}
+
+// parseTimeZone parses Z, ±HH or ±HHMM
+func parseTimeZone(s string) (*time.Location, bool) {
+	if s == "Z" {
+		return time.UTC, true
+	}
+	if len(s) != 3 && len(s) != 5 || s[0] != '+' && s[0] != '-' {
+		return nil, false
+	}
+	hours, ok := parseDigits(s, 1, 2)
+	if !ok || hours > 23 {
+		return nil, false
+	}
+	minutes := 0
+	if len(s) == 5 {
+		minutes, ok = parseDigits(s, 3, 2)
+		if !ok || minutes > 59 {
+			return nil, false
+		}
+	}
+	offset := hours*3600 + minutes*60
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return time.FixedZone("", offset), true
+}
+
+// parseDigits parses nDigits decimal digits at offset
+func parseDigits(s string, offset int, nDigits int) (int, bool) {
+	if len(s) < offset+nDigits {
+		return 0, false
+	}
+	value := 0
+	for i := offset; i < offset+nDigits; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		value = value*10 + int(s[i]-'0')
+	}
+	return value, true
+}
+
+// makeTime creates a time.Time from month, day, hour [, minute [, second]] fields and checks their ranges
+func makeTime(year int, fields []int, fraction time.Duration, location *time.Location) (time.Time, error) {
+	values := [5]int{}
+	copy(values[:], fields)
+	month, day, hour, minute, second := values[0], values[1], values[2], values[3], values[4]
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, location)
+	if month < 1 || month > 12 || t.Day() != day || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, errors.New("time field out of range")
+	}
+
+	return t.Add(fraction), nil
+}
@@ -0,0 +1,117 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUTCTime(t *testing.T) {
+	value := UTCTime("4912312359Z")
+
+	converted, err := value.Time()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expected := time.Date(2049, 12, 31, 23, 59, 0, 0, time.UTC)
+	if !converted.Equal(expected) {
+		t.Fatal("Wrong:", converted)
+	}
+
+	if value.IsDER() {
+		t.Fatal("Wrong")
+	}
+
+	if NewUTCTime(converted) != "491231235900Z" {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestUTCTimeOffset(t *testing.T) {
+	value := UTCTime("500101000030-0230")
+
+	converted, err := value.Time()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	expected := time.Date(1950, 1, 1, 2, 30, 30, 0, time.UTC)
+	if !converted.Equal(expected) {
+		t.Fatal("Wrong:", converted)
+	}
+}
+
+func TestUTCTimeInvalid(t *testing.T) {
+	invalid := []UTCTime{"", "99123123", "9912312359", "991231235Z", "9902302359Z", "9912312460Z", "9912312359+01", "9912312359+2400", "991231235959.5Z"}
+
+	for _, value := range invalid {
+		_, err := value.Time()
+		if err == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestGeneralizedTime(t *testing.T) {
+	values := []GeneralizedTime{"2023123123Z", "202312312330Z", "2023123123.5Z", "202312312359,5Z", "20231231235959.123+0100", "20231231235959.123456789012Z"}
+	expected := []time.Time{
+		time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC),
+		time.Date(2023, 12, 31, 23, 59, 30, 0, time.UTC),
+		time.Date(2023, 12, 31, 22, 59, 59, 123000000, time.UTC),
+		time.Date(2023, 12, 31, 23, 59, 59, 123456789, time.UTC),
+	}
+
+	for i, value := range values {
+		converted, err := value.Time()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if !converted.Equal(expected[i]) {
+			t.Fatal("Wrong:", value, converted)
+		}
+	}
+}
+
+func TestGeneralizedTimeLocal(t *testing.T) {
+	value := GeneralizedTime("20230615120000")
+
+	converted, err := value.Time()
+	if err != nil {
+		t.Fatal("Wrong:", err)
+	}
+
+	if converted.Location() != time.Local || converted.Hour() != 12 {
+		t.Fatal("Wrong:", converted)
+	}
+}
+
+func TestGeneralizedTimeInvalid(t *testing.T) {
+	invalid := []GeneralizedTime{"", "20231231", "20231232235959Z", "20231231235959.Z", "20231231235959ZZ", "2023123123595Z"}
+
+	for _, value := range invalid {
+		_, err := value.Time()
+		if err == nil {
+			t.Fatal("Wrong:", value)
+		}
+	}
+}
+
+func TestGeneralizedTimeDER(t *testing.T) {
+	value := NewGeneralizedTime(time.Date(2023, 12, 31, 23, 59, 59, 500000000, time.FixedZone("", 3600)))
+
+	if value != "20231231225959.5Z" {
+		t.Fatal("Wrong:", value)
+	}
+
+	if !value.IsDER() {
+		t.Fatal("Wrong")
+	}
+
+	value = NewGeneralizedTime(time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC))
+
+	if value != "20231231235959Z" {
+		t.Fatal("Wrong:", value)
+	}
+}
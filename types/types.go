@@ -12,9 +12,21 @@ type BitString struct {
 	Length int    // length of the BIT STRING in bits.
 }
 
-// Get retrieves the bool value of a single bit
+// ValidLength returns the length of the BIT STRING in bits, limited to the bits held by Bytes (0 if Length is negative)
+func (b *BitString) ValidLength() int {
+	length := b.Length
+	if length > 8*len(b.Bytes) {
+		length = 8 * len(b.Bytes)
+	}
+	if length < 0 {
+		length = 0
+	}
+	return length
+}
+
+// Get retrieves the bool value of a single bit, false if the bit is not held by Bytes
 func (b *BitString) Get(i int) bool {
-	if i < 0 || i >= b.Length {
+	if i < 0 || i >= b.ValidLength() {
 		return false
 	}
 	x := i / 8
@@ -62,3 +62,16 @@ func TestBitString3(t *testing.T) {
 		t.Fatal("Wrong")
 	}
 }
+
+func TestBitStringValidLength(t *testing.T) {
+	value := BitString{Bytes: []byte{0xff}, Length: 9}
+
+	if value.ValidLength() != 8 || value.Get(8) != false || value.Get(7) != true {
+		t.Fatal("Wrong")
+	}
+
+	value.Length = -1
+	if value.ValidLength() != 0 || value.Get(0) != false {
+		t.Fatal("Wrong")
+	}
+}
@@ -0,0 +1,411 @@
+package xer
+
+import (
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Reader helps decode ASN.1 values encoded in XER
+// the canonical reader rejects white space between elements, comments and non-canonical values (CXER)
+type Reader struct {
+	// XML tokens read from the stream
+	decoder *xml.Decoder
+
+	// CANONICAL-XER
+	canonical bool
+
+	// token read in advance
+	peeked xml.Token
+}
+
+// NewReader creates a reader for basic XER
+func NewReader(in io.Reader) *Reader {
+	r := new(Reader)
+	r.decoder = xml.NewDecoder(in)
+	return r
+}
+
+// NewCanonicalReader creates a reader rejecting anything that is not canonical XER
+func NewCanonicalReader(in io.Reader) *Reader {
+	r := NewReader(in)
+	r.canonical = true
+	return r
+}
+
+// IsCanonical returns true if the reader rejects encodings which are not canonical XER
+func (r *Reader) IsCanonical() bool {
+	return r.canonical
+}
+
+// readToken reads the next XML token, skipping the XML declaration (and comments in basic XER)
+func (r *Reader) readToken() (xml.Token, error) {
+	if r.peeked != nil {
+		token := r.peeked
+		r.peeked = nil
+		return token, nil
+	}
+	for {
+		token, err := r.decoder.Token()
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch token.(type) {
+		case xml.ProcInst, xml.Directive:
+			continue
+		case xml.Comment:
+			if r.canonical {
+				return nil, errors.New("comments are not canonical")
+			}
+			continue
+		}
+		return xml.CopyToken(token), nil
+	}
+}
+
+// readMarkup reads the next start or end element, skipping the white space before it (rejected by the canonical reader)
+func (r *Reader) readMarkup() (xml.Token, error) {
+	for {
+		token, err := r.readToken()
+		if err != nil {
+			return nil, err
+		}
+		text, isText := token.(xml.CharData)
+		if !isText {
+			return token, nil
+		}
+		if strings.TrimSpace(string(text)) != "" {
+			return nil, errors.New("unexpected text " + strconv.Quote(string(text)))
+		}
+		if r.canonical {
+			return nil, errors.New("white space between elements is not canonical")
+		}
+	}
+}
+
+// BeginElement reads the start tag of an element and returns its name
+func (r *Reader) BeginElement() (string, error) {
+	token, err := r.readMarkup()
+	if err != nil {
+		return "", err
+	}
+	start, ok := token.(xml.StartElement)
+	if !ok {
+		return "", errors.New("expecting a start tag")
+	}
+	if len(start.Attr) > 0 {
+		return "", errors.New("unexpected attributes in element " + start.Name.Local)
+	}
+	return start.Name.Local, nil
+}
+
+// NextElement reads the start tag of the next child element and returns its name, returns false (reading the end tag of the parent) if there is none
+func (r *Reader) NextElement() (string, bool, error) {
+	token, err := r.readMarkup()
+	if err != nil {
+		return "", false, err
+	}
+	if _, isEnd := token.(xml.EndElement); isEnd {
+		return "", false, nil
+	}
+	r.peeked = token
+	name, err := r.BeginElement()
+	return name, err == nil, err
+}
+
+// EndElement reads the end tag of the innermost element
+func (r *Reader) EndElement() error {
+	token, err := r.readMarkup()
+	if err != nil {
+		return err
+	}
+	if _, isEnd := token.(xml.EndElement); !isEnd {
+		return errors.New("expecting an end tag")
+	}
+	return nil
+}
+
+// SkipElement skips the content and the end tag of the innermost element, for instance the element of an unknown extension
+func (r *Reader) SkipElement() error {
+	depth := 0
+	for {
+		token, err := r.readToken()
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// readText reads text up to the next start or end tag (not read)
+func (r *Reader) readText() (string, error) {
+	var text strings.Builder
+	for {
+		token, err := r.readToken()
+		if err != nil {
+			return "", err
+		}
+		data, isText := token.(xml.CharData)
+		if !isText {
+			r.peeked = token
+			return text.String(), nil
+		}
+		text.Write(data)
+	}
+}
+
+// readTextContent reads the text content of the innermost element, up to its end tag (not read)
+func (r *Reader) readTextContent() (string, error) {
+	text, err := r.readText()
+	if err != nil {
+		return "", err
+	}
+	if _, isEnd := r.peeked.(xml.EndElement); !isEnd {
+		return "", errors.New("unexpected element in text content")
+	}
+	return text, nil
+}
+
+// readTrimmedText reads the text content of the innermost element, white space is only accepted by the basic reader
+func (r *Reader) readTrimmedText(removeAllSpaces bool) (string, error) {
+	text, err := r.readTextContent()
+	if err != nil {
+		return "", err
+	}
+	trimmed := strings.TrimSpace(text)
+	if removeAllSpaces {
+		trimmed = strings.Join(strings.Fields(text), "")
+	}
+	if r.canonical && trimmed != text {
+		return "", errors.New("white space in " + strconv.Quote(text) + " is not canonical")
+	}
+	return trimmed, nil
+}
+
+// readEmptyElement reads an empty child element and returns its name
+func (r *Reader) readEmptyElement() (string, error) {
+	name, err := r.BeginElement()
+	if err != nil {
+		return "", err
+	}
+	token, err := r.readToken()
+	if err != nil {
+		return "", err
+	}
+	if _, isEnd := token.(xml.EndElement); !isEnd {
+		return "", errors.New("element " + name + " must be empty")
+	}
+	return name, nil
+}
+
+// ReadBoolean decodes a BOOLEAN from the empty element <true/> or <false/>
+func (r *Reader) ReadBoolean() (bool, error) {
+	name, err := r.readEmptyElement()
+	if err != nil {
+		return false, err
+	}
+	switch name {
+	case trueElement:
+		return true, nil
+	case falseElement:
+		return false, nil
+	}
+	return false, errors.New("invalid BOOLEAN element " + name)
+}
+
+// ReadNull decodes NULL, raises an error if the element has content
+func (r *Reader) ReadNull() error {
+	text, err := r.readTextContent()
+	if err == nil && text != "" {
+		return errors.New("NULL element must be empty")
+	}
+	return err
+}
+
+// ReadInteger decodes an INTEGER, raises an error if it does not fit in an int64
+func (r *Reader) ReadInteger() (int64, error) {
+	value, err := r.ReadBigInteger()
+	if err != nil {
+		return 0, err
+	}
+	if !value.IsInt64() {
+		return 0, errors.New("INTEGER does not fit in 64 bits")
+	}
+	return value.Int64(), nil
+}
+
+// ReadBigInteger decodes an INTEGER
+func (r *Reader) ReadBigInteger() (*big.Int, error) {
+	text, err := r.readTrimmedText(false)
+	if err != nil {
+		return nil, err
+	}
+	return parseBigInteger(text, r.canonical)
+}
+
+// ReadEnumerated decodes an ENUMERATED value and returns its identifier
+func (r *Reader) ReadEnumerated() (string, error) {
+	return r.readEmptyElement()
+}
+
+// ReadReal decodes a REAL from text or from the empty element <PLUS-INFINITY/>, <MINUS-INFINITY/> or <NOT-A-NUMBER/>
+func (r *Reader) ReadReal() (float64, error) {
+	text, err := r.readText()
+	if err != nil {
+		return 0, err
+	}
+	if _, isStart := r.peeked.(xml.StartElement); !isStart {
+		return r.parseReal(text)
+	}
+	if r.canonical && text != "" || strings.TrimSpace(text) != "" {
+		return 0, errors.New("unexpected text " + strconv.Quote(text))
+	}
+
+	name, err := r.readEmptyElement()
+	if err != nil {
+		return 0, err
+	}
+	switch name {
+	case plusInfinityElement:
+		return math.Inf(1), nil
+	case minusInfinityElement:
+		return math.Inf(-1), nil
+	case notANumberElement:
+		return math.NaN(), nil
+	}
+	return 0, errors.New("invalid REAL element " + name)
+}
+
+// parseReal parses the text of a REAL value, the canonical reader only accepts the canonical form
+func (r *Reader) parseReal(text string) (float64, error) {
+	trimmed := strings.TrimSpace(text)
+	if r.canonical && trimmed != text {
+		return 0, errors.New("white space in " + strconv.Quote(text) + " is not canonical")
+	}
+	value, err := parseReal(trimmed)
+	if err != nil {
+		return 0, err
+	}
+	if r.canonical {
+		canonicalText := "0"
+		if math.Signbit(value) && value == 0 {
+			canonicalText = "-0"
+		} else if value != 0 {
+			canonicalText = formatCanonicalReal(value)
+		}
+		if trimmed != canonicalText {
+			return 0, errors.New("non-canonical REAL " + strconv.Quote(text))
+		}
+	}
+	return value, nil
+}
+
+// ReadOctetString decodes an OCTET STRING from hexadecimal digits, the canonical reader only accepts upper case digits
+func (r *Reader) ReadOctetString() ([]byte, error) {
+	text, err := r.readTrimmedText(true)
+	if err != nil {
+		return nil, err
+	}
+	if r.canonical && strings.ToUpper(text) != text {
+		return nil, errors.New("lower case hexadecimal digits are not canonical")
+	}
+	return hex.DecodeString(text)
+}
+
+// ReadBitString decodes a BIT STRING from a string of 0 and 1
+func (r *Reader) ReadBitString() (types.BitString, error) {
+	text, err := r.readTrimmedText(true)
+	if err != nil {
+		return types.BitString{}, err
+	}
+	value := types.BitString{Bytes: make([]byte, (len(text)+7)/8), Length: len(text)}
+	for i, c := range text {
+		switch c {
+		case '1':
+			value.Bytes[i/8] |= 0x80 >> uint(i%8)
+		case '0':
+		default:
+			return types.BitString{}, errors.New("invalid BIT STRING " + strconv.Quote(text))
+		}
+	}
+	return value, nil
+}
+
+// ReadRestrictedCharacterString decodes a character string, white space is preserved
+func (r *Reader) ReadRestrictedCharacterString() (string, error) {
+	return r.readTextContent()
+}
+
+// ReadObjectIdentifier decodes an OBJECT IDENTIFIER in dotted notation
+func (r *Reader) ReadObjectIdentifier() (types.ObjectIdentifier, error) {
+	text, err := r.readTrimmedText(false)
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseObjectIdentifier(text)
+}
+
+// ReadRelativeOID decodes a RELATIVE-OID in dotted notation
+func (r *Reader) ReadRelativeOID() (types.RelativeOID, error) {
+	text, err := r.readTrimmedText(false)
+	if err != nil {
+		return nil, err
+	}
+	return types.ParseRelativeOID(text)
+}
+
+// ReadUTCTime decodes a UTCTime, raises an error if the text is not a valid UTCTime (or is not DER-like in canonical XER)
+func (r *Reader) ReadUTCTime() (types.UTCTime, error) {
+	text, err := r.readTrimmedText(false)
+	if err != nil {
+		return "", err
+	}
+	value := types.UTCTime(text)
+	_, err = value.Time()
+	if err == nil && r.canonical && !value.IsDER() {
+		err = errors.New("non-canonical UTCTime " + strconv.Quote(text))
+	}
+	return value, err
+}
+
+// ReadGeneralizedTime decodes a GeneralizedTime, raises an error if the text is not a valid GeneralizedTime (or is not DER-like in canonical XER)
+func (r *Reader) ReadGeneralizedTime() (types.GeneralizedTime, error) {
+	text, err := r.readTrimmedText(false)
+	if err != nil {
+		return "", err
+	}
+	value := types.GeneralizedTime(text)
+	_, err = value.Time()
+	if err == nil && r.canonical && !value.IsDER() {
+		err = errors.New("non-canonical GeneralizedTime " + strconv.Quote(text))
+	}
+	return value, err
+}
+
+// ReadTime decodes a TIME, DATE, TIME-OF-DAY, DATE-TIME or DURATION with the given properties (types.DateProperties...)
+// raises an error if the text does not match the properties
+func (r *Reader) ReadTime(properties types.TimeProperties) (string, error) {
+	text, err := r.readTrimmedText(false)
+	if err != nil {
+		return "", err
+	}
+	return text, types.Time(text).Validate(properties)
+}
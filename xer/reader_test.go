@@ -0,0 +1,272 @@
+package xer
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadSequence(t *testing.T) {
+	text := `<?xml version="1.0"?>
+<Record>
+  <!-- comment -->
+  <version> 2 </version>
+  <unknown><a>1</a><b/></unknown>
+  <oid>1.2.840.113549</oid>
+  <flags>10 10</flags>
+  <data>01 ab</data>
+  <list>
+    <BOOLEAN><true/></BOOLEAN>
+    <NULL></NULL>
+    <Name><nickname>a &amp; &lt;b&gt;</nickname></Name>
+  </list>
+</Record>`
+	reader := NewReader(strings.NewReader(text))
+
+	name, err := reader.BeginElement()
+	if err != nil || name != "Record" {
+		t.Fatal("Wrong:", err)
+	}
+	names := []string{}
+	for {
+		name, isElement, err := reader.NextElement()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+		if !isElement {
+			break
+		}
+		names = append(names, name)
+		switch name {
+		case "version":
+			version, err := reader.ReadInteger()
+			if err != nil || version != 2 {
+				t.Fatal("Wrong")
+			}
+		case "oid":
+			oid, err := reader.ReadObjectIdentifier()
+			if err != nil || len(oid) != 4 || oid[3] != 113549 {
+				t.Fatal("Wrong")
+			}
+		case "flags":
+			flags, err := reader.ReadBitString()
+			if err != nil || flags.Length != 4 || false == bytes.Equal(flags.Bytes, []byte{0xa0}) {
+				t.Fatal("Wrong")
+			}
+		case "data":
+			data, err := reader.ReadOctetString()
+			if err != nil || false == bytes.Equal(data, []byte{0x01, 0xab}) {
+				t.Fatal("Wrong")
+			}
+		case "list":
+			readList(t, reader)
+			continue
+		default:
+			err = reader.SkipElement()
+			if err != nil {
+				t.Fatal("Wrong:", err)
+			}
+			continue
+		}
+		err = reader.EndElement()
+		if err != nil {
+			t.Fatal("Wrong:", err)
+		}
+	}
+	if strings.Join(names, ",") != "version,unknown,oid,flags,data,list" {
+		t.Fatal("Wrong:", names)
+	}
+}
+
+func readList(t *testing.T, reader *Reader) {
+	name, _, err := reader.NextElement()
+	if err != nil || name != "BOOLEAN" {
+		t.Fatal("Wrong:", err)
+	}
+	value, err := reader.ReadBoolean()
+	if err != nil || value != true || reader.EndElement() != nil {
+		t.Fatal("Wrong:", err)
+	}
+	name, _, err = reader.NextElement()
+	if err != nil || name != "NULL" {
+		t.Fatal("Wrong:", err)
+	}
+	if reader.ReadNull() != nil || reader.EndElement() != nil {
+		t.Fatal("Wrong")
+	}
+	name, _, err = reader.NextElement()
+	if err != nil || name != "Name" {
+		t.Fatal("Wrong:", err)
+	}
+	alternative, err := reader.BeginElement()
+	if err != nil || alternative != "nickname" {
+		t.Fatal("Wrong:", err)
+	}
+	nickname, err := reader.ReadRestrictedCharacterString()
+	if err != nil || nickname != "a & <b>" || reader.EndElement() != nil || reader.EndElement() != nil {
+		t.Fatal("Wrong:", nickname)
+	}
+	_, isElement, err := reader.NextElement()
+	if err != nil || isElement {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadRoundTrip(t *testing.T) {
+	writer := NewCanonicalWriter()
+	writeSequence(writer)
+
+	reader := NewCanonicalReader(bytes.NewReader(writer.GetDataBuffer()))
+	reader.BeginElement()
+	reader.NextElement()
+	version, err := reader.ReadInteger()
+	if err != nil || version != 2 || reader.EndElement() != nil {
+		t.Fatal("Wrong")
+	}
+	reader.NextElement()
+	oid, err := reader.ReadObjectIdentifier()
+	if err != nil || oid.String() != "1.2.840.113549" || reader.EndElement() != nil {
+		t.Fatal("Wrong")
+	}
+	reader.NextElement()
+	flags, err := reader.ReadBitString()
+	if err != nil || flags.Length != 4 || flags.Bytes[0] != 0xa0 || reader.EndElement() != nil {
+		t.Fatal("Wrong")
+	}
+	reader.NextElement()
+	readList(t, reader)
+	reader.NextElement()
+	if reader.ReadNull() != nil || reader.EndElement() != nil {
+		t.Fatal("Wrong")
+	}
+	_, isElement, err := reader.NextElement()
+	if err != nil || isElement {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadReal(t *testing.T) {
+	texts := []string{"<r>1.5E2</r>", "<r> 15e1 </r>", "<r>-0</r>", "<r>0.0</r>", "<r><PLUS-INFINITY/></r>", "<r> <MINUS-INFINITY/> </r>"}
+	expected := []float64{150, 150, math.Copysign(0, -1), 0, math.Inf(1), math.Inf(-1)}
+	for i, text := range texts {
+		reader := NewReader(strings.NewReader(text))
+		reader.BeginElement()
+		value, err := reader.ReadReal()
+		if err != nil || value != expected[i] || math.Signbit(value) != math.Signbit(expected[i]) || reader.EndElement() != nil {
+			t.Fatal("Wrong:", text, err)
+		}
+	}
+
+	reader := NewReader(strings.NewReader("<r><NOT-A-NUMBER/></r>"))
+	reader.BeginElement()
+	value, err := reader.ReadReal()
+	if err != nil || !math.IsNaN(value) {
+		t.Fatal("Wrong")
+	}
+
+	for _, text := range []string{"<r>INF</r>", "<r>0x1p-2</r>", "<r><INFINITY/></r>"} {
+		reader := NewReader(strings.NewReader(text))
+		reader.BeginElement()
+		_, err := reader.ReadReal()
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+}
+
+func TestReadCanonical(t *testing.T) {
+	texts := []string{"<r>1.5E2</r>", "<r>-2.5E-3</r>", "<r>0</r>", "<r>-0</r>"}
+	for _, text := range texts {
+		reader := NewCanonicalReader(strings.NewReader(text))
+		reader.BeginElement()
+		_, err := reader.ReadReal()
+		if err != nil {
+			t.Fatal("Wrong:", text, err)
+		}
+	}
+
+	texts = []string{"<r>15E1</r>", "<r>1.50E2</r>", "<r>1.5e2</r>", "<r>1.5E+2</r>", "<r>0.0</r>", "<r> 1E0</r>"}
+	for _, text := range texts {
+		reader := NewCanonicalReader(strings.NewReader(text))
+		reader.BeginElement()
+		_, err := reader.ReadReal()
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+
+	for _, text := range []string{"<i>+5</i>", "<i>05</i>", "<i>-0</i>", "<i> 5</i>"} {
+		reader := NewCanonicalReader(strings.NewReader(text))
+		reader.BeginElement()
+		_, err := reader.ReadInteger()
+		if err == nil {
+			t.Fatal("Wrong:", text)
+		}
+	}
+
+	reader := NewCanonicalReader(strings.NewReader("<o>01ab</o>"))
+	reader.BeginElement()
+	_, err := reader.ReadOctetString()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewCanonicalReader(strings.NewReader("<a> <b>1</b></a>"))
+	reader.BeginElement()
+	_, _, err = reader.NextElement()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewCanonicalReader(strings.NewReader("<a><!-- c --><b>1</b></a>"))
+	reader.BeginElement()
+	_, _, err = reader.NextElement()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewCanonicalReader(strings.NewReader("<t>19910506234540.50Z</t>"))
+	reader.BeginElement()
+	_, err = reader.ReadGeneralizedTime()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	reader := NewReader(strings.NewReader("<b><maybe/></b>"))
+	reader.BeginElement()
+	_, err := reader.ReadBoolean()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader("<n>x</n>"))
+	reader.BeginElement()
+	err = reader.ReadNull()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader("<o>1.40</o>"))
+	reader.BeginElement()
+	_, err = reader.ReadObjectIdentifier()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader("<s>102</s>"))
+	reader.BeginElement()
+	_, err = reader.ReadBitString()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+
+	reader = NewReader(strings.NewReader("<i>12"))
+	reader.BeginElement()
+	_, err = reader.ReadInteger()
+	if err == nil {
+		t.Fatal("Wrong")
+	}
+}
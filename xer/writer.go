@@ -0,0 +1,223 @@
+package xer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+// Writer helps encode ASN.1 values in XER, elements are written forward
+// the basic writer indents nested elements, the canonical writer writes no white space between elements (CXER)
+type Writer struct {
+	// encoded data
+	dataBuffer bytes.Buffer
+
+	// CANONICAL-XER
+	canonical bool
+
+	// elements being written (innermost last)
+	elements []element
+
+	// true if the start tag of the innermost element has not been written yet
+	pendingStartTag bool
+}
+
+// element is an element being written
+type element struct {
+	name             string
+	hasChildElements bool
+}
+
+// NewWriter creates a writer for basic XER
+func NewWriter() *Writer {
+	w := new(Writer)
+	return w
+}
+
+// NewCanonicalWriter creates a writer for canonical XER
+func NewCanonicalWriter() *Writer {
+	w := new(Writer)
+	w.canonical = true
+	return w
+}
+
+// IsCanonical returns true if the writer encodes in canonical XER
+func (w *Writer) IsCanonical() bool {
+	return w.canonical
+}
+
+// GetDataBuffer returns the encoded data
+func (w *Writer) GetDataBuffer() []byte {
+	return w.dataBuffer.Bytes()
+}
+
+// flushStartTag writes the start tag of the innermost element if it is pending
+func (w *Writer) flushStartTag() {
+	if w.pendingStartTag {
+		w.dataBuffer.WriteString("<" + w.elements[len(w.elements)-1].name + ">")
+		w.pendingStartTag = false
+	}
+}
+
+// writeIndentation writes a new line and the indentation of the current depth, in basic XER only
+func (w *Writer) writeIndentation() {
+	if !w.canonical {
+		w.dataBuffer.WriteString("\n" + strings.Repeat("  ", len(w.elements)))
+	}
+}
+
+// BeginElement begins an element, its content is the value written next (nothing for NULL)
+// SEQUENCE, SET and SEQUENCE OF values contain an element per component, CHOICE values contain the element of the chosen alternative
+func (w *Writer) BeginElement(name string) {
+	w.flushStartTag()
+	if len(w.elements) > 0 {
+		w.elements[len(w.elements)-1].hasChildElements = true
+		w.writeIndentation()
+	}
+	w.elements = append(w.elements, element{name: name})
+	w.pendingStartTag = true
+}
+
+// EndElement ends the innermost element, an element without content is written as an empty-element tag
+func (w *Writer) EndElement() {
+	current := w.elements[len(w.elements)-1]
+	if w.pendingStartTag {
+		w.dataBuffer.WriteString("<" + current.name + "/>")
+		w.pendingStartTag = false
+		w.elements = w.elements[:len(w.elements)-1]
+		return
+	}
+	w.elements = w.elements[:len(w.elements)-1]
+	if current.hasChildElements {
+		w.writeIndentation()
+	}
+	w.dataBuffer.WriteString("</" + current.name + ">")
+}
+
+// writeEmptyElement writes an empty child element
+func (w *Writer) writeEmptyElement(name string) {
+	w.flushStartTag()
+	w.dataBuffer.WriteString("<" + name + "/>")
+}
+
+// writeText writes the escaped text content of the innermost element
+func (w *Writer) writeText(text string) {
+	w.flushStartTag()
+	xml.EscapeText(&w.dataBuffer, []byte(text))
+}
+
+// WriteBoolean encodes a BOOLEAN as the empty element <true/> or <false/>
+func (w *Writer) WriteBoolean(value bool) {
+	if value {
+		w.writeEmptyElement(trueElement)
+	} else {
+		w.writeEmptyElement(falseElement)
+	}
+}
+
+// WriteNull encodes NULL, the element has no content
+func (w *Writer) WriteNull() {
+}
+
+// WriteInteger encodes an INTEGER as decimal digits
+func (w *Writer) WriteInteger(value int64) {
+	w.writeText(strconv.FormatInt(value, 10))
+}
+
+// WriteBigInteger encodes an INTEGER as decimal digits
+func (w *Writer) WriteBigInteger(value *big.Int) {
+	w.writeText(value.String())
+}
+
+// WriteEnumerated encodes an ENUMERATED value as an empty element named by its identifier
+func (w *Writer) WriteEnumerated(identifier string) {
+	w.writeEmptyElement(identifier)
+}
+
+// WriteReal encodes a REAL as text, or as the empty element <PLUS-INFINITY/>, <MINUS-INFINITY/> or <NOT-A-NUMBER/> for the special values
+// finite non-zero values are written with a mantissa with a single digit before the decimal point (e.g. 1.5E2), which is canonical
+func (w *Writer) WriteReal(value float64) {
+	switch {
+	case math.IsInf(value, 1):
+		w.writeEmptyElement(plusInfinityElement)
+	case math.IsInf(value, -1):
+		w.writeEmptyElement(minusInfinityElement)
+	case math.IsNaN(value):
+		w.writeEmptyElement(notANumberElement)
+	case value == 0 && math.Signbit(value):
+		w.writeText("-0")
+	case value == 0:
+		w.writeText("0")
+	default:
+		w.writeText(formatCanonicalReal(value))
+	}
+}
+
+// WriteOctetString encodes an OCTET STRING as hexadecimal digits
+func (w *Writer) WriteOctetString(value []byte) {
+	w.writeText(strings.ToUpper(hex.EncodeToString(value)))
+}
+
+// WriteBitString encodes a BIT STRING as a string of 0 and 1
+func (w *Writer) WriteBitString(value types.BitString) {
+	length := value.ValidLength()
+	text := make([]byte, length)
+	for i := range text {
+		if value.Get(i) {
+			text[i] = '1'
+		} else {
+			text[i] = '0'
+		}
+	}
+	w.writeText(string(text))
+}
+
+// WriteRestrictedCharacterString encodes a character string as text
+func (w *Writer) WriteRestrictedCharacterString(value string) {
+	w.writeText(value)
+}
+
+// WriteObjectIdentifier encodes an OBJECT IDENTIFIER in dotted notation
+func (w *Writer) WriteObjectIdentifier(value types.ObjectIdentifier) {
+	w.writeText(value.String())
+}
+
+// WriteRelativeOID encodes a RELATIVE-OID in dotted notation
+func (w *Writer) WriteRelativeOID(value types.RelativeOID) {
+	w.writeText(value.String())
+}
+
+// WriteUTCTime encodes a UTCTime as text
+// with canonical XER, the value is converted to the form YYMMDDHHMMSSZ (an invalid value is written unchanged)
+func (w *Writer) WriteUTCTime(value types.UTCTime) {
+	if w.canonical {
+		t, err := value.Time()
+		if err == nil {
+			value = types.NewUTCTime(t)
+		}
+	}
+	w.writeText(string(value))
+}
+
+// WriteGeneralizedTime encodes a GeneralizedTime as text
+// with canonical XER, the value is converted to the form YYYYMMDDHHMMSS[.f]Z (an invalid value is written unchanged)
+func (w *Writer) WriteGeneralizedTime(value types.GeneralizedTime) {
+	if w.canonical {
+		t, err := value.Time()
+		if err == nil {
+			value = types.NewGeneralizedTime(t)
+		}
+	}
+	w.writeText(string(value))
+}
+
+// WriteTime encodes a TIME, DATE, TIME-OF-DAY, DATE-TIME or DURATION as text
+func (w *Writer) WriteTime(value string) {
+	w.writeText(value)
+}
@@ -0,0 +1,142 @@
+package xer
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/yafred/asn1-go/types"
+)
+
+func writeSequence(writer *Writer) {
+	writer.BeginElement("Record")
+	writer.BeginElement("version")
+	writer.WriteInteger(2)
+	writer.EndElement()
+	writer.BeginElement("oid")
+	writer.WriteObjectIdentifier(types.ObjectIdentifier{1, 2, 840, 113549})
+	writer.EndElement()
+	writer.BeginElement("flags")
+	writer.WriteBitString(types.BitString{Bytes: []byte{0xaf}, Length: 4})
+	writer.EndElement()
+	writer.BeginElement("list")
+	writer.BeginElement("BOOLEAN")
+	writer.WriteBoolean(true)
+	writer.EndElement()
+	writer.BeginElement("NULL")
+	writer.WriteNull()
+	writer.EndElement()
+	writer.BeginElement("Name")
+	writer.BeginElement("nickname")
+	writer.WriteRestrictedCharacterString("a & <b>")
+	writer.EndElement()
+	writer.EndElement()
+	writer.EndElement()
+	writer.BeginElement("empty")
+	writer.EndElement()
+	writer.EndElement()
+}
+
+func TestWriteSequence(t *testing.T) {
+	writer := NewWriter()
+	writeSequence(writer)
+
+	expected := `<Record>
+  <version>2</version>
+  <oid>1.2.840.113549</oid>
+  <flags>1010</flags>
+  <list>
+    <BOOLEAN><true/></BOOLEAN>
+    <NULL/>
+    <Name>
+      <nickname>a &amp; &lt;b&gt;</nickname>
+    </Name>
+  </list>
+  <empty/>
+</Record>`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWriteCanonicalSequence(t *testing.T) {
+	writer := NewCanonicalWriter()
+	writeSequence(writer)
+
+	expected := `<Record><version>2</version><oid>1.2.840.113549</oid><flags>1010</flags><list><BOOLEAN><true/></BOOLEAN><NULL/><Name><nickname>a &amp; &lt;b&gt;</nickname></Name></list><empty/></Record>`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWritePrimitives(t *testing.T) {
+	writer := NewCanonicalWriter()
+
+	writer.BeginElement("list")
+	for _, write := range []func(){
+		func() { writer.WriteOctetString([]byte{0x01, 0xab}) },
+		func() { writer.WriteRelativeOID(types.RelativeOID{8571, 3}) },
+		func() { writer.WriteEnumerated("red") },
+		func() { writer.WriteBigInteger(new(big.Int).Lsh(big.NewInt(1), 70)) },
+		func() { writer.WriteInteger(-5) },
+		func() { writer.WriteBoolean(false) },
+		func() { writer.WriteUTCTime(types.UTCTime("910506234540Z")) },
+		func() { writer.WriteGeneralizedTime(types.GeneralizedTime("19910506234540.5Z")) },
+		func() { writer.WriteTime("2020-01-31") },
+	} {
+		writer.BeginElement("a")
+		write()
+		writer.EndElement()
+	}
+	writer.EndElement()
+
+	expected := `<list><a>01AB</a><a>8571.3</a><a><red/></a><a>1180591620717411303424</a><a>-5</a><a><false/></a>` +
+		`<a>910506234540Z</a><a>19910506234540.5Z</a><a>2020-01-31</a></list>`
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+}
+
+func TestWriteCanonicalTimes(t *testing.T) {
+	writer := NewCanonicalWriter()
+	writer.BeginElement("utc")
+	writer.WriteUTCTime(types.UTCTime("9901011200+0100"))
+	writer.EndElement()
+	writer.BeginElement("generalized")
+	writer.WriteGeneralizedTime(types.GeneralizedTime("19990101120000.50+0100"))
+	writer.EndElement()
+
+	expected := "<utc>990101110000Z</utc><generalized>19990101110000.5Z</generalized>"
+	if string(writer.GetDataBuffer()) != expected {
+		t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+	}
+
+	reader := NewCanonicalReader(bytes.NewReader(writer.GetDataBuffer()))
+	reader.BeginElement()
+	utcTime, err := reader.ReadUTCTime()
+	if err != nil || utcTime != "990101110000Z" {
+		t.Fatal("Wrong:", err)
+	}
+	reader.EndElement()
+	reader.BeginElement()
+	generalizedTime, err := reader.ReadGeneralizedTime()
+	if err != nil || generalizedTime != "19990101110000.5Z" {
+		t.Fatal("Wrong:", err)
+	}
+}
+
+func TestWriteReal(t *testing.T) {
+	values := []float64{150, 1, -0.0025, 1e300, 0, math.Copysign(0, -1), math.Inf(1), math.Inf(-1), math.NaN()}
+	expected := []string{"<r>1.5E2</r>", "<r>1E0</r>", "<r>-2.5E-3</r>", "<r>1E300</r>", "<r>0</r>", "<r>-0</r>",
+		"<r><PLUS-INFINITY/></r>", "<r><MINUS-INFINITY/></r>", "<r><NOT-A-NUMBER/></r>"}
+	for i, value := range values {
+		writer := NewCanonicalWriter()
+		writer.BeginElement("r")
+		writer.WriteReal(value)
+		writer.EndElement()
+		if string(writer.GetDataBuffer()) != expected[i] {
+			t.Fatal("Wrong:", string(writer.GetDataBuffer()))
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package xer implements the basic and canonical XML Encoding Rules (X.693)
+// values are encoded as elements named by the caller (component identifiers, alternative identifiers or type names)
+// the encoding instructions of EXTENDED-XER are not supported
+package xer
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// names of the empty elements encoding BOOLEAN and special REAL values
+const (
+	trueElement          = "true"
+	falseElement         = "false"
+	plusInfinityElement  = "PLUS-INFINITY"
+	minusInfinityElement = "MINUS-INFINITY"
+	notANumberElement    = "NOT-A-NUMBER"
+)
+
+// formatCanonicalReal returns the canonical text of a finite non-zero REAL value:
+// a mantissa with a single non-zero digit before the decimal point and no trailing zero, and an exponent without leading zero (e.g. 1.5E2)
+func formatCanonicalReal(value float64) string {
+	text := strconv.FormatFloat(value, 'E', -1, 64)
+	mantissa, exponent := text[:strings.IndexByte(text, 'E')], text[strings.IndexByte(text, 'E')+1:]
+	exponent = strings.TrimPrefix(exponent, "+")
+	if exponent[0] == '-' {
+		exponent = "-" + strings.TrimLeft(exponent[1:], "0")
+	} else {
+		exponent = strings.TrimLeft(exponent, "0")
+	}
+	if exponent == "" || exponent == "-" {
+		exponent = "0"
+	}
+	return mantissa + "E" + exponent
+}
+
+// parseReal parses the text of a REAL value
+func parseReal(text string) (float64, error) {
+	if text == "-0" {
+		return math.Copysign(0, -1), nil
+	}
+	if strings.ContainsAny(text, "xXpP_") {
+		return 0, errors.New("invalid REAL " + strconv.Quote(text))
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, errors.New("invalid REAL " + strconv.Quote(text))
+	}
+	return value, nil
+}
+
+// parseBigInteger parses the text of an INTEGER value, canonical values have no leading zero nor plus sign and zero is not negative
+func parseBigInteger(text string, canonical bool) (*big.Int, error) {
+	digits := text
+	if strings.HasPrefix(digits, "-") || !canonical && strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return nil, errors.New("invalid INTEGER " + strconv.Quote(text))
+	}
+	value, _ := new(big.Int).SetString(strings.TrimPrefix(text, "+"), 10)
+	if canonical && (len(digits) > 1 && digits[0] == '0' || text == "-0") {
+		return nil, errors.New("non-canonical INTEGER " + strconv.Quote(text))
+	}
+	return value, nil
+}